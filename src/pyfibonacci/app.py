@@ -7,25 +7,121 @@ composants (comme le pool de processus), et l'orchestration de l'exécution
 des algorithmes de calcul de la suite de Fibonacci.
 """
 
+import argparse
 import asyncio
+import contextlib
+import dataclasses
+import json
+import math
+import os
+import statistics
 import sys
-from typing import Callable, Coroutine, Any, Awaitable, Dict
+import time
+from decimal import Decimal
+from typing import (
+    Callable, Coroutine, Any, Awaitable, Dict, Iterable, List, Optional, Sequence, TextIO
+)
 from concurrent.futures import ProcessPoolExecutor
 
-from .cli.args import parse_args
-from .cli.progress import progress_bar_manager
-from .core.algorithms import fib_iterative, fib_matrix, fib_fast_doubling
-from .core.context import CalculationContext
-from .calibrate import run_calibration
-
-# Le registre des algorithmes disponibles.
-# Il mappe les noms de la CLI aux fonctions (asynchrones ou synchrones).
-ALGORITHM_REGISTRY: Dict[str, Callable[..., Awaitable[int] | int]] = {
-    "iterative": fib_iterative,
-    "matrix": fib_matrix,
-    "fast": fib_fast_doubling,
+from .cli.args import (
+    EXPECT_MISMATCH_EXIT_CODE,
+    MEMORY_EXIT_CODE,
+    SUM_CHECK_MAX_N,
+    WATCHDOG_EXIT_CODE,
+    apply_json_request,
+    parse_args,
+)
+from .cli.color import color_enabled, paint
+from .cli.memory import describe_memory, start_memory_tracking, stop_memory_tracking
+from .cli.profile import DEFAULT_PROFILE_PATHS, start_profiling
+from .cli.output import (
+    CalculationResult,
+    append_csv,
+    count_digits,
+    DisplayOptions,
+    describe_durations,
+    describe_progress,
+    digit_range,
+    digit_sum,
+    encode_json_result,
+    first_mismatch,
+    format_scientific,
+    format_value,
+    group_digits,
+    hash_value,
+    iter_decimal_chunks,
+    read_binary,
+    write_binary,
+    write_value,
+)
+from .cli.progress import final_status, multi_progress_manager, progress_bar_manager
+from .core.algorithms import (
+    fib_fast_doubling_mod,
+    fib_fast_doubling_pair,
+    fib_last_digits,
+    fib_sequence,
+    fib_sum_iterative,
+    apply_negafibonacci_sign,
+    apply_negalucas_sign,
+    divisibility_facts,
+    predict_bit_length,
+    predict_digits,
+    verify_cassini,
+)
+from .core.binet import (
+    DEFAULT_VERIFIED_DIGITS, fibonacci_ratio, golden_ratio, leading_digits, verify_leading_digits
+)
+from .core.context import (
+    CalculationContext, CalculationProgress, ExecutorLoad, StepTrace, Tracer, track_progress
+)
+from .core.errors import CalculationError, ErrorCategory, is_caller_cancelled
+from .core.inverse import fibonacci_indices
+from .core.multiplication import resolve_mul_backend
+from .core.snapshot import ProgressSnapshot
+from .core.watchdog import Watchdog
+from . import metrics
+from .bench import (
+    benchmark_score,
+    describe_benchmark,
+    describe_machine,
+    describe_scaling,
+    run_benchmark,
+    run_scaling,
+    scaling_plateau,
+    scaling_speedups,
+    scaling_worker_counts,
+)
+from .calibrate import run_calibration, write_calibration_points
+from .config import save_config
+from .crossover import CROSSOVER_REPEAT, CROSSOVER_TIMEOUT, describe_crossover, run_crossover
+from .estimate import check_memory, describe_estimate, estimate_calculation
+from .reference import REFERENCE_HASH_ALGORITHM, default_reference_dir, load_reference, save_reference
+from .registry import ALGORITHM_REGISTRY, describe_algorithms, select_best_algorithm
+from .server import CachingExecutor, run_server
+
+# Algorithmes qui calculent une autre suite que celle de Fibonacci. Ils ne
+# participent pas au mode 'all' et appliquent leur propre règle de signe pour
+# les indices négatifs.
+NEGATIVE_INDEX_RULES: Dict[str, Callable[[int, int], int]] = {
+    "lucas": apply_negalucas_sign,
 }
 
+# Symbole de la suite calculée par chaque algorithme, pour l'affichage.
+SEQUENCE_SYMBOLS: Dict[str, str] = {"lucas": "L"}
+
+# Taille (en bits) au-delà de laquelle les valeurs en désaccord ne sont plus
+# affichées, seule la position de leur premier chiffre différent l'étant.
+_SHOWN_VALUE_BITS = 128
+
+# Module premier (2^61 - 1) du contrôle indépendant qui départage des
+# algorithmes en désaccord (voir `_report_divergence`).
+_DIVERGENCE_CHECK_MODULUS = 2**61 - 1
+
+# Indice du calcul de préchauffage (voir `_warm_up_algorithms`) : F(100 000)
+# compte près de 21 000 chiffres, assez pour solliciter la multiplication
+# parallèle avec le seuil par défaut, et s'obtient en une fraction de seconde.
+_WARMUP_INDEX = 100_000
+
 
 async def _run_cpu_bound_task(func: Callable[..., Any], *args: Any) -> Any:
     """Exécute une fonction bloquante (CPU-bound) dans un `ProcessPoolExecutor`.
@@ -46,9 +142,299 @@ async def _run_cpu_bound_task(func: Callable[..., Any], *args: Any) -> Any:
     return await loop.run_in_executor(None, func, *args)
 
 
-async def _run_single_algorithm(
+def _failed_result(
+    failure: CalculationError,
+    cause: BaseException,
+    duration: float,
+    progress: CalculationProgress,
+) -> CalculationResult:
+    """Construit le résultat d'un calcul échoué, l'erreur d'origine étant chaînée."""
+    failure.__cause__ = cause
+    return CalculationResult(
+        failure.algorithm,
+        None,
+        duration,
+        error=str(failure),
+        timed_out=failure.category is ErrorCategory.TIMEOUT,
+        progress=progress,
+        failure=failure,
+    )
+
+
+async def _warn_if_late(
+    progress: CalculationProgress, algo_name: str, timeout: float, warn_at: float
+) -> None:
+    """Signale un calcul qui dépassera probablement son timeout.
+
+    À la fraction `warn_at` du timeout, la durée totale du calcul est
+    extrapolée de l'avancement atteint (voir
+    `CalculationProgress.work_fraction`) ; si elle dépasse le timeout, un
+    avertissement est écrit sur la sortie d'erreur, pour que l'utilisateur
+    puisse relancer le calcul avec un délai plus long. Rien n'est signalé
+    pour un algorithme qui ne rapporte pas son avancement.
+
+    Args:
+        progress (CalculationProgress): L'avancement du calcul surveillé.
+        algo_name (str): Le nom de l'algorithme, pour le message.
+        timeout (float): Le timeout du calcul, en secondes.
+        warn_at (float): La fraction du timeout à laquelle l'avancement est
+            examiné.
+    """
+    await asyncio.sleep(timeout * warn_at)
+    if progress.total and progress.work_fraction < warn_at:
+        print(
+            f"AVERTISSEMENT ({algo_name}): à {warn_at:.0%} du timeout, le calcul n'est "
+            f"effectué qu'à ~{progress.work_fraction:.0%} ; le timeout sera probablement "
+            "atteint (voir --timeout).",
+            file=sys.stderr,
+        )
+
+
+def _watched(context: CalculationContext) -> contextlib.AbstractContextManager:
+    """Arme le chien de garde du contexte, s'il y en a un, pendant un calcul."""
+    if context.watchdog is None:
+        return contextlib.nullcontext()
+    return context.watchdog.watch()
+
+
+def _snapshotted(
+    context: CalculationContext, algo_name: str, progress: CalculationProgress
+) -> contextlib.AbstractContextManager:
+    """Inscrit le calcul à l'instantané de progression du contexte, s'il y en a un."""
+    if context.snapshot is None:
+        return contextlib.nullcontext()
+    return context.snapshot.track(algo_name, progress)
+
+
+def _trace_writer(stream: TextIO) -> Tracer:
+    """Crée un traceur qui écrit chaque étape sur un flux, en JSON, une par ligne.
+
+    Args:
+        stream (TextIO): Le flux de destination du journal.
+
+    Returns:
+        Tracer: Le traceur, à placer dans le `CalculationContext`.
+    """
+
+    def write(event: StepTrace) -> None:
+        stream.write(json.dumps(dataclasses.asdict(event)) + "\n")
+
+    return write
+
+
+async def _execute_algorithm(
     context: CalculationContext, n: int, algo_name: str, timeout: float
+) -> CalculationResult:
+    """Exécute et chronomètre un algorithme, sans rien afficher.
+
+    L'algorithme, synchrone ou asynchrone, est exécuté sous un timeout. Les
+    indices négatifs sont pris en charge : l'algorithme calcule F(|n|) et le
+    signe est ensuite appliqué selon l'identité du "negafibonacci" (ou la
+    règle propre à la suite, voir `NEGATIVE_INDEX_RULES`). Les erreurs sont
+    capturées dans le résultat, sous la forme d'une `CalculationError`,
+    plutôt que propagées, à l'exception des interruptions
+    (`KeyboardInterrupt`) et de l'annulation de l'appelant. L'issue et la
+    durée de chaque exécution sont enregistrées dans les métriques (voir
+    `metrics`).
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
+        algo_name (str): Le nom de l'algorithme (clé de `ALGORITHM_REGISTRY`).
+        timeout (float): Le temps maximum en secondes alloué pour l'exécution.
+
+    Returns:
+        CalculationResult: La valeur obtenue, ou l'erreur rencontrée, ainsi
+        que la durée d'exécution.
+    """
+    algo_func = ALGORITHM_REGISTRY[algo_name]
+    metrics.record_request(n)
+    start_time = time.perf_counter()
+    try:
+        with (
+            track_progress() as progress,
+            _watched(context),
+            _snapshotted(context, algo_name, progress),
+        ):
+            watcher = (
+                asyncio.create_task(_warn_if_late(progress, algo_name, timeout, context.warn_at))
+                if context.warn_at
+                else None
+            )
+            try:
+                async with asyncio.timeout(timeout):
+                    if asyncio.iscoroutinefunction(algo_func):
+                        value = await algo_func(context, abs(n))
+                    else:
+                        value = await _run_cpu_bound_task(algo_func, abs(n))
+            finally:
+                if watcher is not None:
+                    watcher.cancel()
+    except TimeoutError as e:
+        failure = CalculationError(algo_name, n, ErrorCategory.TIMEOUT, f"timeout ({timeout}s)")
+        result = _failed_result(failure, e, time.perf_counter() - start_time, progress)
+    except asyncio.CancelledError as e:
+        if is_caller_cancelled():
+            metrics.record_outcome(algo_name, ErrorCategory.CANCELED.value)
+            raise
+        failure = CalculationError(algo_name, n, ErrorCategory.CANCELED, "calcul annulé")
+        result = _failed_result(failure, e, time.perf_counter() - start_time, progress)
+    except MemoryError as e:
+        failure = CalculationError(algo_name, n, ErrorCategory.MEMORY, str(e) or "mémoire épuisée")
+        result = _failed_result(failure, e, time.perf_counter() - start_time, progress)
+    except Exception as e:
+        failure = CalculationError(algo_name, n, ErrorCategory.INTERNAL, str(e))
+        result = _failed_result(failure, e, time.perf_counter() - start_time, progress)
+    else:
+        sign_rule = NEGATIVE_INDEX_RULES.get(algo_name, apply_negafibonacci_sign)
+        result = CalculationResult(
+            algo_name, sign_rule(n, value), time.perf_counter() - start_time
+        )
+
+    metrics.record_result(result)
+    return result
+
+
+async def _execute_repeated(
+    context: CalculationContext, n: int, algo_name: str, timeout: float, repeat: int = 1
+) -> CalculationResult:
+    """Exécute un algorithme `repeat` fois et agrège ses durées.
+
+    La valeur est celle de la première exécution ; les suivantes ne servent
+    qu'à la mesure et ne rapportent ni leur progression, la barre couvrant
+    une seule exécution, ni leurs étapes au traceur. Le premier échec
+    interrompt la série et est retourné tel quel.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
+        algo_name (str): Le nom de l'algorithme (clé de `ALGORITHM_REGISTRY`).
+        timeout (float): Le timeout applicable à chaque exécution.
+        repeat (int): Le nombre d'exécutions.
+
+    Returns:
+        CalculationResult: Le résultat de la première exécution, dont la
+        durée est la médiane des exécutions et `durations` leur détail.
+    """
+    result = await _execute_algorithm(context, n, algo_name, timeout)
+    if repeat == 1 or not result.success:
+        return result
+    timing_context = dataclasses.replace(context, progress_queue=None, reporter=None, tracer=None)
+    durations = [result.duration]
+    for _ in range(repeat - 1):
+        run = await _execute_algorithm(timing_context, n, algo_name, timeout)
+        if not run.success:
+            return run
+        durations.append(run.duration)
+    return dataclasses.replace(
+        result, duration=statistics.median(durations), durations=durations
+    )
+
+
+async def _warm_up_executor(executor: ProcessPoolExecutor, workers: Optional[int] = None) -> None:
+    """Démarre les processus de travail avant une série de mesures.
+
+    Le `ProcessPoolExecutor` ne lance ses processus qu'à la première tâche
+    soumise : sans cette préparation, la première exécution d'une série
+    répétée paierait seule leur démarrage.
+
+    Args:
+        executor (ProcessPoolExecutor): L'exécuteur à préparer.
+        workers (Optional[int]): Le nombre de processus de l'exécuteur ;
+            par défaut, un par cœur.
+    """
+    loop = asyncio.get_running_loop()
+    await asyncio.gather(
+        *(loop.run_in_executor(executor, int) for _ in range(workers or os.cpu_count() or 1))
+    )
+
+
+async def _warm_up_algorithms(
+    context: CalculationContext, names: Sequence[str], timeout: float
 ) -> None:
+    """Exécute un petit calcul jetable par algorithme avant la mesure.
+
+    Le premier calcul paie le démarrage des processus de travail, le
+    chargement des modules et, le cas échéant, la construction de la table
+    de correspondance : sans préchauffage, l'algorithme exécuté en premier
+    en serait pénalisé. Les calculs de préchauffage ne sont ni affichés, ni
+    enregistrés dans les métriques, ni confrontés aux résultats ; leurs
+    échecs sont ignorés, la mesure qui suit les rapportant.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul de la mesure.
+        names (Sequence[str]): Les algorithmes à préchauffer, dans l'ordre.
+        timeout (float): Le timeout applicable à chaque calcul de préchauffage.
+    """
+    warm_context = dataclasses.replace(
+        context,
+        progress_queue=None,
+        reporter=None,
+        tracer=None,
+        watchdog=None,
+        snapshot=None,
+        warn_at=None,
+    )
+    for name in names:
+        algo_func = ALGORITHM_REGISTRY[name]
+        with contextlib.suppress(Exception):
+            async with asyncio.timeout(timeout):
+                if asyncio.iscoroutinefunction(algo_func):
+                    await algo_func(warm_context, _WARMUP_INDEX)
+                else:
+                    await _run_cpu_bound_task(algo_func, _WARMUP_INDEX)
+
+
+def _bar_colour(display: DisplayOptions) -> Optional[str]:
+    """Retourne la couleur des barres de progression, ou `None` sans coloration.
+
+    Les barres sont écrites sur la sortie d'erreur : c'est ce flux qui
+    détermine, en mode `auto`, si la coloration est active.
+    """
+    return "green" if color_enabled(display.color, sys.stderr) else None
+
+
+def _report_failure(
+    failure: CalculationError,
+    timeout: float,
+    progress: Optional[CalculationProgress],
+    display: DisplayOptions,
+) -> None:
+    """Signale l'échec d'un calcul sur la sortie d'erreur, selon sa catégorie.
+
+    Args:
+        failure (CalculationError): L'erreur structurée du calcul.
+        timeout (float): Le timeout qui était alloué au calcul.
+        progress (Optional[CalculationProgress]): L'avancement atteint.
+        display (DisplayOptions): Les options de présentation.
+    """
+    match failure.category:
+        case ErrorCategory.TIMEOUT:
+            message = (
+                f"ERREUR: L'algorithme '{failure.algorithm}' a dépassé le timeout de {timeout}s."
+            )
+        case ErrorCategory.CANCELED:
+            message = f"ERREUR: Le calcul avec l'algorithme '{failure.algorithm}' a été annulé."
+        case ErrorCategory.MEMORY:
+            message = (
+                f"ERREUR: Mémoire insuffisante pour l'algorithme '{failure.algorithm}' : {failure}."
+            )
+        case _:
+            message = f"ERREUR inattendue avec l'algorithme '{failure.algorithm}': {failure}"
+    print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+    reached = describe_progress(progress)
+    if failure.category is ErrorCategory.TIMEOUT and reached:
+        print(f"  Progression atteinte avant le timeout : {reached}.", file=sys.stderr)
+
+
+async def _run_single_algorithm(
+    context: CalculationContext,
+    n: int,
+    algo_name: str,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+    repeat: int = 1,
+) -> CalculationResult:
     """Exécute un algorithme de Fibonacci et gère son cycle de vie.
 
     Cette fonction prend en charge l'exécution d'un algorithme, qu'il soit
@@ -58,141 +444,1942 @@ async def _run_single_algorithm(
     Args:
         context (CalculationContext): Le contexte de calcul contenant le pool
             de processus et la queue pour la barre de progression.
-        n (int): L'indice de la suite de Fibonacci à calculer.
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
         algo_name (str): Le nom de l'algorithme à utiliser (clé de `ALGORITHM_REGISTRY`).
         timeout (float): Le temps maximum en secondes alloué pour l'exécution.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, rien n'est écrit sur la sortie standard afin de la
+            réserver au document ; les erreurs restent signalées sur la
+            sortie d'erreur.
+        repeat (int): Le nombre d'exécutions ; au-delà d'une, les durées
+            minimale, médiane et moyenne sont affichées.
+
+    Returns:
+        CalculationResult: Le résultat de l'exécution.
     """
-    algo_func = ALGORITHM_REGISTRY[algo_name]
-    print(f"Calcul de F({n}) en utilisant l'algorithme '{algo_name}'...")
+    display = display or DisplayOptions()
+    symbol = SEQUENCE_SYMBOLS.get(algo_name, "F")
+    if display.show_messages:
+        print(f"Calcul de {symbol}({n}) en utilisant l'algorithme '{algo_name}'...")
+
+    result = await _execute_repeated(context, n, algo_name, timeout, repeat)
+
+    if not result.success:
+        _report_failure(result.failure, timeout, result.progress, display)
+    elif display.quiet and not display.json:
+        if display.show_value:
+            write_value(sys.stdout, result.value, display.base)
+    elif not display.json:
+        if display.show_value:
+            display.write_result(sys.stdout, f"Résultat ({algo_name}): ", result.value)
+        else:
+            print(f"Résultat ({algo_name}): Calcul terminé.")
+        timing = describe_durations(result.durations)
+        if timing:
+            print(f"Durée : {timing}")
+    return result
+
+
+def _run_estimate(n: int, display: Optional[DisplayOptions] = None) -> None:
+    """Affiche le coût prévu du calcul de F(n), sans l'effectuer.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, l'estimation est émise sous forme d'objet `{"n",
+            "digits", "bit_length", "peak_memory_bytes", "duration_s"}`.
+    """
+    display = display or DisplayOptions()
+    estimate = estimate_calculation(n)
+    if display.json:
+        print(
+            json.dumps(
+                {
+                    "n": n,
+                    "digits": estimate.digits,
+                    "bit_length": estimate.bit_length,
+                    "peak_memory_bytes": estimate.peak_memory_bytes,
+                    "duration_s": estimate.duration,
+                }
+            )
+        )
+    else:
+        print(describe_estimate(estimate, display.thousands_sep))
+
+
+async def _run_benchmark(
+    context: CalculationContext,
+    algo_name: str,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+) -> None:
+    """Exécute le benchmark de débit et affiche son rapport.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        algo_name (str): Le nom de l'algorithme mesuré.
+        timeout (float): Le temps maximum en secondes alloué à chaque indice.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le rapport est émis sous forme d'objet `{"algorithm",
+            "machine", "results", "score"}`.
+    """
+    display = display or DisplayOptions()
+    if display.show_messages:
+        print(f"Benchmark en cours (timeout de {timeout}s par indice)...")
+
+    async def execute(n: int, name: str) -> CalculationResult:
+        return await _execute_algorithm(context, n, name, timeout)
+
+    results = await run_benchmark(execute, algo_name)
+    if display.json:
+        entries = [
+            {"n": r.n, "error": r.error}
+            if r.error is not None
+            else {
+                "n": r.n,
+                "digits": r.digits,
+                "bit_length": r.bit_length,
+                "duration_s": r.duration,
+                "digits_per_s": r.digits_per_second,
+                "bits_per_s": r.bits_per_second,
+            }
+            for r in results
+        ]
+        print(
+            json.dumps(
+                {
+                    "algorithm": algo_name,
+                    "machine": describe_machine(resolve_mul_backend(context.mul_backend)),
+                    "results": entries,
+                    "score": benchmark_score(results),
+                }
+            )
+        )
+    else:
+        print(
+            describe_benchmark(
+                algo_name, results, display.thousands_sep, resolve_mul_backend(context.mul_backend)
+            )
+        )
+
+
+def _run_scaled(n: int, k: int, display: Optional[DisplayOptions] = None) -> None:
+    """Affiche F(n) sous forme normalisée, mantisse de `k` chiffres et exposant.
+
+    Comme pour `_run_edges`, le nombre complet n'est jamais construit : la
+    mantisse et l'exposant proviennent de la formule de Binet
+    (`leading_digits`), si bien que le coût ne dépend que de `k`. La
+    mantisse est tronquée, et complétée par des zéros si F(n) compte moins
+    de `k` chiffres (sa valeur est alors exacte).
 
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
+        k (int): Le nombre de chiffres significatifs de la mantisse.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le résultat est émis sous forme d'objet
+            `{"n", "scaled", "digits", "mantissa", "exponent"}`, où F(n),
+            qui compte `digits` chiffres, vaut `mantissa` × 10^`exponent`.
+    """
+    display = display or DisplayOptions()
     try:
-        async with asyncio.timeout(timeout):
-            if asyncio.iscoroutinefunction(algo_func):
-                result = await algo_func(context, n)
-            else:
-                result = await _run_cpu_bound_task(algo_func, n)
+        head, length = leading_digits(n, k)
+    except ValueError as e:
+        print(f"ERREUR: {e}", file=sys.stderr)
+        sys.exit(1)
+    sign = "-" if apply_negafibonacci_sign(n, 1) < 0 and n != 0 else ""
+    head = head.ljust(k, "0")
+    mantissa = f"{sign}{head[0]}.{head[1:]}" if k > 1 else sign + head
+    exponent = length - 1
+    if display.json:
+        print(
+            json.dumps(
+                {"n": n, "scaled": k, "digits": length, "mantissa": mantissa, "exponent": exponent}
+            )
+        )
+    elif display.quiet:
+        print(f"{mantissa}e{exponent:+03d}")
+    else:
+        significant = "chiffre significatif" if k == 1 else "chiffres significatifs"
+        print(f"F({n}) ≈ {mantissa} × 10^{exponent} ({k} {significant})")
+
+
+async def _run_scaling(
+    n: int,
+    algo_name: str,
+    timeout: float,
+    threshold: int,
+    mul_algo: str,
+    max_workers: Optional[int] = None,
+    display: Optional[DisplayOptions] = None,
+    mul_backend: str = "auto",
+) -> None:
+    """Mesure le passage à l'échelle de la multiplication parallèle et l'affiche.
+
+    Chaque taille de pool (voir `scaling_worker_counts`) dispose de son
+    propre `ProcessPoolExecutor`, dont les processus sont démarrés avant la
+    mesure (voir `_warm_up_executor`).
+
+    Args:
+        n (int): L'indice calculé à chaque taille de pool.
+        algo_name (str): Le nom de l'algorithme mesuré.
+        timeout (float): Le temps maximum en secondes alloué à chaque calcul.
+        threshold (int): Le seuil de parallélisation (voir `--threshold`).
+        mul_algo (str): La stratégie de multiplication (voir `--mul-algo`).
+        max_workers (Optional[int]): La plus grande taille de pool ; par
+            défaut, le nombre de cœurs.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le rapport est émis sous forme d'objet `{"n",
+            "algorithm", "machine", "results", "plateau"}`.
+        mul_backend (str): Le moteur de multiplication (voir `--mul-backend`).
+    """
+    display = display or DisplayOptions()
+    counts = scaling_worker_counts(max_workers)
+    if display.show_messages:
+        sizes = ", ".join(map(str, counts))
+        print(f"Mesure du passage à l'échelle ({sizes} processus, timeout de {timeout}s par calcul)...")
+
+    async def execute(workers: int) -> CalculationResult:
+        with ProcessPoolExecutor(max_workers=workers) as executor:
+            await _warm_up_executor(executor, workers)
+            context = CalculationContext(
+                threshold=threshold, executor=executor, mul_algo=mul_algo, mul_backend=mul_backend
+            )
+            return await _execute_algorithm(context, n, algo_name, timeout)
+
+    results = await run_scaling(execute, counts)
+    if display.json:
+        speedups = iter(scaling_speedups(results))
+        entries = []
+        for r in results:
+            if r.error is not None:
+                entries.append({"workers": r.workers, "error": r.error})
+                continue
+            speedup = next(speedups)
+            entries.append(
+                {
+                    "workers": r.workers,
+                    "duration_s": r.duration,
+                    "speedup": speedup,
+                    "efficiency": speedup * results[0].workers / r.workers,
+                }
+            )
+        print(
+            json.dumps(
+                {
+                    "n": n,
+                    "algorithm": algo_name,
+                    "machine": describe_machine(resolve_mul_backend(mul_backend)),
+                    "results": entries,
+                    "plateau": scaling_plateau(results),
+                }
+            )
+        )
+    else:
+        print(
+            describe_scaling(
+                n, algo_name, results, display.thousands_sep, resolve_mul_backend(mul_backend)
+            )
+        )
+
+
+async def _run_crossover(
+    executor: ProcessPoolExecutor,
+    timeout: float,
+    threshold: int,
+    mul_algo: str,
+    sep: str = " ",
+    mul_backend: str = "auto",
+) -> None:
+    """Recherche les points de croisement des algorithmes et les affiche.
+
+    Les algorithmes comparés sont ceux du mode 'all' ; chaque mesure est la
+    durée médiane de `CROSSOVER_REPEAT` exécutions (voir `_execute_repeated`).
+
+    Args:
+        executor (ProcessPoolExecutor): Le pool de processus des calculs.
+        timeout (float): Le temps maximum en secondes alloué à chaque exécution.
+        threshold (int): Le seuil de parallélisation (voir `--threshold`).
+        mul_algo (str): La stratégie de multiplication (voir `--mul-algo`).
+        sep (str): Le séparateur des milliers du rapport.
+        mul_backend (str): Le moteur de multiplication (voir `--mul-backend`).
+    """
+    algorithms = [name for name in ALGORITHM_REGISTRY if name not in NEGATIVE_INDEX_RULES]
+    print(f"Recherche des points de croisement ({', '.join(algorithms)}, timeout de {timeout}s par calcul)...")
+    context = CalculationContext(
+        threshold=threshold, executor=executor, mul_algo=mul_algo, mul_backend=mul_backend
+    )
+
+    async def execute(n: int, algo_name: str) -> CalculationResult:
+        return await _execute_repeated(context, n, algo_name, timeout, CROSSOVER_REPEAT)
+
+    measures = await run_crossover(execute, algorithms)
+    print(describe_crossover(algorithms, measures, sep))
+
+
+def _run_modular(
+    n: int, moduli: Sequence[int], display: Optional[DisplayOptions] = None
+) -> None:
+    """Calcule et affiche F(n) modulo chacun des `moduli`.
+
+    Le calcul modulaire manipule de petits entiers et s'exécute en quelques
+    microsecondes, même pour des indices gigantesques ; il est donc effectué
+    directement, sans pool de processus ni timeout, une fois par module.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
+        moduli (Sequence[int]): Les modules de la réduction.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le résultat est émis sous forme d'objet
+            `{"n", "modulus", "base", "value"}` pour un seul module, et
+            `{"n", "base", "residues"}` pour plusieurs, `residues` associant
+            chaque module à son résidu.
+    """
+    display = display or DisplayOptions()
+    residues: Dict[int, str] = {}
+    try:
+        for modulus in moduli:
+            residue = fib_fast_doubling_mod(abs(n), modulus)
+            result = apply_negafibonacci_sign(n, residue) % modulus
+            residues[modulus] = format_value(result, display.base)
+    except ValueError as e:
+        print(f"ERREUR: {e}", file=sys.stderr)
+        sys.exit(1)
+    if display.json:
+        if len(residues) == 1:
+            [(modulus, value)] = residues.items()
+            document = {"n": n, "modulus": str(modulus), "base": display.base, "value": value}
+        else:
+            document = {
+                "n": n,
+                "base": display.base,
+                "residues": {str(modulus): value for modulus, value in residues.items()},
+            }
+        print(json.dumps(document))
+    elif display.quiet:
+        for value in residues.values():
+            print(value)
+    else:
+        width = max(len(str(modulus)) for modulus in residues)
+        for modulus, value in residues.items():
+            modulus_text = str(modulus) if len(residues) == 1 else f"{modulus:<{width}}"
+            print(f"F({n}) mod {modulus_text} = {value}")
+
+
+def _run_tail(n: int, k: int, display: Optional[DisplayOptions] = None) -> None:
+    """Calcule et affiche les `k` derniers chiffres décimaux de F(n).
+
+    Comme `_run_modular`, le calcul s'appuie sur le "Fast Doubling" modulaire
+    et s'exécute directement. Pour un indice négatif, le signe de F(n) est
+    indiqué devant les chiffres.
 
-            print(f"Résultat ({algo_name}): {result}")
-    except TimeoutError:
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
+        k (int): Le nombre de chiffres souhaités.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le résultat est émis sous forme d'objet
+            `{"n", "tail", "value"}`.
+    """
+    display = display or DisplayOptions()
+    try:
+        digits = fib_last_digits(abs(n), k)
+    except ValueError as e:
+        print(f"ERREUR: {e}", file=sys.stderr)
+        sys.exit(1)
+    if apply_negafibonacci_sign(n, 1) < 0 and digits.strip("0"):
+        digits = "-" + digits
+    if display.json:
+        print(json.dumps({"n": n, "tail": k, "value": digits}))
+    elif display.quiet:
+        print(digits)
+    else:
+        print(f"Derniers chiffres de F({n}) ({k}) : {digits}")
+
+
+async def _run_digit_range(
+    context: CalculationContext,
+    n: int,
+    start: int,
+    end: int,
+    algo_name: str,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+) -> bool:
+    """Calcule F(n) et en affiche les chiffres décimaux de rang `start` à `end`.
+
+    Un intervalle qui dépasse la taille prévue de F(n) (`predict_digits`,
+    exacte à une unité près) est refusé avant le calcul ; la taille exacte
+    tranche ensuite. Les chiffres sont isolés arithmétiquement (voir
+    `digit_range`), sans construire la représentation décimale de F(n).
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
+        start (int): Le rang du premier chiffre, à partir de 1, inclus.
+        end (int): Le rang du dernier chiffre, inclus.
+        algo_name (str): Le nom de l'algorithme de Fibonacci utilisé.
+        timeout (float): Le temps maximum en secondes alloué au calcul.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le résultat est émis sous forme d'objet
+            `{"n", "start", "end", "digits", "value"}`.
+
+    Returns:
+        bool: `True` si les chiffres ont été affichés, `False` si
+        l'intervalle est hors de F(n) ou si le calcul a échoué (l'erreur est
+        alors signalée sur la sortie d'erreur).
+    """
+    display = display or DisplayOptions()
+    predicted = predict_digits(n)
+    if start > predicted + 1 or end > predicted + 1:
         print(
-            f"ERREUR: L'algorithme '{algo_name}' a dépassé le timeout de {timeout}s.",
+            f"ERREUR: F({n}) compte environ {predicted} chiffres : les chiffres "
+            f"{start} à {end} sont hors du nombre.",
             file=sys.stderr,
         )
-    except Exception as e:
+        return False
+    result = await _execute_algorithm(context, n, algo_name, timeout)
+    if not result.success:
+        print(f"ERREUR: {result.error}", file=sys.stderr)
+        return False
+    try:
+        value = digit_range(result.value, start, end)
+    except ValueError as e:
+        print(f"ERREUR: {e}", file=sys.stderr)
+        return False
+    digits = count_digits(result.value)
+    if display.json:
+        print(json.dumps({"n": n, "start": start, "end": end, "digits": digits, "value": value}))
+    elif display.quiet:
+        print(value)
+    else:
+        total = group_digits(digits, display.thousands_sep)
+        print(f"Chiffres {start} à {end} de F({n}) ({total} chiffres) : {value}")
+    return True
+
+
+def _run_edges(n: int, k: int, display: Optional[DisplayOptions] = None) -> None:
+    """Calcule et affiche les `k` premiers et `k` derniers chiffres de F(n).
+
+    Le nombre complet n'est jamais construit : les premiers chiffres et la
+    taille de F(n) proviennent de la formule de Binet (`leading_digits`),
+    les derniers du "Fast Doubling" modulaire (`fib_last_digits`). Le coût
+    ne dépend ainsi que de `k` et de la taille de `n`, si bien que les
+    extrémités de F(10^9) s'obtiennent instantanément. Si F(n) compte au
+    plus `2k` chiffres, il est affiché en entier.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
+        k (int): Le nombre de chiffres souhaités à chaque extrémité.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le résultat est émis sous forme d'objet
+            `{"n", "edges", "digits", "head", "tail"}`.
+    """
+    display = display or DisplayOptions()
+    try:
+        head, length = leading_digits(n, k)
+        if length > 2 * k:
+            tail = fib_last_digits(abs(n), k)
+        else:
+            # F(n) est assez court pour être affiché entièrement.
+            head, tail = fib_last_digits(abs(n), 2 * k), ""
+    except ValueError as e:
+        print(f"ERREUR: {e}", file=sys.stderr)
+        sys.exit(1)
+    sign = "-" if apply_negafibonacci_sign(n, 1) < 0 else ""
+    if display.json:
+        print(json.dumps({"n": n, "edges": k, "digits": length, "head": sign + head, "tail": tail}))
+        return
+    value = f"{sign}{head}...{tail}" if tail else sign + head
+    if display.quiet:
+        print(value)
+    else:
+        digits = group_digits(length, display.thousands_sep)
+        print(f"Extrémités de F({n}) ({digits} chiffres) : {value}")
+
+
+async def _run_ratio(
+    context: CalculationContext, n: int, digits: int, display: Optional[DisplayOptions] = None
+) -> None:
+    """Calcule et affiche le rapport F(n)/F(n-1), comparé au nombre d'or φ.
+
+    Le couple (F(n-1), F(n)) est obtenu par un seul "Fast Doubling", puis
+    seuls ses bits de tête sont divisés (voir `fibonacci_ratio`). Le rapport
+    s'approche de φ alternativement par défaut et par excès, l'écart
+    décroissant comme 1/F(n-1)² : chaque terme ajoute environ 0,42 décimale
+    exacte.
+
+    Args:
+        context (CalculationContext): Le contexte du calcul.
+        n (int): L'indice du numérateur, au moins 2 (F(0) = 0 annule le
+            dénominateur pour n = 1, et le rapport ne s'approche de φ que
+            pour les indices positifs).
+        digits (int): Le nombre de chiffres significatifs du rapport.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le résultat est émis sous forme d'objet
+            `{"n", "digits", "ratio", "phi", "difference", "agreeing_decimals"}`.
+    """
+    display = display or DisplayOptions()
+    if n < 2:
         print(
-            f"ERREUR inattendue avec l'algorithme '{algo_name}': {e}", file=sys.stderr
+            f"ERREUR: Le rapport F(n)/F(n-1) n'est pas défini pour n = {n} ; "
+            "il requiert n ≥ 2.",
+            file=sys.stderr,
+        )
+        sys.exit(1)
+    previous, current = await fib_fast_doubling_pair(context, n - 1)
+    ratio = fibonacci_ratio(current, previous, digits)
+    phi = golden_ratio(digits)
+    # L'écart est évalué avec des chiffres de garde : à la précision
+    # affichée, il ne serait que le bruit des deux arrondis.
+    guarded = digits + 10
+    difference = fibonacci_ratio(current, previous, guarded) - golden_ratio(guarded)
+    resolution = Decimal(10) ** (1 - digits)
+    below_resolution = difference.copy_abs() < resolution
+    # Le nombre de décimales exactes, borné par la précision demandée.
+    agreeing = digits - 1
+    if not below_resolution:
+        agreeing = max(int(-difference.copy_abs().log10()), 0)
+    if display.json:
+        print(
+            json.dumps(
+                {
+                    "n": n,
+                    "digits": digits,
+                    "ratio": str(ratio),
+                    "phi": str(phi),
+                    "difference": f"{difference:.2E}",
+                    "agreeing_decimals": agreeing,
+                }
+            )
         )
+    elif display.quiet:
+        print(ratio)
+    else:
+        print(f"F({n})/F({n - 1}) = {ratio}")
+        print(f"φ{' ' * (len(f'F({n})/F({n - 1})') - 1)} = {phi}")
+        gap = f"< 1E-{digits - 1}" if below_resolution else f"{difference:.2E}"
+        print(f"Écart : {gap} ({agreeing} décimales exactes)")
 
 
-async def _run_all_algorithms(
-    context: CalculationContext, n: int, timeout: float
+async def _run_range(
+    context: CalculationContext,
+    start: int,
+    stop: int,
+    display: Optional[DisplayOptions] = None,
 ) -> None:
+    """Calcule et affiche F(start), F(start+1), ..., F(stop).
+
+    Les termes sont produits par `fib_sequence` : seul le couple initial est
+    calculé par "Fast Doubling", chaque terme suivant étant la somme des deux
+    précédents, ce qui est bien moins coûteux qu'un calcul complet par
+    indice. La récurrence restant valable pour les indices négatifs,
+    l'intervalle peut les chevaucher.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        start (int): Le premier indice (éventuellement négatif).
+        stop (int): Le dernier indice, inclus.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, chaque terme est émis sous forme d'objet
+            `{"n", "base", "value"}`, un par ligne.
+    """
+    display = display or DisplayOptions()
+    async with contextlib.aclosing(fib_sequence(context, start)) as terms:
+        n = start
+        async for term in terms:
+            value = format_value(term, display.base)
+            if display.json:
+                print(json.dumps({"n": n, "base": display.base, "value": value}))
+            else:
+                print(f"{n}\t{value}")
+            if n >= stop:
+                break
+            n += 1
+
+
+async def _run_batch(
+    context: CalculationContext,
+    lines: Iterable[str],
+    algo_name: str,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+) -> bool:
+    """Calcule le terme de la suite pour chaque indice lu, un par ligne.
+
+    Les lignes vides et celles commençant par `#` sont ignorées. Un même
+    contexte (et donc le même pool de processus) sert à tous les calculs, et
+    les indices déjà rencontrés ne sont pas recalculés. Chaque résultat est
+    écrit dès qu'il est connu, dans l'ordre de lecture, sous la forme
+    `n<TAB>valeur`, ou d'un objet JSON par ligne en mode JSON.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul partagé.
+        lines (Iterable[str]): Les lignes à traiter (fichier ou stdin).
+        algo_name (str): Le nom de l'algorithme (clé de `ALGORITHM_REGISTRY`).
+        timeout (float): Le timeout applicable à chaque indice.
+        display (Optional[DisplayOptions]): Les options de présentation.
+
+    Returns:
+        bool: `True` si tous les indices ont été calculés, `False` si au
+        moins une ligne est invalide ou un calcul a échoué.
+    """
+    display = display or DisplayOptions()
+    cache: Dict[int, CalculationResult] = {}
+    all_succeeded = True
+
+    for line_number, line in enumerate(lines, start=1):
+        text = line.strip()
+        if not text or text.startswith("#"):
+            continue
+        try:
+            n = int(text)
+        except ValueError:
+            print(f"ERREUR: ligne {line_number} : indice invalide '{text}'.", file=sys.stderr)
+            all_succeeded = False
+            continue
+
+        if n not in cache:
+            cache[n] = await _execute_algorithm(context, n, algo_name, timeout)
+        result = cache[n]
+
+        if not result.success:
+            all_succeeded = False
+            print(f"ERREUR: n={n} ({algo_name}) : {result.error}", file=sys.stderr)
+            if display.json:
+                print(json.dumps({"n": n, "algorithm": algo_name, "error": result.error}))
+            continue
+        value = format_value(result.value, display.base)
+        if display.json:
+            print(
+                json.dumps(
+                    {
+                        "n": n,
+                        "algorithm": algo_name,
+                        "duration_ns": int(result.duration * 1e9),
+                        "base": display.base,
+                        "value": value,
+                    }
+                )
+            )
+        else:
+            print(f"{n}\t{value}")
+    return all_succeeded
+
+
+def _report_divergence(n: int, results: List[CalculationResult]) -> None:
+    """Détaille, sur la sortie d'erreur, un désaccord entre algorithmes.
+
+    Les algorithmes sont regroupés par valeur produite. La valeur la plus
+    fréquente sert de référence (à égalité, celle du premier algorithme) ;
+    pour chacune des autres, la position du premier chiffre décimal qui
+    diffère de la référence est indiquée (voir `first_mismatch`), ce qui
+    donne l'ampleur de l'erreur.
+
+    La majorité peut se tromper, et deux algorithmes n'en ont pas : chaque
+    valeur est donc confrontée à F(n) modulo `_DIVERGENCE_CHECK_MODULUS`,
+    calculé indépendamment par le "Fast Doubling" modulaire en quelques
+    microsecondes. Les algorithmes dont la valeur n'y concorde pas sont
+    désignés comme suspects.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) calculé.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+    """
+    groups: Dict[int, List[str]] = {}
+    for result in results:
+        if result.success:
+            groups.setdefault(result.value, []).append(result.algorithm)
+    # Le tri est stable : à effectif égal, l'ordre des algorithmes est conservé.
+    ranked = sorted(groups.items(), key=lambda group: -len(group[1]))
+    reference, agreeing = ranked[0]
+    majority = len(agreeing) > len(ranked[1][1])
+
+    def _describe(value: int, names: List[str]) -> str:
+        algorithms = ", ".join(f"'{name}'" for name in names)
+        shown = f" = {value}" if abs(value).bit_length() <= _SHOWN_VALUE_BITS else ""
+        return f"{algorithms}{shown}"
+
+    label = "Valeur majoritaire" if majority else "Valeur de référence (aucune majorité)"
+    print(f"  - {label} : {_describe(reference, agreeing)}", file=sys.stderr)
+    for value, names in ranked[1:]:
+        position = first_mismatch(value, iter_decimal_chunks(reference))
+        print(
+            f"  - Valeur divergente : {_describe(value, names)} "
+            f"(premier chiffre différent : position {position})",
+            file=sys.stderr,
+        )
+
+    modulus = _DIVERGENCE_CHECK_MODULUS
+    residue = apply_negafibonacci_sign(n, fib_fast_doubling_mod(abs(n), modulus)) % modulus
+    suspects = [name for value, names in ranked if value % modulus != residue for name in names]
+    if len(suspects) == sum(len(names) for _, names in ranked):
+        verdict = "aucune valeur ne concorde"
+    else:
+        verdict = "en cause : " + ", ".join(f"'{name}'" for name in suspects)
+    print(f"  - Contrôle indépendant de F(n) modulo 2^61 - 1 : {verdict}", file=sys.stderr)
+
+
+async def _run_all_algorithms(
+    context: CalculationContext,
+    n: int,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+    multi_progress: bool = False,
+    repeat: int = 1,
+    algorithms: Optional[Sequence[str]] = None,
+    sequential: bool = False,
+) -> List[CalculationResult]:
     """Exécute tous les algorithmes de Fibonacci enregistrés en parallèle.
 
     Utilise un `asyncio.TaskGroup` pour lancer et gérer l'exécution
     concurrente de tous les algorithmes. Chaque algorithme est encapsulé dans
-    une tâche distincte avec son propre timeout.
+    une tâche distincte avec son propre timeout ; ses échecs (timeout,
+    annulation interne, erreur) sont capturés dans son résultat, si bien
+    qu'ils n'interrompent jamais les autres algorithmes. Si les algorithmes
+    ayant abouti ne s'accordent pas sur la valeur, une erreur est signalée
+    sur la sortie d'erreur.
+
+    Les algorithmes concurrents se disputent le processeur et le pool de
+    processus : leurs durées sont gonflées et ne se comparent pas à celles
+    d'une exécution isolée. En mode séquentiel, chacun s'exécute seul, à la
+    suite du précédent, et sa durée est celle d'une exécution isolée.
 
     Args:
         context (CalculationContext): Le contexte de calcul.
-        n (int): L'indice de la suite de Fibonacci à calculer.
+        n (int): L'indice (éventuellement négatif) de la suite à calculer.
         timeout (float): Le timeout applicable à chaque algorithme individuellement.
+        display (Optional[DisplayOptions]): Les options de présentation.
+        multi_progress (bool): Si vrai, chaque algorithme reçoit sa propre file
+            de progression et une barre par algorithme est affichée.
+        repeat (int): Le nombre d'exécutions de chaque algorithme ; au-delà
+            d'une, le résumé indique les durées minimale, médiane et moyenne.
+        algorithms (Optional[Sequence[str]]): Les algorithmes à exécuter et à
+            comparer ; par défaut, tous les algorithmes de Fibonacci.
+        sequential (bool): Si vrai, les algorithmes sont exécutés l'un après
+            l'autre, dans l'ordre, plutôt que simultanément.
+
+    Returns:
+        List[CalculationResult]: Les résultats, dans l'ordre du registre ou
+        dans celui de `algorithms`.
     """
-    print(f"Calcul de F({n}) en utilisant tous les algorithmes en parallèle...")
+    display = display or DisplayOptions()
+    if algorithms is None:
+        names = [name for name in ALGORITHM_REGISTRY if name not in NEGATIVE_INDEX_RULES]
+        label = "tous les algorithmes"
+    else:
+        names = list(algorithms)
+        label = "les algorithmes " + ", ".join(f"'{name}'" for name in names)
+    if display.show_messages:
+        mode = "l'un après l'autre" if sequential else "en parallèle"
+        print(f"Calcul de F({n}) en utilisant {label} {mode}...")
+
+    queues = {name: asyncio.Queue() for name in names} if multi_progress else {}
 
-    async def _task_wrapper(name: str, func: Callable) -> None:
-        """Encapsule un algorithme pour gestion d'erreurs et de timeout."""
+    async def _task_wrapper(name: str) -> CalculationResult:
+        """Exécute un algorithme et signale son issue dès qu'elle est connue."""
+        algo_context = context
+        if name in queues:
+            algo_context = dataclasses.replace(context, progress_queue=queues[name])
+        status = "cancel"
         try:
-            async with asyncio.timeout(timeout):
-                if asyncio.iscoroutinefunction(func):
-                    await func(context, n)
-                else:
-                    await _run_cpu_bound_task(func, n)
-                print(f"  - Résultat ({name}): Calcul terminé.")
-        except TimeoutError:
-            print(f"  - Résultat ({name}): TIMEOUT ({timeout}s)", file=sys.stderr)
-        except Exception as e:
-            print(f"  - Résultat ({name}): ERREUR ({e})", file=sys.stderr)
+            result = await _execute_repeated(algo_context, n, name, timeout, repeat)
+            status = final_status(result.outcome)
+        finally:
+            if name in queues:
+                await queues[name].put(status)
+        if result.timed_out:
+            reached = describe_progress(result.progress)
+            suffix = f", {reached} atteint" if reached else ""
+            status = paint(f"TIMEOUT ({timeout}s{suffix})", "yellow", display.color, sys.stderr)
+            print(f"  - Résultat ({name}): {status}", file=sys.stderr)
+        elif not result.success:
+            status = paint(f"ERREUR ({result.error})", "red", display.color, sys.stderr)
+            print(f"  - Résultat ({name}): {status}", file=sys.stderr)
+        elif display.show_messages:
+            timing = describe_durations(result.durations)
+            done = f"Calcul terminé ({timing})." if timing else "Calcul terminé."
+            status = paint(done, "green", display.color, sys.stdout)
+            print(f"  - Résultat ({name}): {status}")
+        return result
 
     async with asyncio.TaskGroup() as tg:
-        for name, func in ALGORITHM_REGISTRY.items():
-            tg.create_task(_task_wrapper(name, func))
+        if queues:
+            # Le nombre d'étapes du 'Fast Doubling' : une par bit de n.
+            tg.create_task(
+                multi_progress_manager(queues, abs(n).bit_length(), _bar_colour(display))
+            )
+        if sequential:
+            # Les barres des algorithmes en attente restent à zéro : seule
+            # celle du calcul en cours progresse.
+            results = [await _task_wrapper(name) for name in names]
+        else:
+            tasks = [tg.create_task(_task_wrapper(name)) for name in names]
 
+    if not sequential:
+        results = [task.result() for task in tasks]
+    values = {r.value for r in results if r.success}
+    if len(values) > 1:
+        message = "ERREUR: Les algorithmes ont produit des résultats différents."
+        print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+        _report_divergence(n, results)
+        for result in results:
+            if result.success:
+                metrics.record_outcome(result.algorithm, "mismatch")
+    elif values and not display.show_value:
+        if display.show_messages:
+            succeeded = sum(1 for r in results if r.success)
+            verdict = f"Statut global : les {succeeded} algorithmes ayant abouti concordent."
+            print(paint(verdict, "green", display.color, sys.stdout))
+    elif values and display.quiet and not display.json:
+        write_value(sys.stdout, values.pop(), display.base)
+    return results
 
-async def _run_single_algorithm_with_progress_shutdown(
-    context: CalculationContext, n: int, algo_name: str, timeout: float
+
+def _verify_results(
+    n: int, results: List[CalculationResult], display: Optional[DisplayOptions] = None
+) -> bool:
+    """Vérifie les résultats obtenus contre la formule de Binet.
+
+    Seuls les résultats des algorithmes de Fibonacci sont vérifiés ; les
+    autres suites (voir `NEGATIVE_INDEX_RULES`) sont ignorées.
+
+    Args:
+        n (int): L'indice calculé.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, seuls les échecs sont signalés, sur la sortie d'erreur.
+
+    Returns:
+        bool: `True` si aucun résultat vérifié n'est en désaccord.
+    """
+    display = display or DisplayOptions()
+    all_verified = True
+    for result in results:
+        if not result.success or result.algorithm in NEGATIVE_INDEX_RULES:
+            continue
+        try:
+            verify_leading_digits(result.value, n)
+        except ValueError as e:
+            all_verified = False
+            message = f"ERREUR: La vérification de Binet a échoué pour '{result.algorithm}': {e}"
+            print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+        else:
+            if display.show_messages:
+                print(
+                    f"Vérification ({result.algorithm}): les {DEFAULT_VERIFIED_DIGITS} "
+                    "premiers chiffres concordent avec la formule de Binet."
+                )
+    return all_verified
+
+
+async def _self_check(
+    context: CalculationContext,
+    n: int,
+    results: List[CalculationResult],
+    display: Optional[DisplayOptions] = None,
+) -> bool:
+    """Vérifie les résultats obtenus par l'identité de Cassini.
+
+    Comme pour `_verify_results`, seuls les résultats des algorithmes de
+    Fibonacci sont vérifiés ; des algorithmes concordants produisant la même
+    valeur, chaque valeur distincte n'est vérifiée qu'une fois.
+
+    Args:
+        context (CalculationContext): Le contexte du recalcul, sans
+            progression ni journal d'étapes.
+        n (int): L'indice calculé.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        display (Optional[DisplayOptions]): Les options de présentation.
+
+    Returns:
+        bool: `True` si aucun résultat vérifié ne contredit l'identité.
+    """
+    display = display or DisplayOptions()
+    all_verified = True
+    checked: Dict[int, Optional[str]] = {}
+    for result in results:
+        if not result.success or result.algorithm in NEGATIVE_INDEX_RULES:
+            continue
+        if result.value not in checked:
+            try:
+                await verify_cassini(context, n, result.value)
+            except ValueError as e:
+                checked[result.value] = str(e)
+            else:
+                checked[result.value] = None
+        error = checked[result.value]
+        if error is not None:
+            all_verified = False
+            message = f"ERREUR: L'auto-vérification a échoué pour '{result.algorithm}': {error}"
+            print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+        elif display.show_messages:
+            print(f"Auto-vérification ({result.algorithm}): l'identité de Cassini est vérifiée.")
+    return all_verified
+
+
+def _report_scientific(results: List[CalculationResult], display: Optional[DisplayOptions] = None) -> None:
+    """Affiche le résultat en notation scientifique, si elle est demandée.
+
+    Comme pour `_report_digit_sum`, seul le premier résultat valide est
+    représenté.
+
+    Args:
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        display (Optional[DisplayOptions]): Les options de présentation ;
+            `sci_digits` fixe le nombre de chiffres significatifs.
+    """
+    display = display or DisplayOptions()
+    value = next((r.value for r in results if r.success), None)
+    if value is None or display.sci_digits is None or not display.show_messages:
+        return
+    if not display.show_value:  # '--compare-only' : la valeur n'est pas convertie.
+        return
+    print(f"Notation scientifique : {format_scientific(value, display.sci_digits)}")
+
+
+def _report_digit_sum(results: List[CalculationResult], display: Optional[DisplayOptions] = None) -> None:
+    """Affiche la somme des chiffres et la racine numérique du résultat.
+
+    Les algorithmes concordants produisant la même valeur, seul le premier
+    résultat valide est analysé. Rien n'est affiché si aucun n'a abouti, si
+    les messages sont désactivés ou si la valeur est masquée.
+
+    Args:
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        display (Optional[DisplayOptions]): Les options de présentation.
+    """
+    display = display or DisplayOptions()
+    value = next((r.value for r in results if r.success), None)
+    if value is None or not display.show_messages or not display.show_value:
+        return
+    total, root = digit_sum(value)
+    print(f"Somme des chiffres : {total} (racine numérique : {root})")
+
+
+def _report_hash(
+    results: List[CalculationResult], algorithm: str, display: Optional[DisplayOptions] = None
 ) -> None:
+    """Affiche l'empreinte du résultat (voir `hash_value`).
+
+    Comme pour `_report_digit_sum`, seul le premier résultat valide est
+    considéré. L'empreinte ne demandant pas de conversion décimale, elle est
+    affichée même lorsque la valeur est masquée.
+
+    Args:
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        algorithm (str): L'algorithme d'empreinte.
+        display (Optional[DisplayOptions]): Les options de présentation.
+    """
+    display = display or DisplayOptions()
+    value = next((r.value for r in results if r.success), None)
+    if value is None or not display.show_messages:
+        return
+    print(f"Empreinte {algorithm} : {hash_value(value, algorithm)}")
+
+
+def _report_properties(
+    n: int, results: List[CalculationResult], display: Optional[DisplayOptions] = None
+) -> bool:
+    """Affiche la parité et les petits diviseurs de F(n), puis les confronte au résultat.
+
+    Ces propriétés se déduisent de `n` seul (voir `divisibility_facts`) ; un
+    résultat qui les contredit trahit une erreur de calcul, signalée sur la
+    sortie d'erreur.
+
+    Args:
+        n (int): L'indice calculé.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        display (Optional[DisplayOptions]): Les options de présentation.
+
+    Returns:
+        bool: `True` si aucun résultat valide ne contredit les propriétés.
+    """
+    display = display or DisplayOptions()
+    facts = divisibility_facts(n)
+    if display.show_messages:
+        divisors = ", ".join(str(d) for d, divides in facts.items() if divides) or "aucun"
+        print(f"Propriétés de F({n}), déduites de n :")
+        print(f"  - Parité : {'pair' if facts[2] else 'impair'} (F(n) est pair ssi 3 divise n).")
+        print(f"  - Diviseurs parmi {', '.join(str(d) for d in facts)} : {divisors}.")
+    consistent = True
+    for result in results:
+        if not result.success:
+            continue
+        for divisor, divides in facts.items():
+            if (result.value % divisor == 0) != divides:
+                consistent = False
+                message = (
+                    f"ERREUR: Le résultat de '{result.algorithm}' contredit les propriétés de "
+                    f"F({n}) : il {'devrait' if divides else 'ne devrait pas'} être divisible "
+                    f"par {divisor}."
+                )
+                print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+    if consistent and display.show_messages:
+        print("  - Propriétés confirmées par le résultat.")
+    return consistent
+
+
+def _check_expected(
+    path: str, results: List[CalculationResult], display: Optional[DisplayOptions] = None
+) -> bool:
+    """Compare le résultat à une valeur de référence lue dans un fichier.
+
+    Le fichier est lu par blocs et comparé au fil de l'eau à la
+    représentation décimale du premier résultat valide (voir
+    `first_mismatch`), si bien qu'aucune des deux valeurs n'est chargée en
+    entier sous forme de chaîne.
+
+    Args:
+        path (str): Le chemin du fichier de référence.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, seul un désaccord est signalé, sur la sortie d'erreur.
+
+    Returns:
+        bool: `True` si le résultat concorde avec la référence (ou si aucun
+        algorithme n'a abouti, l'échec étant alors déjà signalé).
+
+    Raises:
+        OSError: Si le fichier de référence ne peut pas être lu.
+    """
+    display = display or DisplayOptions()
+    value = next((r.value for r in results if r.success), None)
+    if value is None:
+        return True
+    with open(path, encoding="utf-8") as reference:
+        position = first_mismatch(value, iter(lambda: reference.read(1 << 16), ""))
+    if position is not None:
+        message = f"ERREUR: Le résultat diffère de la référence '{path}' à la position {position}."
+        print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+        return False
+    if display.show_messages:
+        print(f"Référence ({path}) : le résultat concorde.")
+    return True
+
+
+def _check_cached_reference(
+    n: int,
+    results: List[CalculationResult],
+    directory: Optional[str] = None,
+    display: Optional[DisplayOptions] = None,
+) -> bool:
+    """Compare chaque résultat à la référence de F(n) enregistrée dans le cache.
+
+    Contrairement à `_check_expected`, chaque algorithme est confronté à la
+    référence : c'est ainsi qu'un algorithme ajouté se valide seul, sans
+    recalculer les autres. La comparaison porte d'abord sur les empreintes ;
+    en cas de désaccord, la position du premier chiffre qui diffère est
+    recherchée dans la valeur de la référence.
+
+    Args:
+        n (int): L'indice calculé.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        directory (Optional[str]): Le répertoire du cache (voir
+            `--reference-dir`).
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, seul un désaccord est signalé, sur la sortie d'erreur.
+
+    Returns:
+        bool: `True` si tous les résultats valides concordent avec la
+        référence (ou si aucun algorithme n'a abouti, l'échec étant alors
+        déjà signalé).
+
+    Raises:
+        OSError: Si la référence ne peut pas être lue.
+        ValueError: Si F(n) n'est pas en cache, ou si la référence est
+            corrompue (voir `load_reference`).
+    """
+    display = display or DisplayOptions()
+    successful = [r for r in results if r.success]
+    if not successful:
+        return True
+    reference = load_reference(n, directory)
+    if reference is None:
+        location = directory or default_reference_dir()
+        raise ValueError(f"Aucune référence de F({n}) dans le cache '{location}' (voir '--cache-reference').")
+    matches = True
+    for result in successful:
+        if hash_value(result.value, REFERENCE_HASH_ALGORITHM) == reference.hash:
+            if display.show_messages:
+                print(f"Référence en cache (établie par {reference.algorithm}) : '{result.algorithm}' concorde.")
+            continue
+        matches = False
+        position = first_mismatch(result.value, iter_decimal_chunks(reference.value))
+        message = (
+            f"ERREUR: '{result.algorithm}' diffère de la référence en cache "
+            f"(établie par {reference.algorithm}) à la position {position}."
+        )
+        print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+    return matches
+
+
+def _cache_reference(
+    n: int,
+    results: List[CalculationResult],
+    directory: Optional[str] = None,
+    display: Optional[DisplayOptions] = None,
+) -> bool:
+    """Enregistre le résultat comme référence de F(n) dans le cache.
+
+    Une référence ne vaut que si elle est sûre : l'enregistrement est refusé
+    si un algorithme a échoué ou si les algorithmes ne concordent pas.
+
+    Args:
+        n (int): L'indice calculé.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        directory (Optional[str]): Le répertoire du cache (voir
+            `--reference-dir`).
+        display (Optional[DisplayOptions]): Les options de présentation.
+
+    Returns:
+        bool: `True` si la référence a été enregistrée.
+
+    Raises:
+        OSError: Si la référence ne peut pas être écrite.
+    """
+    display = display or DisplayOptions()
+    if not results or not all(r.success for r in results):
+        print("ERREUR: Référence non enregistrée : un algorithme n'a pas abouti.", file=sys.stderr)
+        return False
+    if len({r.value for r in results}) > 1:
+        print("ERREUR: Référence non enregistrée : les algorithmes ne concordent pas.", file=sys.stderr)
+        return False
+    algorithm = ", ".join(r.algorithm for r in results)
+    path = save_reference(n, results[0].value, algorithm, directory)
+    if display.show_messages:
+        print(f"Référence de F({group_digits(n, display.thousands_sep)}) enregistrée dans {path}.")
+    return True
+
+
+def _write_output(
+    path: str,
+    output_format: str,
+    results: List[CalculationResult],
+    display: Optional[DisplayOptions] = None,
+) -> None:
+    """Écrit le premier résultat valide dans un fichier.
+
+    Args:
+        path (str): Le chemin du fichier de destination.
+        output_format (str): Le format du fichier (voir `OUTPUT_FORMATS`) :
+            décimal ou binaire.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+        display (Optional[DisplayOptions]): Les options de présentation.
+
+    Raises:
+        OSError: Si le fichier ne peut pas être écrit.
+    """
+    display = display or DisplayOptions()
+    value = next((r.value for r in results if r.success), None)
+    if value is None:
+        return
+    if output_format == "binary":
+        with open(path, "wb") as stream:
+            size = write_binary(stream, value)
+    else:
+        with open(path, "w", encoding="utf-8") as stream:
+            write_value(stream, value)
+        size = os.path.getsize(path)
+    if display.show_messages:
+        print(f"Résultat enregistré dans {path} ({group_digits(size, display.thousands_sep)} octets).")
+
+
+def _run_decode(path: str, display: Optional[DisplayOptions] = None) -> None:
+    """Relit un export binaire et affiche ses métadonnées.
+
+    Le nombre de chiffres décimaux est déduit de la taille en bits, sans
+    conversion : il peut être inférieur d'une unité au nombre exact.
+
+    Args:
+        path (str): Le chemin du fichier écrit avec `--format binary`.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, les métadonnées sont émises sous la forme d'un objet.
+
+    Raises:
+        OSError: Si le fichier ne peut pas être lu.
+        ValueError: Si le fichier n'est pas un export binaire valide.
+    """
+    display = display or DisplayOptions()
+    with open(path, "rb") as stream:
+        value = read_binary(stream)
+    bits = value.bit_length()
+    digits = int((bits - 1) * math.log10(2)) + 1 if bits else 1
+    if display.json:
+        print(
+            json.dumps(
+                {
+                    "file": path,
+                    "size_bytes": os.path.getsize(path),
+                    "negative": value < 0,
+                    "bit_length": bits,
+                    "digits": digits,
+                }
+            )
+        )
+        return
+    sep = display.thousands_sep
+    print(f"Fichier           : {path} ({group_digits(os.path.getsize(path), sep)} octets)")
+    print(f"Signe             : {'négatif' if value < 0 else 'positif'}")
+    print(f"Taille binaire    : {group_digits(bits, sep)} bits")
+    print(f"Chiffres décimaux : ~{group_digits(digits, sep)}")
+
+
+def _run_algo_info(display: Optional[DisplayOptions] = None) -> None:
+    """Décrit les algorithmes enregistrés (voir `describe_algorithms`).
+
+    Args:
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, la description est émise sous forme d'objet
+            `{"algorithms": [{"name", "title", "summary", "complexity",
+            "parallel"}...]}`.
+    """
+    display = display or DisplayOptions()
+    infos = describe_algorithms()
+    if display.json:
+        print(json.dumps({"algorithms": [dataclasses.asdict(info) for info in infos]}))
+        return
+    for info in infos:
+        print(f"{info.name} ({info.title})")
+        if info.summary:
+            print(f"  {info.summary}")
+        print(f"  Complexité : {info.complexity or 'non documentée'}")
+        if info.parallel:
+            print("  Multiplications parallèles : oui, au-delà du seuil '--threshold'")
+        else:
+            print("  Multiplications parallèles : non, calcul d'un bloc dans un thread")
+
+
+async def _run_is_fibonacci(
+    context: CalculationContext, m: int, display: Optional[DisplayOptions] = None
+) -> None:
+    """Indique si un entier est un nombre de Fibonacci et en affiche l'indice.
+
+    Args:
+        context (CalculationContext): Le contexte du calcul de confirmation
+            de l'indice.
+        m (int): L'entier à tester.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, la réponse est émise sous la forme d'un objet.
+    """
+    display = display or DisplayOptions()
+    indices = await fibonacci_indices(context, m)
+    if display.json:
+        print(json.dumps({"fibonacci": bool(indices), "indices": list(indices)}))
+        return
+    length = len(str(abs(m)))
+    size = f"{group_digits(length, display.thousands_sep)} chiffre{'s' if length > 1 else ''}"
+    if not indices:
+        print(f"La valeur ({size}) n'est pas un nombre de Fibonacci.")
+        return
+    terms = " = ".join(f"F({index})" for index in indices)
+    print(f"La valeur ({size}) est un nombre de Fibonacci : {terms}.")
+
+
+async def _run_gcd(
+    context: CalculationContext,
+    m: int,
+    n: int,
+    algo_name: str,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+) -> Optional[bool]:
+    """Vérifie l'identité pgcd(F(m), F(n)) = F(pgcd(m, n)) et en affiche le détail.
+
+    F(m), F(n) et F(pgcd(m, n)) sont calculés en parallèle ; le PGCD des deux
+    premiers est ensuite confronté au troisième. Les indices négatifs sont
+    acceptés, l'identité portant alors sur les valeurs absolues.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        m (int): Le premier indice.
+        n (int): Le second indice.
+        algo_name (str): Le nom de l'algorithme de Fibonacci utilisé.
+        timeout (float): Le timeout de chacun des trois calculs.
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le détail est émis sous la forme d'un objet.
+
+    Returns:
+        Optional[bool]: `True` si l'identité est vérifiée, `False` sinon, et
+        `None` si l'un des calculs a échoué (l'échec étant alors signalé).
+    """
+    display = display or DisplayOptions()
+    g = math.gcd(m, n)
+    results = await asyncio.gather(
+        *(_execute_algorithm(context, k, algo_name, timeout) for k in (m, n, g))
+    )
+    for k, result in zip((m, n, g), results):
+        if not result.success:
+            print(f"ERREUR: Le calcul de F({k}) a échoué : {result.error}", file=sys.stderr)
+            return None
+    fm, fn, fg = (result.value for result in results)
+    direct, identity = math.gcd(fm, fn), abs(fg)
+    verified = direct == identity
+    if display.json:
+        print(
+            json.dumps(
+                {
+                    "m": m,
+                    "n": n,
+                    "gcd_index": g,
+                    "base": display.base,
+                    "direct": format_value(direct, display.base),
+                    "identity": format_value(identity, display.base),
+                    "verified": verified,
+                }
+            )
+        )
+    elif display.quiet:
+        write_value(sys.stdout, direct, display.base)
+    else:
+        print(f"pgcd(F({m}), F({n})) = {format_value(direct, display.base)}")
+        print(f"F(pgcd({m}, {n})) = F({g}) = {format_value(identity, display.base)}")
+    if not verified:
+        message = f"ERREUR: pgcd(F({m}), F({n})) diffère de F({g}) : l'un des calculs est erroné."
+        print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+    elif display.show_messages:
+        print(paint("Verdict : l'identité est vérifiée.", "green", display.color, sys.stdout))
+    return verified
+
+
+async def _run_sum(
+    context: CalculationContext,
+    n: int,
+    algo_name: str,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+    selfcheck: bool = False,
+) -> Optional[bool]:
+    """Calcule et affiche la somme F(1) + F(2) + ... + F(n).
+
+    La somme est déduite d'un seul calcul, par l'identité
+    F(1) + ... + F(n) = F(n+2) - 1, et présentée comme un résultat ordinaire.
+    Avec `selfcheck`, F(n+2) est vérifié par l'identité de Cassini et, pour
+    n ≤ `SUM_CHECK_MAX_N`, la somme est aussi calculée terme à terme, ce qui
+    protège l'identité elle-même.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul, sans progression.
+        n (int): Le nombre de termes (positif ou nul).
+        algo_name (str): Le nom de l'algorithme de Fibonacci utilisé.
+        timeout (float): Le timeout du calcul de F(n+2).
+        display (Optional[DisplayOptions]): Les options de présentation. En
+            mode JSON, le résultat est émis comme celui d'un calcul ordinaire
+            (voir `encode_json_result`), la valeur étant la somme.
+        selfcheck (bool): Si vrai, vérifie la somme obtenue.
+
+    Returns:
+        Optional[bool]: `True` si la somme est obtenue (et vérifiée, le cas
+        échéant), `False` si la vérification échoue, et `None` si le calcul
+        a échoué (l'échec étant alors signalé).
+    """
+    display = display or DisplayOptions()
+    if display.show_messages:
+        print(
+            f"Calcul de F(1) + ... + F({n}) = F({n + 2}) - 1 "
+            f"en utilisant l'algorithme '{algo_name}'..."
+        )
+    result = await _execute_algorithm(context, n + 2, algo_name, timeout)
+    if not result.success:
+        _report_failure(result.failure, timeout, result.progress, display)
+        return None
+    total = result.value - 1
+
+    if display.json:
+        summed = dataclasses.replace(result, value=total)
+        print(encode_json_result(n, [summed], display.base, False, display.show_value))
+    elif display.quiet:
+        if display.show_value:
+            write_value(sys.stdout, total, display.base)
+    elif display.show_value:
+        display.write_result(sys.stdout, f"Résultat ({algo_name}): ", total)
+    else:
+        print(f"Résultat ({algo_name}): Calcul terminé.")
+
+    if not selfcheck:
+        return True
+    verified = await _self_check(context, n + 2, [result], display)
+    if n <= SUM_CHECK_MAX_N:
+        expected = await _run_cpu_bound_task(fib_sum_iterative, n)
+        if expected != total:
+            verified = False
+            message = (
+                f"ERREUR: La somme terme à terme des {n} premiers nombres de Fibonacci "
+                f"diffère de F({n + 2}) - 1."
+            )
+            print(paint(message, "red", display.color, sys.stderr), file=sys.stderr)
+        elif display.show_messages:
+            print("Auto-vérification (somme): la somme terme à terme concorde.")
+    return verified
+
+
+async def _run_single_algorithm_with_progress_shutdown(
+    context: CalculationContext,
+    n: int,
+    algo_name: str,
+    timeout: float,
+    display: Optional[DisplayOptions] = None,
+    repeat: int = 1,
+) -> CalculationResult:
     """Exécute un algorithme et garantit la terminaison de la barre de progression.
 
-    Cet enrobeur s'assure que le message de fin (`"done"`) est envoyé à la
+    Cet enrobeur s'assure que le message final est envoyé à la
     `progress_queue`, même en cas d'erreur ou d'annulation de l'algorithme.
     Ceci est crucial pour que la tâche de la barre de progression ne reste
-    pas en attente indéfiniment.
+    pas en attente indéfiniment. Le message porte le statut du calcul (voir
+    `FINAL_STATUSES`) : `"done"` s'il a abouti, son issue sinon, et
+    `"cancel"` si l'appelant l'a interrompu.
 
     Args:
         context (CalculationContext): Le contexte de calcul.
         n (int): L'indice de la suite de Fibonacci à calculer.
         algo_name (str): Le nom de l'algorithme à exécuter.
         timeout (float): Le timeout pour l'exécution.
+        display (Optional[DisplayOptions]): Les options de présentation.
+        repeat (int): Le nombre d'exécutions de l'algorithme.
+
+    Returns:
+        CalculationResult: Le résultat de l'exécution.
     """
+    status = "cancel"
     try:
-        await _run_single_algorithm(context, n, algo_name, timeout)
+        result = await _run_single_algorithm(context, n, algo_name, timeout, display, repeat)
+        status = final_status(result.outcome)
+        return result
     finally:
         if context.progress_queue:
-            await context.progress_queue.put("done")
+            await context.progress_queue.put(status)
+
+
+def _make_context(
+    args: argparse.Namespace,
+    executor: ProcessPoolExecutor,
+    executor_load: ExecutorLoad,
+    **options: Any,
+) -> CalculationContext:
+    """Construit le contexte de calcul d'un mode à partir des options de la CLI.
+
+    Tous les modes partagent le pool, la stratégie de multiplication, la
+    table de correspondance (`--no-lut`) et la garde mémoire (`--memory`,
+    désactivée par `--force`) ; seul le calcul principal y ajoute la
+    progression, le traceur et la surveillance.
+
+    Args:
+        args (argparse.Namespace): Les options de la ligne de commande.
+        executor (ProcessPoolExecutor): Le pool partagé par les calculs.
+        executor_load (ExecutorLoad): L'occupation du pool.
+        **options: Les champs propres au mode, ajoutés au contexte.
+
+    Returns:
+        CalculationContext: Le contexte de calcul du mode.
+    """
+    return CalculationContext(
+        threshold=args.threshold,
+        executor=executor,
+        mul_algo=args.mul_algo,
+        mul_backend=args.mul_backend,
+        executor_load=executor_load,
+        use_lookup_table=args.use_lookup_table,
+        memory_fraction=None if args.force else args.memory_fraction or None,
+        **options,
+    )
 
 
 async def main_async() -> None:
     """Point d'entrée principal et orchestrateur de l'application asynchrone.
 
     Cette fonction orchestre le flux de l'application :
-    1.  Analyse les arguments de la ligne de commande.
+    1.  Analyse les arguments de la ligne de commande, leur applique la
+        requête JSON lue sur l'entrée standard avec la sous-commande
+        `request`, et résout `--algo best` en un algorithme unique.
     2.  Initialise le `ProcessPoolExecutor` pour les calculs CPU-bound.
-    3.  Exécute le mode de calibration si l'option `--calibrate` est passée.
-    4.  Crée le `CalculationContext` partagé.
-    5.  Lance le ou les algorithmes de Fibonacci.
-    6.  Gère la barre de progression si l'option `--details` est activée.
+    3.  Exécute le mode de calibration si l'option `--calibrate` est passée,
+        enregistre le seuil trouvé si `--calibrate-save` est passée, et
+        toutes les mesures si `--calibrate-json` est passée.
+        Exécute le benchmark de débit si l'option `--bench` est passée (ou
+        mesure le passage à l'échelle avec `--scaling`), et
+        décrit un export binaire si l'option `--decode` est passée.
+        Recherche l'indice d'un entier avec la sous-commande `isfib`, et
+        vérifie l'identité du PGCD avec la sous-commande `gcd`.
+    4.  Démarre le serveur HTTP si l'option `--serve` est passée.
+    5.  Traite un intervalle ou une liste d'indices si l'option `--range`,
+        `--batch` ou `--batch-file` est passée.
+    6.  Exécute le calcul modulaire si l'option `--mod`, `--tail`,
+        `--edges` ou `--scaled` est passée.
+    7.  Refuse le calcul si sa mémoire de pointe prévue dépasse la mémoire
+        disponible, sauf si l'option `--force` est passée. Extrait des
+        chiffres de F(n) si l'option `--digit-range` est passée. Calcule la
+        somme des n premiers termes si l'option `--sum` est passée, et le
+        rapport F(n)/F(n-1) si l'option `--ratio` est passée ; sinon, crée
+        le `CalculationContext` partagé.
+    8.  Lance le ou les algorithmes de Fibonacci.
+    9.  Gère la progression si l'option `--details` ou `--progress` est passée,
+        et rapporte la consommation mémoire du calcul avec `--details`.
+        Journalise chaque étape du calcul si l'option `--trace` est passée.
+        Surveille le calcul si l'option `--watchdog` est passée.
+        Profile le calcul si l'option `--profile` est passée. Interrompt le
+        calcul avant une étape qui risquerait d'épuiser la mémoire (voir
+        `--memory-fraction`), avec le code de sortie `MEMORY_EXIT_CODE`.
+    10. Émet le document JSON des résultats si l'option `--json` est passée,
+        écrit le résultat dans un fichier si l'option `--output` est
+        passée, et ajoute les mesures au journal CSV si l'option `--csv`
+        est passée. Sort ensuite avec le code 1 si aucun algorithme n'a
+        produit de valeur.
+    11. Vérifie les résultats par la formule de Binet si l'option `--verify`
+        est passée, et par l'identité de Cassini si l'option `--selfcheck`
+        est passée (code `EXPECT_MISMATCH_EXIT_CODE` en cas d'échec).
+    12. Compare le résultat à une valeur de référence si l'option `--expect`
+        est passée, ou à la référence en cache si l'option `--compare-cached`
+        est passée, et sort avec le code `EXPECT_MISMATCH_EXIT_CODE` en cas
+        de désaccord.
+    13. Enregistre le résultat comme référence en cache si l'option
+        `--cache-reference` est passée.
+    14. Sort avec le code `EXPECT_MISMATCH_EXIT_CODE` si les algorithmes
+        comparés ont produit des résultats différents.
+
+    En mode JSON, la sortie standard ne contient que le document ; la barre
+    de progression (`tqdm`) et les erreurs sont écrites sur la sortie d'erreur.
     """
     args = parse_args()
+    if args.stdin_json:
+        try:
+            apply_json_request(args, sys.stdin.read())
+        except ValueError as e:
+            print(f"ERREUR: {e}", file=sys.stderr)
+            sys.exit(1)
+    if args.algo == "best":
+        args.algo = select_best_algorithm()
 
     # Le 'with' s'assure que le pool de processus est correctement fermé à la fin.
-    with ProcessPoolExecutor() as executor:
+    # '--workers' borne le nombre de processus, et donc le parallélisme des
+    # multiplications ; par défaut, il y en a un par cœur.
+    with ProcessPoolExecutor(max_workers=args.workers) as executor:
         if args.calibrate:
-            await run_calibration(executor)
+            if args.crossover:
+                timeout = args.calibrate_timeout or CROSSOVER_TIMEOUT
+                await _run_crossover(
+                    executor,
+                    timeout,
+                    args.threshold,
+                    args.mul_algo,
+                    args.thousands_sep,
+                    args.mul_backend,
+                )
+                return
+            points = [] if args.calibrate_json else None
+            threshold = await run_calibration(
+                executor, args.calibrate_timeout, points, args.mul_backend
+            )
+            if points is not None:
+                try:
+                    write_calibration_points(
+                        args.calibrate_json,
+                        points,
+                        threshold,
+                        resolve_mul_backend(args.mul_backend),
+                    )
+                except OSError as e:
+                    print(f"ERREUR: {e}", file=sys.stderr)
+                    sys.exit(1)
+                print(f">> {len(points)} points de mesure enregistrés dans {args.calibrate_json}.")
+            if args.calibrate_save and threshold is not None:
+                path = save_config({"threshold": threshold})
+                print(f">> Seuil de {threshold} chiffres enregistré dans {path}.")
             return
 
-        if args.n is None:
+        if args.trunc_limit is not None and 2 * args.trunc_edges >= args.trunc_limit:
             print(
-                "ERREUR: L'argument '-n' est obligatoire sauf si --calibrate est utilisé.",
+                f"ERREUR: '--trunc-edges' ({args.trunc_edges}) doit rester inférieur à la moitié "
+                f"de '--trunc-limit' ({args.trunc_limit}).",
                 file=sys.stderr,
             )
             sys.exit(1)
 
-        progress_queue = asyncio.Queue() if args.details else None
+        display = DisplayOptions(
+            json=args.json,
+            base=args.base,
+            quiet=args.quiet,
+            color=args.color,
+            thousands_sep=args.thousands_sep,
+            sci_digits=args.sci_digits,
+            show_value=not args.compare_only,
+            truncate_limit=args.trunc_limit,
+            truncate_edges=args.trunc_edges,
+        )
+
+        # L'occupation du pool est commune à tous les calculs qui le
+        # partagent ; la stratégie 'adaptive' la consulte.
+        executor_load = ExecutorLoad(args.workers or os.cpu_count() or 1)
+
+        if args.serve is not None:
+            # Chaque requête réutilise le pool et le timeout configurés ; la
+            # progression n'a pas de sens côté serveur.
+            server_context = _make_context(args, executor, executor_load)
+            def execute(n: int, algo_name: str) -> Awaitable[CalculationResult]:
+                return _execute_algorithm(server_context, n, algo_name, args.timeout)
+
+            drainer = None
+            try:
+                if args.cache_size:
+                    execute = CachingExecutor(execute, args.cache_size)
+                    # '--cache-idle' rend la mémoire du cache pendant les
+                    # périodes creuses.
+                    if args.cache_idle:
+                        drainer = asyncio.create_task(execute.drain_when_idle(args.cache_idle))
+                await run_server(
+                    args.serve, execute, ALGORITHM_REGISTRY.keys(), args.max_n, args.shutdown_grace
+                )
+            except (ValueError, OSError) as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+            finally:
+                if drainer is not None:
+                    drainer.cancel()
+            return
+
+        if args.range is not None:
+            range_context = _make_context(args, executor, executor_load)
+            await _run_range(range_context, *args.range, display)
+            return
+
+        if args.batch or args.batch_file:
+            if args.algo == "all" or "," in args.algo:
+                print("ERREUR: Le mode batch requiert un algorithme unique.", file=sys.stderr)
+                sys.exit(1)
+            batch_context = _make_context(args, executor, executor_load)
+            try:
+                source = (
+                    open(args.batch_file, encoding="utf-8")
+                    if args.batch_file
+                    else contextlib.nullcontext(sys.stdin)
+                )
+            except OSError as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+            with source as lines:
+                succeeded = await _run_batch(
+                    batch_context, lines, args.algo, args.timeout, display
+                )
+            if not succeeded:
+                sys.exit(1)
+            return
+
+        if args.bench:
+            if args.algo == "all" or "," in args.algo:
+                print("ERREUR: Le benchmark requiert un algorithme unique.", file=sys.stderr)
+                sys.exit(1)
+            if args.scaling is not None:
+                await _run_scaling(
+                    args.scaling,
+                    args.algo,
+                    args.timeout,
+                    args.threshold,
+                    args.mul_algo,
+                    args.workers,
+                    display,
+                    args.mul_backend,
+                )
+                return
+            bench_context = _make_context(args, executor, executor_load)
+            await _run_benchmark(bench_context, args.algo, args.timeout, display)
+            return
+
+        if args.gcd is not None:
+            if args.algo == "all" or "," in args.algo or args.algo in NEGATIVE_INDEX_RULES:
+                print("ERREUR: L'identité du PGCD requiert un algorithme de Fibonacci unique.", file=sys.stderr)
+                sys.exit(1)
+            gcd_context = _make_context(args, executor, executor_load)
+            verified = await _run_gcd(gcd_context, *args.gcd, args.algo, args.timeout, display)
+            if verified is None:
+                sys.exit(1)
+            if not verified:
+                sys.exit(EXPECT_MISMATCH_EXIT_CODE)
+            return
+
+        if args.algo_info:
+            _run_algo_info(display)
+            return
+
+        if args.decode is not None:
+            try:
+                _run_decode(args.decode, display)
+            except (OSError, ValueError) as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+            return
+
+        if args.is_fib is not None:
+            inverse_context = _make_context(args, executor, executor_load)
+            await _run_is_fibonacci(inverse_context, args.is_fib, display)
+            return
+
+        if args.n is None:
+            print("ERREUR: L'argument '-n' est obligatoire pour calculer F(n).", file=sys.stderr)
+            sys.exit(1)
+
+        if args.estimate:
+            _run_estimate(args.n, display)
+            return
+
+        if args.mod is not None:
+            _run_modular(args.n, args.mod, display)
+            return
+
+        if args.edges is not None:
+            _run_edges(args.n, args.edges, display)
+            return
+
+        if args.scaled is not None:
+            _run_scaled(args.n, args.scaled, display)
+            return
+
+        if args.tail is not None:
+            _run_tail(args.n, args.tail, display)
+            return
+
+        # Un résultat qui ne tiendrait pas en mémoire est refusé avant d'être
+        # alloué, plutôt que de laisser le système tuer le processus.
+        if not args.force:
+            error = check_memory(args.n)
+            if error is not None:
+                print(f"ERREUR: {error} Utiliser --force pour passer outre.", file=sys.stderr)
+                sys.exit(1)
+
+        if args.digit_range is not None:
+            if args.algo == "all" or "," in args.algo or args.algo in NEGATIVE_INDEX_RULES:
+                print(
+                    "ERREUR: L'extraction de chiffres requiert un algorithme de Fibonacci unique.",
+                    file=sys.stderr,
+                )
+                sys.exit(1)
+            digits_context = _make_context(args, executor, executor_load)
+            if not await _run_digit_range(
+                digits_context, args.n, *args.digit_range, args.algo, args.timeout, display
+            ):
+                sys.exit(1)
+            return
 
-        context = CalculationContext(
-            threshold=args.threshold,
-            executor=executor,
+        if args.ratio is not None:
+            ratio_context = _make_context(args, executor, executor_load)
+            await _run_ratio(ratio_context, args.n, args.ratio, display)
+            return
+
+        if args.sum:
+            if args.n < 0:
+                print("ERREUR: La somme requiert un nombre de termes positif ou nul.", file=sys.stderr)
+                sys.exit(1)
+            if args.algo == "all" or "," in args.algo or args.algo in NEGATIVE_INDEX_RULES:
+                print("ERREUR: La somme requiert un algorithme de Fibonacci unique.", file=sys.stderr)
+                sys.exit(1)
+            sum_context = _make_context(args, executor, executor_load)
+            verified = await _run_sum(
+                sum_context, args.n, args.algo, args.timeout, display, args.selfcheck
+            )
+            if verified is None:
+                sys.exit(1)
+            if not verified:
+                sys.exit(EXPECT_MISMATCH_EXIT_CODE)
+            return
+
+        # La taille du résultat est connue avant le calcul : '-d' l'annonce,
+        # pour qu'un très grand indice ne soit pas lancé à l'aveugle.
+        if args.details and display.show_messages and args.algo not in NEGATIVE_INDEX_RULES:
+            digits = group_digits(predict_digits(args.n), display.thousands_sep)
+            bits = group_digits(predict_bit_length(args.n), display.thousands_sep)
+            print(f"F({args.n}) comptera environ {digits} chiffres ({bits} bits).")
+
+        # '--progress' active la progression dans le mode choisi ; '-d' seul
+        # conserve la barre interactive historique.
+        # En mode silencieux, seule une progression explicitement demandée est affichée.
+        progress_mode = args.progress or ("bar" if args.details and not args.quiet else None)
+        progress_queue = asyncio.Queue() if progress_mode else None
+
+        try:
+            trace_stream = (
+                None if args.trace is None
+                else sys.stderr if args.trace == "-"
+                else open(args.trace, "w", encoding="utf-8")
+            )
+        except OSError as e:
+            print(f"ERREUR: {e}", file=sys.stderr)
+            sys.exit(1)
+
+        watchdog = (
+            Watchdog(args.watchdog, WATCHDOG_EXIT_CODE if args.watchdog_abort else None)
+            if args.watchdog
+            else None
+        )
+        # 'kill -USR1 <pid>' rapporte l'avancement sans interrompre le calcul.
+        snapshot = ProgressSnapshot()
+        snapshot.install()
+        context = _make_context(
+            args,
+            executor,
+            executor_load,
             progress_queue=progress_queue,
+            warn_at=args.warn_at or None,
+            tracer=_trace_writer(trace_stream) if trace_stream else None,
+            watchdog=watchdog,
+            snapshot=snapshot,
         )
 
-        if args.algo == "all":
-            await _run_all_algorithms(context, args.n, args.timeout)
-        else:
-            # Si la barre de progression est activée, on la lance en parallèle du calcul.
-            if progress_queue and args.algo in ["fast", "matrix"]:
-                total_steps = args.n.bit_length()
-                async with asyncio.TaskGroup() as tg:
-                    tg.create_task(
-                        progress_bar_manager(
-                            progress_queue, total_steps, f"Algo: {args.algo}"
+        if args.repeat > 1 or args.warmup:
+            await _warm_up_executor(executor, args.workers)
+        if args.warmup:
+            if args.algo == "all":
+                warmed = [name for name in ALGORITHM_REGISTRY if name not in NEGATIVE_INDEX_RULES]
+            else:
+                warmed = args.algo.split(",")
+            if display.show_messages:
+                print("Préchauffage des algorithmes...")
+            await _warm_up_algorithms(context, warmed, args.timeout)
+
+        # Avec '-d', la mémoire est relevée avant et après le calcul ; en
+        # mode comparaison, le pic est celui de l'ensemble des algorithmes.
+        track_memory = args.details and display.show_messages
+        # Le profileur démarre le premier, pour que '--profile mem' suive les
+        # allocations avec leur pile d'appel complète.
+        profiler = start_profiling(args.profile) if args.profile else None
+        memory_before = start_memory_tracking() if track_memory else None
+        try:
+            if args.algo == "all" or "," in args.algo:
+                results = await _run_all_algorithms(
+                    context,
+                    args.n,
+                    args.timeout,
+                    display,
+                    progress_mode == "multi",
+                    args.repeat,
+                    None if args.algo == "all" else args.algo.split(","),
+                    args.sequential,
+                )
+            else:
+                # Si la barre de progression est activée, on la lance en parallèle du calcul.
+                if progress_queue and args.algo in ["fast", "matrix", "lucas"]:
+                    total_steps = args.n.bit_length()
+                    async with asyncio.TaskGroup() as tg:
+                        tg.create_task(
+                            progress_bar_manager(
+                                progress_queue,
+                                total_steps,
+                                f"Algo: {args.algo}",
+                                "bar" if progress_mode == "multi" else progress_mode,
+                                _bar_colour(display),
+                            )
                         )
-                    )
-                    # On utilise le nouveau wrapper ici
-                    tg.create_task(
-                        _run_single_algorithm_with_progress_shutdown(
-                            context, args.n, args.algo, args.timeout
+                        # On utilise le nouveau wrapper ici
+                        task = tg.create_task(
+                            _run_single_algorithm_with_progress_shutdown(
+                                context, args.n, args.algo, args.timeout, display, args.repeat
+                            )
                         )
-                    )
-            else:
-                await _run_single_algorithm(context, args.n, args.algo, args.timeout)
+                    results = [task.result()]
+                else:
+                    results = [
+                        await _run_single_algorithm(
+                            context, args.n, args.algo, args.timeout, display, args.repeat
+                        )
+                    ]
+        finally:
+            memory_after = stop_memory_tracking() if track_memory else None
+            if trace_stream is not None and trace_stream is not sys.stderr:
+                trace_stream.close()
+            if watchdog is not None:
+                watchdog.close()
+            snapshot.uninstall()
+            if profiler is not None:
+                profiler.stop()
+
+        if profiler is not None:
+            profile_path = args.profile_out or DEFAULT_PROFILE_PATHS[args.profile]
+            try:
+                profiler.write(profile_path)
+            except OSError as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+            print(f"Profil '{args.profile}' écrit dans {profile_path}.", file=sys.stderr)
+
+        if memory_before is not None:
+            scope = " (tous algorithmes confondus)" if len(results) > 1 else ""
+            print(describe_memory(memory_before, memory_after) + scope)
+
+        if display.json:
+            print(
+                encode_json_result(
+                    args.n, results, display.base, args.digitsum, display.show_value, args.hash
+                )
+            )
+        else:
+            _report_scientific(results, display)
+            if args.digitsum:
+                _report_digit_sum(results, display)
+            if args.hash:
+                _report_hash(results, args.hash, display)
+
+        if any(result.outcome == ErrorCategory.MEMORY.value for result in results):
+            sys.exit(MEMORY_EXIT_CODE)
+
+        if (
+            args.details
+            and args.algo not in NEGATIVE_INDEX_RULES
+            and not _report_properties(args.n, results, display)
+        ):
+            sys.exit(1)
+
+        if args.output is not None:
+            try:
+                _write_output(args.output, args.format, results, display)
+            except OSError as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+
+        if args.csv is not None:
+            try:
+                append_csv(args.csv, args.n, results)
+            except OSError as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+
+        # Un échec de tous les algorithmes (timeout, erreur) n'écrit rien sur
+        # la sortie standard avec '-q' : le code de sortie le signale, une
+        # fois l'échec consigné dans le journal CSV.
+        if not any(result.success for result in results):
+            sys.exit(1)
+
+        if args.verify and not _verify_results(args.n, results, display):
+            sys.exit(1)
+
+        if args.selfcheck:
+            check_context = dataclasses.replace(
+                context, progress_queue=None, reporter=None, tracer=None, warn_at=None, watchdog=None
+            )
+            if not await _self_check(check_context, args.n, results, display):
+                sys.exit(EXPECT_MISMATCH_EXIT_CODE)
+
+        if args.expect is not None:
+            try:
+                matches = _check_expected(args.expect, results, display)
+            except OSError as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+            if not matches:
+                sys.exit(EXPECT_MISMATCH_EXIT_CODE)
+
+        if args.compare_cached:
+            try:
+                matches = _check_cached_reference(args.n, results, args.reference_dir, display)
+            except (OSError, ValueError) as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+            if not matches:
+                sys.exit(EXPECT_MISMATCH_EXIT_CODE)
+
+        if args.cache_reference:
+            try:
+                cached = _cache_reference(args.n, results, args.reference_dir, display)
+            except OSError as e:
+                print(f"ERREUR: {e}", file=sys.stderr)
+                sys.exit(1)
+            if not cached:
+                sys.exit(1)
+
+        if len({r.value for r in results if r.success}) > 1:
+            sys.exit(EXPECT_MISMATCH_EXIT_CODE)
@@ -0,0 +1,322 @@
+"""
+Module du benchmark de débit, pour comparer des machines entre elles.
+
+Contrairement aux benchmarks de la suite de tests, qui mesurent des
+opérations isolées, `run_benchmark` calcule F(n) pour quelques indices de
+référence avec l'algorithme choisi et rapporte des métriques orientées
+matériel : chiffres et bits produits par seconde, ainsi qu'un score composite
+(la moyenne géométrique des débits, en millions de chiffres par seconde ;
+seuls les scores obtenus sur les mêmes indices sont comparables). Le
+rapport indique la version de Python, la plate-forme et le moteur de
+multiplication, afin de pouvoir être collé tel quel dans un ticket.
+
+`run_scaling` mesure quant à lui le passage à l'échelle de la multiplication
+parallèle : le même F(n) est calculé avec un pool de 1, 2, 4, 8... processus,
+et le rapport donne l'accélération et l'efficacité parallèle de chaque
+taille de pool, ainsi que celle à partir de laquelle l'accélération plafonne.
+"""
+
+import math
+import os
+import platform
+from dataclasses import dataclass
+from typing import Awaitable, Callable, List, Optional, Sequence
+
+from .cli.output import CalculationResult, _format_duration, group_digits
+from .core.multiplication import MUL_BACKEND
+
+# Indices calculés par le benchmark, du plus petit au plus grand.
+BENCHMARK_SIZES = (1_000_000, 10_000_000, 100_000_000)
+
+# log10(2) : nombre de chiffres décimaux par bit.
+_LOG10_2 = math.log10(2)
+
+# Indice calculé par défaut par la mesure du passage à l'échelle.
+SCALING_N = 10_000_000
+
+# Gain minimal d'accélération, relativement à la taille de pool précédente,
+# en deçà duquel le passage à l'échelle est considéré comme plafonnant.
+SCALING_PLATEAU_GAIN = 1.1
+
+
+@dataclass(frozen=True)
+class BenchmarkResult:
+    """Mesure du calcul de F(n) pour un indice du benchmark.
+
+    Attributes:
+        n (int): L'indice calculé.
+        duration (float): La durée du calcul, en secondes.
+        bit_length (int): La longueur en bits du résultat (0 en cas d'échec).
+        error (Optional[str]): Le message d'échec, le cas échéant.
+    """
+
+    n: int
+    duration: float
+    bit_length: int = 0
+    error: Optional[str] = None
+
+    @property
+    def digits(self) -> int:
+        """Le nombre de chiffres décimaux du résultat, déduit de sa taille en bits."""
+        return int((self.bit_length - 1) * _LOG10_2) + 1 if self.bit_length else 0
+
+    @property
+    def digits_per_second(self) -> float:
+        """Le débit, en chiffres décimaux produits par seconde."""
+        return self.digits / self.duration if self.duration > 0 else math.inf
+
+    @property
+    def bits_per_second(self) -> float:
+        """Le débit, en bits produits par seconde."""
+        return self.bit_length / self.duration if self.duration > 0 else math.inf
+
+
+async def run_benchmark(
+    execute: Callable[[int, str], Awaitable[CalculationResult]],
+    algo_name: str,
+    sizes: Optional[Sequence[int]] = None,
+) -> List[BenchmarkResult]:
+    """Calcule F(n) pour chaque indice de référence et en mesure le débit.
+
+    Les indices sont traités du plus petit au plus grand ; au premier échec
+    (un timeout, typiquement), les suivants, plus coûteux encore, ne sont pas
+    lancés.
+
+    Args:
+        execute (Callable[[int, str], Awaitable[CalculationResult]]): La
+            fonction qui exécute et chronomètre un calcul `(n, algo_name)`.
+        algo_name (str): Le nom de l'algorithme mesuré.
+        sizes (Optional[Sequence[int]]): Les indices à calculer ; par défaut,
+            `BENCHMARK_SIZES`.
+
+    Returns:
+        List[BenchmarkResult]: Une mesure par indice traité.
+    """
+    results = []
+    for n in BENCHMARK_SIZES if sizes is None else sizes:
+        result = await execute(n, algo_name)
+        if not result.success:
+            results.append(BenchmarkResult(n, result.duration, error=result.error))
+            break
+        results.append(BenchmarkResult(n, result.duration, result.value.bit_length()))
+    return results
+
+
+def benchmark_score(results: Sequence[BenchmarkResult]) -> Optional[float]:
+    """Calcule le score composite d'un benchmark.
+
+    Args:
+        results (Sequence[BenchmarkResult]): Les mesures du benchmark.
+
+    Returns:
+        Optional[float]: La moyenne géométrique des débits, en millions de
+        chiffres par seconde, ou `None` si aucune mesure n'a abouti.
+    """
+    rates = [r.digits_per_second for r in results if r.error is None]
+    if not rates:
+        return None
+    return math.exp(sum(math.log(rate) for rate in rates) / len(rates)) / 1e6
+
+
+def describe_machine(mul_backend: str = MUL_BACKEND) -> str:
+    """Décrit l'environnement d'exécution, pour comparer des rapports entre eux.
+
+    Args:
+        mul_backend (str): Le moteur effectif des grands produits (voir
+            `resolve_mul_backend`) ; par défaut, celui choisi au chargement.
+    """
+    return (
+        f"Python {platform.python_version()} ({platform.python_implementation()}), "
+        f"{platform.system()} {platform.machine()}, {os.cpu_count()} CPU, "
+        f"multiplication : {mul_backend}"
+    )
+
+
+def describe_benchmark(
+    algo_name: str, results: Sequence[BenchmarkResult], sep: str = " ", mul_backend: str = MUL_BACKEND
+) -> str:
+    """Met en forme les mesures d'un benchmark sous forme de tableau.
+
+    Args:
+        algo_name (str): Le nom de l'algorithme mesuré.
+        results (Sequence[BenchmarkResult]): Les mesures du benchmark.
+        sep (str): Le séparateur des milliers.
+        mul_backend (str): Le moteur effectif des grands produits.
+
+    Returns:
+        str: Le rapport, sur plusieurs lignes.
+    """
+    lines = [
+        f"Benchmark PyFibonacci, algorithme '{algo_name}'",
+        describe_machine(mul_backend),
+        "| n           | Chiffres    | Durée       | Chiffres/s | Bits/s     |",
+        "|-------------|-------------|-------------|------------|------------|",
+    ]
+    for r in results:
+        n = group_digits(r.n, sep)
+        if r.error is not None:
+            lines.append(f"| {n:>11} | ÉCHEC : {r.error}")
+            continue
+        digits = group_digits(r.digits, sep)
+        lines.append(
+            f"| {n:>11} | {digits:>11} | {_format_duration(r.duration):>11} "
+            f"| {r.digits_per_second:10.3e} | {r.bits_per_second:10.3e} |"
+        )
+    score = benchmark_score(results)
+    if score is None:
+        lines.append("Score : -")
+    else:
+        measured = sum(1 for r in results if r.error is None)
+        lines.append(f"Score : {score:.2f} (millions de chiffres par seconde, sur {measured} indices)")
+    return "\n".join(lines)
+
+
+@dataclass(frozen=True)
+class ScalingResult:
+    """Mesure du calcul de F(n) avec un pool d'une taille donnée.
+
+    Attributes:
+        workers (int): Le nombre de processus du pool.
+        duration (float): La durée du calcul, en secondes.
+        error (Optional[str]): Le message d'échec, le cas échéant.
+    """
+
+    workers: int
+    duration: float
+    error: Optional[str] = None
+
+
+def scaling_worker_counts(max_workers: Optional[int] = None) -> List[int]:
+    """Retourne les tailles de pool mesurées : les puissances de 2, puis le maximum.
+
+    Args:
+        max_workers (Optional[int]): La plus grande taille de pool ; par
+            défaut, le nombre de cœurs.
+
+    Returns:
+        List[int]: Les tailles, par ordre croissant (1, 2, 4... max_workers).
+    """
+    limit = max_workers or os.cpu_count() or 1
+    counts = [2**k for k in range(limit.bit_length()) if 2**k <= limit]
+    if counts[-1] != limit:
+        counts.append(limit)
+    return counts
+
+
+async def run_scaling(
+    execute: Callable[[int], Awaitable[CalculationResult]], worker_counts: Sequence[int]
+) -> List[ScalingResult]:
+    """Calcule le même F(n) pour chaque taille de pool et en mesure la durée.
+
+    Au premier échec, les tailles suivantes ne sont pas mesurées : sans la
+    référence d'un pool plus petit, elles ne seraient pas comparables.
+
+    Args:
+        execute (Callable[[int], Awaitable[CalculationResult]]): La fonction
+            qui exécute et chronomètre le calcul avec un pool du nombre de
+            processus donné.
+        worker_counts (Sequence[int]): Les tailles de pool, par ordre
+            croissant ; la première sert de référence.
+
+    Returns:
+        List[ScalingResult]: Une mesure par taille de pool traitée.
+    """
+    results = []
+    for workers in worker_counts:
+        result = await execute(workers)
+        if not result.success:
+            results.append(ScalingResult(workers, result.duration, result.error))
+            break
+        results.append(ScalingResult(workers, result.duration))
+    return results
+
+
+def scaling_speedups(results: Sequence[ScalingResult]) -> List[float]:
+    """Calcule l'accélération de chaque mesure réussie par rapport à la première.
+
+    Args:
+        results (Sequence[ScalingResult]): Les mesures, la première servant
+            de référence.
+
+    Returns:
+        List[float]: Les accélérations des mesures réussies, dans l'ordre.
+    """
+    measured = [r for r in results if r.error is None]
+    if not measured:
+        return []
+    reference = measured[0].duration
+    return [reference / r.duration if r.duration > 0 else math.inf for r in measured]
+
+
+def scaling_plateau(results: Sequence[ScalingResult]) -> Optional[int]:
+    """Retourne la taille de pool à partir de laquelle l'accélération plafonne.
+
+    Le plafond est atteint à la première taille dont l'accélération ne
+    dépasse pas celle de la taille précédente d'au moins
+    `SCALING_PLATEAU_GAIN`.
+
+    Args:
+        results (Sequence[ScalingResult]): Les mesures du passage à l'échelle.
+
+    Returns:
+        Optional[int]: La dernière taille de pool profitable, ou `None` si
+        l'accélération progresse jusqu'à la plus grande taille mesurée.
+    """
+    measured = [r for r in results if r.error is None]
+    speedups = scaling_speedups(results)
+    for previous, current, result in zip(speedups, speedups[1:], measured):
+        if current < previous * SCALING_PLATEAU_GAIN:
+            return result.workers
+    return None
+
+
+def describe_scaling(
+    n: int,
+    algo_name: str,
+    results: Sequence[ScalingResult],
+    sep: str = " ",
+    mul_backend: str = MUL_BACKEND,
+) -> str:
+    """Met en forme les mesures du passage à l'échelle sous forme de tableau.
+
+    L'efficacité parallèle est l'accélération rapportée au nombre de
+    processus, relativement à la première mesure (un seul processus, en
+    général) : 100 % correspond à un passage à l'échelle parfait.
+
+    Args:
+        n (int): L'indice calculé.
+        algo_name (str): Le nom de l'algorithme mesuré.
+        results (Sequence[ScalingResult]): Les mesures du passage à l'échelle.
+        sep (str): Le séparateur des milliers.
+        mul_backend (str): Le moteur effectif des grands produits.
+
+    Returns:
+        str: Le rapport, sur plusieurs lignes.
+    """
+    lines = [
+        f"Passage à l'échelle de F({group_digits(n, sep)}), algorithme '{algo_name}'",
+        describe_machine(mul_backend),
+        "| Processus | Durée       | Accélération | Efficacité |",
+        "|-----------|-------------|--------------|------------|",
+    ]
+    speedups = iter(scaling_speedups(results))
+    reference = results[0].workers if results else 1
+    for r in results:
+        if r.error is not None:
+            lines.append(f"| {r.workers:>9} | ÉCHEC : {r.error}")
+            continue
+        speedup = next(speedups)
+        efficiency = speedup * reference / r.workers
+        lines.append(
+            f"| {r.workers:>9} | {_format_duration(r.duration):>11} "
+            f"| {speedup:11.2f}x | {efficiency:9.0%}  |"
+        )
+    plateau = scaling_plateau(results)
+    if plateau is None:
+        lines.append("Plafond : non atteint dans les tailles de pool mesurées.")
+    else:
+        lines.append(
+            f"Plafond : au-delà de {plateau} processus, l'accélération progresse de "
+            f"moins de {SCALING_PLATEAU_GAIN - 1:.0%} par palier."
+        )
+    return "\n".join(lines)
@@ -5,20 +5,79 @@ Ce module fournit les fonctionnalités nécessaires pour déterminer le seuil
 optimal à partir duquel la multiplication de grands nombres bénéficie d'une
 exécution parallèle via un `ProcessPoolExecutor`. Il compare les performances
 de la multiplication standard de Python avec une version parallélisée.
+
+Chaque taille peut être bornée par un délai : une taille dont les mesures le
+dépassent (un pool de processus saturé, par exemple) est ignorée plutôt que
+de bloquer toute la calibration, et le résumé la distingue des tailles dont
+les mesures ont échoué.
+
+Les mesures brutes de chaque taille peuvent être conservées
+(`CalibrationPoint`) et écrites dans un fichier JSON avec la description de
+la machine (`write_calibration_points`), pour tracer la courbe complète et
+comparer des machines entre elles.
 """
 
+import dataclasses
+import json
+import os
+import platform
 import time
 import asyncio
 from concurrent.futures import ProcessPoolExecutor
-from .core.multiplication import _parallel_multiply
+from dataclasses import dataclass
+from typing import List, Optional, Tuple
+from .core.multiplication import MUL_BACKEND, _parallel_multiply, _product
+
+# Tailles (en bits) des opérandes mesurés, par ordre croissant.
+CALIBRATION_SIZES = [10000, 20000, 50000, 100000, 200000, 500000]
+
+# Nombre de passes moyennées pour chaque taille et chaque multiplication.
+CALIBRATION_PASSES = 3
+
+# Séparateur horizontal du tableau des mesures.
+_RULE = "----------------------------------------------------------------------"
+
+
+@dataclass(frozen=True)
+class CalibrationPoint:
+    """Mesure d'une taille d'opérandes lors de la calibration.
+
+    Attributes:
+        size_bits (int): La taille, en bits, des deux opérandes.
+        threshold_digits (int): Le seuil équivalent, en chiffres décimaux
+            (l'unité de `--threshold`).
+        standard_ms (Optional[float]): La durée moyenne de la multiplication
+            standard, en millisecondes, ou `None` si la taille n'a pas été
+            mesurée.
+        parallel_ms (Optional[float]): La durée moyenne de la multiplication
+            parallélisée, en millisecondes, ou `None`.
+        error (Optional[str]): La raison pour laquelle la taille n'a pas été
+            mesurée (délai dépassé, échec), le cas échéant.
+    """
 
+    size_bits: int
+    threshold_digits: int
+    standard_ms: Optional[float] = None
+    parallel_ms: Optional[float] = None
+    error: Optional[str] = None
 
-async def _measure_standard_multiply(size_in_bits: int) -> float:
+
+def _bits_to_digits(size_in_bits: int) -> int:
+    """Convertit une taille en bits en nombre de chiffres décimaux."""
+    return int(size_in_bits / 3.3219)
+
+
+async def _measure_standard_multiply(size_in_bits: int, backend: str = "auto") -> float:
     """Mesure la durée d'une multiplication standard.
 
+    Le produit passe par le même aiguillage que dans un processus de travail
+    (GMP au-delà de son seuil, selon `backend`), pour que les deux mesures
+    comparent le même moteur.
+
     Args:
         size_in_bits (int): La taille en bits des deux nombres à multiplier.
             Les nombres sont générés comme étant `(2**size_in_bits) - 1`.
+        backend (str): Le moteur de multiplication (voir `MUL_BACKENDS`).
 
     Returns:
         float: La durée en secondes de l'opération de multiplication.
@@ -27,13 +86,13 @@ async def _measure_standard_multiply(size_in_bits: int) -> float:
     b = (1 << size_in_bits) - 1
 
     start_time = time.perf_counter()
-    _ = a * b
+    _ = _product(a, b, backend)
     end_time = time.perf_counter()
     return end_time - start_time
 
 
 async def _measure_parallel_multiply(
-    executor: ProcessPoolExecutor, size_in_bits: int
+    executor: ProcessPoolExecutor, size_in_bits: int, backend: str = "auto"
 ) -> float:
     """Mesure la durée d'une multiplication parallélisée.
 
@@ -41,6 +100,7 @@ async def _measure_parallel_multiply(
         executor (ProcessPoolExecutor): L'instance du pool de processus à
             utiliser pour l'exécution.
         size_in_bits (int): La taille en bits des deux nombres à multiplier.
+        backend (str): Le moteur de multiplication (voir `MUL_BACKENDS`).
 
     Returns:
         float: La durée en secondes de l'opération, incluant la
@@ -52,12 +112,49 @@ async def _measure_parallel_multiply(
     loop = asyncio.get_running_loop()
 
     start_time = time.perf_counter()
-    await loop.run_in_executor(executor, _parallel_multiply, a, b)
+    await loop.run_in_executor(executor, _parallel_multiply, a, b, backend)
     end_time = time.perf_counter()
     return end_time - start_time
 
 
-async def run_calibration(executor: ProcessPoolExecutor) -> None:
+async def _measure_size(
+    executor: ProcessPoolExecutor, size_in_bits: int, backend: str = "auto"
+) -> Tuple[float, float]:
+    """Mesure les durées moyennes, en ms, des deux multiplications sur `CALIBRATION_PASSES` passes.
+
+    Returns:
+        Tuple[float, float]: Les durées moyennes standard et parallèle.
+    """
+    standard_times = [
+        await _measure_standard_multiply(size_in_bits, backend) for _ in range(CALIBRATION_PASSES)
+    ]
+    parallel_times = [
+        await _measure_parallel_multiply(executor, size_in_bits, backend)
+        for _ in range(CALIBRATION_PASSES)
+    ]
+
+    avg_standard = (sum(standard_times) / len(standard_times)) * 1000  # en ms
+    avg_parallel = (sum(parallel_times) / len(parallel_times)) * 1000  # en ms
+    return avg_standard, avg_parallel
+
+
+def _print_unmeasured(
+    skipped: List[int], failed: List[Tuple[int, str]], size_timeout: Optional[float]
+) -> None:
+    """Résume les tailles ignorées pour délai dépassé et celles en échec."""
+    if skipped:
+        sizes = ", ".join(str(size) for size in skipped)
+        print(f">> Tailles ignorées (délai de {size_timeout:g} s dépassé) : {sizes} bits.")
+    for size, error in failed:
+        print(f">> Échec des mesures pour {size} bits : {error}")
+
+
+async def run_calibration(
+    executor: ProcessPoolExecutor,
+    size_timeout: Optional[float] = None,
+    points: Optional[List[CalibrationPoint]] = None,
+    mul_backend: str = "auto",
+) -> Optional[int]:
     """Exécute le processus de calibration pour trouver le seuil de multiplication.
 
     Cette fonction orchestre une série de tests de performance pour différentes
@@ -65,38 +162,122 @@ async def run_calibration(executor: ProcessPoolExecutor) -> None:
     point où la multiplication parallèle devient plus rapide que la
     multiplication standard.
 
+    Une taille dont les mesures dépassent `size_timeout` est ignorée, de même
+    qu'une taille dont les mesures échouent ; la calibration passe alors à la
+    taille suivante. Le délai n'interrompt les mesures qu'à l'attente d'une
+    multiplication parallélisée : une multiplication standard, exécutée dans
+    le processus courant, va toujours à son terme.
+
+    Sans `points`, la calibration s'arrête au premier point de croisement.
+    Avec `points`, toutes les tailles sont mesurées, pour que la courbe soit
+    complète, et chacune y est ajoutée, mesurée ou non ; le seuil retenu
+    reste le premier point de croisement.
+
     Args:
         executor (ProcessPoolExecutor): L'instance du pool de processus à
             utiliser pour les benchmarks de multiplication parallèle.
+        size_timeout (Optional[float]): Le délai, en secondes, accordé aux
+            mesures de chaque taille, ou `None` pour ne pas les limiter.
+        points (Optional[List[CalibrationPoint]]): La liste qui reçoit la
+            mesure de chaque taille, ou `None` pour ne pas les conserver.
+        mul_backend (str): Le moteur des deux multiplications mesurées (voir
+            `--mul-backend`).
+
+    Returns:
+        Optional[int]: Le seuil optimal, en nombre de chiffres décimaux (l'unité
+        de `--threshold`), ou `None` si aucun point de croisement n'a été trouvé.
     """
     print("Démarrage de la calibration... (cela peut prendre quelques minutes)")
-    print("----------------------------------------------------------------------")
+    print(_RULE)
     print("| Taille (bits) | Temps Standard (ms) | Temps Parallèle (ms) | Ratio S/P |")
-    print("----------------------------------------------------------------------")
-
-    sizes_to_test = [10000, 20000, 50000, 100000, 200000, 500000]
-
-    for size in sizes_to_test:
-        standard_times = [await _measure_standard_multiply(size) for _ in range(3)]
-        parallel_times = [
-            await _measure_parallel_multiply(executor, size) for _ in range(3)
-        ]
+    print(_RULE)
 
-        avg_standard = (sum(standard_times) / len(standard_times)) * 1000  # en ms
-        avg_parallel = (sum(parallel_times) / len(parallel_times)) * 1000  # en ms
+    skipped: List[int] = []
+    failed: List[Tuple[int, str]] = []
+    crossover: Optional[int] = None
+    for size in CALIBRATION_SIZES:
+        try:
+            avg_standard, avg_parallel = await asyncio.wait_for(
+                _measure_size(executor, size, mul_backend), size_timeout
+            )
+        except asyncio.TimeoutError:
+            print(f"| {size:<13} | {'DÉLAI DÉPASSÉ, taille ignorée':<54} |")
+            skipped.append(size)
+            if points is not None:
+                error = f"délai de {size_timeout:g} s dépassé"
+                points.append(CalibrationPoint(size, _bits_to_digits(size), error=error))
+            continue
+        except Exception as e:
+            print(f"| {size:<13} | {'ÉCHEC':<54} |")
+            failed.append((size, f"{type(e).__name__}: {e}"))
+            if points is not None:
+                points.append(CalibrationPoint(size, _bits_to_digits(size), error=failed[-1][1]))
+            continue
 
         ratio = avg_standard / avg_parallel if avg_parallel > 0 else float("inf")
 
         print(
             f"| {size:<13} | {avg_standard:<19.4f} | {avg_parallel:<20.4f} | {ratio:<9.2f} |"
         )
+        if points is not None:
+            points.append(CalibrationPoint(size, _bits_to_digits(size), avg_standard, avg_parallel))
 
-        if avg_parallel < avg_standard:
-            print("----------------------------------------------------------------------")
-            print(f"\n>> Seuil optimal approximatif trouvé autour de {size} bits.")
-            print(f">> (Equivalent à environ {int(size / 3.3219)} chiffres décimaux)")
-            return
+        if crossover is None and avg_parallel < avg_standard:
+            crossover = size
+            if points is None:
+                break
 
-    print("----------------------------------------------------------------------")
+    if crossover is not None:
+        print(_RULE)
+        _print_unmeasured(skipped, failed, size_timeout)
+        print(f"\n>> Seuil optimal approximatif trouvé autour de {crossover} bits.")
+        print(f">> (Equivalent à environ {_bits_to_digits(crossover)} chiffres décimaux)")
+        return _bits_to_digits(crossover)
+
+    print(_RULE)
+    _print_unmeasured(skipped, failed, size_timeout)
     print("\n>> Aucun seuil optimal trouvé dans la plage testée. Le parallélisme")
     print(">> n'est peut-être pas avantageux sur cette machine pour ces tailles.")
+    return None
+
+
+def write_calibration_points(
+    path: str,
+    points: List[CalibrationPoint],
+    threshold: Optional[int],
+    mul_backend: str = MUL_BACKEND,
+) -> None:
+    """Écrit les mesures d'une calibration dans un fichier JSON.
+
+    Le document décrit la machine (`machine` : version et implémentation de
+    Python, système, architecture, nombre de CPU, moteur de multiplication),
+    le nombre de passes moyennées, le seuil retenu et l'ensemble des points
+    (`points`, voir `CalibrationPoint`), y compris les tailles non mesurées.
+
+    Args:
+        path (str): Le chemin du fichier, remplacé s'il existe.
+        points (List[CalibrationPoint]): Les mesures de chaque taille.
+        threshold (Optional[int]): Le seuil retenu, en chiffres décimaux, ou
+            `None` si aucun point de croisement n'a été trouvé.
+        mul_backend (str): Le moteur effectif des multiplications mesurées
+            (voir `resolve_mul_backend`).
+
+    Raises:
+        OSError: Si le fichier ne peut pas être écrit.
+    """
+    document = {
+        "machine": {
+            "python": platform.python_version(),
+            "implementation": platform.python_implementation(),
+            "system": platform.system(),
+            "architecture": platform.machine(),
+            "cpu_count": os.cpu_count(),
+            "mul_backend": mul_backend,
+        },
+        "passes": CALIBRATION_PASSES,
+        "threshold": threshold,
+        "points": [dataclasses.asdict(point) for point in points],
+    }
+    with open(path, "w", encoding="utf-8") as f:
+        json.dump(document, f, indent=2)
+        f.write("\n")
@@ -7,43 +7,978 @@ l'exécution du programme en ligne de commande.
 """
 
 import argparse
+import json
+import os
+import sys
+from typing import Any, Dict, List, Optional, Sequence, Tuple
 
+from ..bench import SCALING_N
+from ..config import get_config_path, load_config
+from ..core.multiplication import MUL_BACKEND, MUL_BACKENDS
+from ..crossover import CROSSOVER_TIMEOUT
+from ..registry import ALGORITHM_REGISTRY
+from ..server import DEFAULT_MAX_N, DEFAULT_SHUTDOWN_GRACE
+from .color import COLOR_MODES
+from .expression import ExpressionError, evaluate_index
+from .output import DEFAULT_TRUNCATE_EDGES, HASH_ALGORITHMS, OUTPUT_FORMATS, group_digits
+from .profile import DEFAULT_PROFILE_PATHS, PROFILE_MODES
+
+# Code de sortie signalant un désaccord avec la valeur de référence ('--expect'),
+# ou entre les algorithmes comparés.
+EXPECT_MISMATCH_EXIT_CODE = 3
+
+# Code de sortie d'un calcul arrêté par le chien de garde ('--watchdog-abort').
+WATCHDOG_EXIT_CODE = 4
+
+# Code de sortie d'un calcul interrompu avant d'épuiser la mémoire
+# ('--memory-fraction').
+MEMORY_EXIT_CODE = 5
+
+# Indice maximal pour lequel '--sum --selfcheck' additionne les termes un à un.
+SUM_CHECK_MAX_N = 100_000
+
+# Bornes du nombre de chiffres significatifs de l'option `--sci-digits`.
+MAX_SCI_DIGITS = 1000
+
+# Nombre de chiffres significatifs de l'option `--ratio` employée sans valeur.
+DEFAULT_RATIO_DIGITS = 50
+
+# Préfixe des variables d'environnement fournissant des valeurs par défaut
+# aux options (`PYFIBONACCI_N`, `PYFIBONACCI_ALGO`, `PYFIBONACCI_MAX_N`...).
+ENV_VAR_PREFIX = "PYFIBONACCI_"
+
+# Valeurs reconnues pour les options booléennes lues dans l'environnement.
+_ENV_BOOLEANS = {
+    **dict.fromkeys(("1", "true", "yes", "on"), True),
+    **dict.fromkeys(("0", "false", "no", "off"), False),
+}
+
+# Sous-commandes de la CLI, chacune avec ses propres options.
+SUBCOMMANDS = (
+    "calc", "serve", "bench", "calibrate", "batch", "range", "decode", "isfib", "gcd", "algos", "request",
+)
+
+# Destinations des options de l'invocation historique qui choisissent un
+# mode, et que les sous-commandes remplacent. L'environnement ne les fournit
+# jamais : une variable ne doit pas changer le mode demandé.
+_MODE_DESTS = (
+    "serve", "range", "batch", "batch_file", "decode", "bench", "calibrate", "scaling", "crossover",
+)
+
+# Nombre maximal d'indices acceptés par l'option `--range`.
+MAX_RANGE_LENGTH = 100_000
+
+# Aide de l'option d'export des mesures de la calibration, commune à
+# l'invocation historique (`--calibrate-json`) et à la sous-commande
+# `calibrate` (`--points`).
+_CALIBRATION_POINTS_HELP = """Écrit dans FICHIER, en JSON, toutes les mesures de
+la calibration (taille, seuil équivalent, durées standard et parallèle, ou
+raison de l'absence de mesure) et la description de la machine, pour tracer
+la courbe complète. Toutes les tailles sont alors mesurées, y compris
+au-delà du point de croisement."""
+
+# Aide de l'option `--scaling`, commune à l'invocation historique et à la
+# sous-commande `bench`.
+_SCALING_HELP = f"""Au lieu du benchmark de débit, mesure le passage à
+l'échelle de la multiplication parallèle : calcule F(N) (par défaut:
+{group_digits(SCALING_N)}) avec un pool de 1, 2, 4, 8... processus, jusqu'à
+'--workers' (par défaut: le nombre de cœurs), et affiche l'accélération et
+l'efficacité parallèle de chaque taille, ainsi que celle à partir de
+laquelle l'accélération plafonne. '--timeout' s'applique à chaque calcul."""
+
+_CROSSOVER_HELP = f"""Au lieu du seuil de parallélisation, recherche les points de
+croisement des algorithmes : chronomètre chacun sur une gamme d'indices (de
+100 à 50 000 000) et indique, par plage d'indices, le plus rapide sur cette
+machine. Le délai de calibration s'applique alors à chaque calcul (par
+défaut: {CROSSOVER_TIMEOUT:g} s) ; un algorithme qui le dépasse n'est plus
+mesuré aux indices suivants."""
 
-def parse_args() -> argparse.Namespace:
-    """Configure et exécute l'analyse des arguments de la ligne de commande.
 
-    Cette fonction met en place un `ArgumentParser` pour gérer toutes les
-    options de l'application, telles que l'indice de Fibonacci `n`, le choix
-    de l'algorithme, les paramètres de performance (timeout, seuil de
-    parallélisation) et les modes spéciaux comme la calibration.
+def _algo_type(value: str) -> str:
+    """Valide un algorithme, `all`, `best`, ou une liste d'algorithmes séparés par des virgules.
+
+    Les noms sont ceux du registre (voir `registry`), extensions comprises.
+    Les doublons d'une liste sont ignorés. `lucas` calculant une autre suite,
+    il ne peut pas figurer dans une liste d'algorithmes à comparer.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
 
     Returns:
-        argparse.Namespace: Un objet contenant les arguments analysés. Chaque
-        argument est accessible en tant qu'attribut de cet objet (par exemple,
-        `args.n`, `args.algo`).
+        str: `all`, `best`, le nom de l'algorithme, ou la liste normalisée
+        (`fast,matrix`).
+
+    Raises:
+        argparse.ArgumentTypeError: Si un nom est inconnu ou si `lucas` figure
+            dans une liste.
     """
-    parser = argparse.ArgumentParser(
-        description="Calculateur de nombres de Fibonacci haute performance en Python.",
-        formatter_class=argparse.RawTextHelpFormatter,
-    )
+    if value in ("all", "best"):
+        return value
+    names = list(dict.fromkeys(name.strip() for name in value.split(",")))
+    for name in names:
+        if name not in ALGORITHM_REGISTRY:
+            choices = ", ".join([*ALGORITHM_REGISTRY, "all", "best"])
+            raise argparse.ArgumentTypeError(f"algorithme inconnu : '{name}' (choix : {choices})")
+    if len(names) > 1 and "lucas" in names:
+        raise argparse.ArgumentTypeError("'lucas' calcule une autre suite et ne peut pas être comparé")
+    return ",".join(names)
+
+
+def _index_type(value: str) -> int:
+    """Valide l'indice de `-n`, nombre ou expression arithmétique.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: L'indice, évalué par `evaluate_index`.
+
+    Raises:
+        argparse.ArgumentTypeError: Si l'expression est malformée ou sort de
+            l'intervalle [0, 2^64 - 1].
+    """
+    try:
+        return evaluate_index(value)
+    except ExpressionError as e:
+        raise argparse.ArgumentTypeError(f"valeur invalide : '{value}' ({e})")
+
+
+def _moduli_type(value: str) -> List[int]:
+    """Valide un module, ou une liste de modules séparés par des virgules.
+
+    Les doublons d'une liste sont ignorés ; le signe des modules est vérifié
+    par le calcul modulaire.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        List[int]: Les modules, dans l'ordre de la liste.
+
+    Raises:
+        argparse.ArgumentTypeError: Si un élément n'est pas un entier.
+    """
+    moduli = []
+    for item in value.split(","):
+        try:
+            moduli.append(int(item))
+        except ValueError:
+            raise argparse.ArgumentTypeError(f"module invalide : '{item.strip()}'")
+    return list(dict.fromkeys(moduli))
+
+
+# Champs d'une requête JSON (sous-commande `request`), associés au validateur
+# de l'option qu'ils remplacent.
+_JSON_REQUEST_FIELDS = {"n": _index_type, "algo": _algo_type, "mod": _moduli_type}
+
+
+def apply_json_request(args: argparse.Namespace, text: str) -> None:
+    """Applique aux options une requête JSON, telle que `{"n": 1000, "algo": "fast"}`.
+
+    Les champs `n`, `algo` et `mod` remplacent les options `-n`, `--algo` et
+    `--mod`, et sont validés comme elles : `n` peut être une expression
+    (`"10^6"`), `algo` et `mod` une liste. Le résultat est émis en JSON.
+
+    Args:
+        args (argparse.Namespace): Les options, modifiées en place.
+        text (str): Le document JSON de la requête.
+
+    Raises:
+        ValueError: Si le document est malformé, n'est pas un objet, omet
+            `n` ou contient un champ inconnu ou invalide.
+    """
+    try:
+        request = json.loads(text)
+    except json.JSONDecodeError as e:
+        raise ValueError(f"Requête JSON malformée : {e}.") from None
+    if not isinstance(request, dict):
+        raise ValueError('La requête JSON doit être un objet, par exemple {"n": 1000}.')
+    unknown = [field for field in request if field not in _JSON_REQUEST_FIELDS]
+    if unknown:
+        raise ValueError(
+            f"Champ inconnu dans la requête JSON : '{unknown[0]}' "
+            f"(champs acceptés : {', '.join(_JSON_REQUEST_FIELDS)})."
+        )
+    if "n" not in request:
+        raise ValueError("Champ 'n' manquant dans la requête JSON.")
+    for field, validate in _JSON_REQUEST_FIELDS.items():
+        if field not in request:
+            continue
+        value = request[field]
+        if isinstance(value, list):
+            value = ",".join(map(str, value))
+        try:
+            if isinstance(value, bool) or not isinstance(value, (int, str)):
+                raise argparse.ArgumentTypeError(f"valeur invalide : {json.dumps(value)}")
+            setattr(args, field, validate(str(value)))
+        except argparse.ArgumentTypeError as e:
+            raise ValueError(f"Champ '{field}' invalide dans la requête JSON : {e}.") from None
+    args.json = True
+
+
+def _base_type(value: str) -> int:
+    """Valide une base de représentation comprise entre 2 et 36.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: La base validée.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un entier entre 2 et 36.
+    """
+    try:
+        base = int(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"base invalide : '{value}'")
+    if not 2 <= base <= 36:
+        raise argparse.ArgumentTypeError("la base doit être comprise entre 2 et 36")
+    return base
+
+
+def _separator_type(value: str) -> str:
+    """Valide un séparateur des milliers.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande : un
+            caractère de ponctuation, ou l'un des noms `space` et `none`.
+
+    Returns:
+        str: Le séparateur (vide pour `none`).
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est ni un nom reconnu ni un
+            unique caractère autre qu'un chiffre ou une lettre.
+    """
+    aliases = {"space": " ", "none": ""}
+    if value in aliases:
+        return aliases[value]
+    if len(value) != 1 or value.isalnum():
+        raise argparse.ArgumentTypeError(
+            f"séparateur invalide : '{value}' (attendu : ',', '.', 'space', 'none'...)"
+        )
+    return value
+
+
+def _sci_digits_type(value: str) -> int:
+    """Valide un nombre de chiffres significatifs pour la notation scientifique.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: Le nombre de chiffres validé.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un entier entre 1
+            et `MAX_SCI_DIGITS`.
+    """
+    try:
+        digits = int(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"nombre de chiffres invalide : '{value}'")
+    if not 1 <= digits <= MAX_SCI_DIGITS:
+        raise argparse.ArgumentTypeError(
+            f"le nombre de chiffres doit être compris entre 1 et {MAX_SCI_DIGITS}"
+        )
+    return digits
+
+
+def _digit_count_type(value: str) -> int:
+    """Valide un nombre de chiffres strictement positif.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: Le nombre de chiffres validé.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un entier strictement positif.
+    """
+    try:
+        digits = int(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"nombre de chiffres invalide : '{value}'")
+    if digits < 1:
+        raise argparse.ArgumentTypeError("le nombre de chiffres doit être au moins 1")
+    return digits
+
+
+def _repeat_type(value: str) -> int:
+    """Valide un nombre d'exécutions strictement positif.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: Le nombre d'exécutions validé.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un entier strictement positif.
+    """
+    try:
+        repeat = int(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"nombre d'exécutions invalide : '{value}'")
+    if repeat < 1:
+        raise argparse.ArgumentTypeError("le nombre d'exécutions doit être au moins 1")
+    return repeat
+
+
+def _workers_type(value: str) -> int:
+    """Valide un nombre de processus de travail strictement positif.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: Le nombre de processus validé.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un entier strictement positif.
+    """
+    try:
+        workers = int(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"nombre de processus invalide : '{value}'")
+    if workers < 1:
+        raise argparse.ArgumentTypeError("le nombre de processus doit être au moins 1")
+    return workers
+
+
+def _mul_backend_type(value: str) -> str:
+    """Valide un moteur de multiplication disponible sur cette installation.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        str: Le moteur, parmi `MUL_BACKENDS`.
+
+    Raises:
+        argparse.ArgumentTypeError: Si le moteur est inconnu, ou si `gmp` est
+            demandé sans que `gmpy2` soit installé.
+    """
+    if value not in MUL_BACKENDS:
+        raise argparse.ArgumentTypeError(
+            f"moteur inconnu : '{value}' (choix : {', '.join(MUL_BACKENDS)})"
+        )
+    if value == "gmp" and MUL_BACKEND != "gmp":
+        raise argparse.ArgumentTypeError(
+            "le moteur 'gmp' requiert la dépendance optionnelle 'gmpy2' "
+            "(pip install pyfibonacci[gmp])"
+        )
+    return value
+
+
+def _scaling_type(value: str) -> int:
+    """Valide l'indice calculé par la mesure du passage à l'échelle.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: L'indice validé.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un entier
+            strictement positif.
+    """
+    try:
+        n = int(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"indice invalide : '{value}'")
+    if n < 1:
+        raise argparse.ArgumentTypeError("l'indice doit être strictement positif")
+    return n
+
+
+def _memory_fraction_type(value: str) -> float:
+    """Valide une fraction de la mémoire disponible, comprise entre 0 et 1 (inclus).
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        float: La fraction validée.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un nombre de
+            l'intervalle [0, 1].
+    """
+    try:
+        fraction = float(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"fraction invalide : '{value}'")
+    if not 0 <= fraction <= 1:
+        raise argparse.ArgumentTypeError("la fraction doit être comprise entre 0 et 1")
+    return fraction
+
+
+def _warn_at_type(value: str) -> float:
+    """Valide une fraction du timeout, comprise entre 0 (inclus) et 1 (exclu).
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        float: La fraction validée.
 
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un nombre de
+            l'intervalle [0, 1[.
+    """
+    try:
+        fraction = float(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"fraction invalide : '{value}'")
+    if not 0 <= fraction < 1:
+        raise argparse.ArgumentTypeError("la fraction doit être comprise entre 0 et 1 (exclu)")
+    return fraction
+
+
+def _interval_type(value: str) -> float:
+    """Valide une durée strictement positive, en secondes.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        float: La durée validée.
+
+    Raises:
+        argparse.ArgumentTypeError: Si la valeur n'est pas un nombre
+            strictement positif.
+    """
+    try:
+        interval = float(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"durée invalide : '{value}'")
+    if not interval > 0:
+        raise argparse.ArgumentTypeError("la durée doit être strictement positive")
+    return interval
+
+
+def _range_type(value: str) -> Tuple[int, int]:
+    """Valide un intervalle d'indices de la forme `a:b`.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        Tuple[int, int]: Les bornes `(a, b)`, incluses.
+
+    Raises:
+        argparse.ArgumentTypeError: Si l'intervalle est malformé, vide ou
+            compte plus de `MAX_RANGE_LENGTH` indices.
+    """
+    try:
+        start_text, stop_text = value.split(":")
+        start, stop = int(start_text), int(stop_text)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"intervalle invalide : '{value}' (attendu : a:b)")
+    if start > stop:
+        raise argparse.ArgumentTypeError("la borne inférieure doit être inférieure ou égale à la borne supérieure")
+    if stop - start + 1 > MAX_RANGE_LENGTH:
+        raise argparse.ArgumentTypeError(f"l'intervalle ne peut pas dépasser {MAX_RANGE_LENGTH} indices")
+    return (start, stop)
+
+
+def _digit_range_type(value: str) -> Tuple[int, int]:
+    """Valide un intervalle de rangs de chiffres de la forme `début:fin`.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        Tuple[int, int]: Les rangs `(début, fin)`, inclus, à partir de 1.
+
+    Raises:
+        argparse.ArgumentTypeError: Si l'intervalle est malformé, vide ou
+            commence avant le premier chiffre.
+    """
+    try:
+        start_text, end_text = value.split(":")
+        start, end = int(start_text), int(end_text)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"intervalle de chiffres invalide : '{value}' (attendu : début:fin)")
+    if start < 1:
+        raise argparse.ArgumentTypeError("les rangs des chiffres commencent à 1")
+    if start > end:
+        raise argparse.ArgumentTypeError("le rang de début doit être inférieur ou égal au rang de fin")
+    return (start, end)
+
+
+def _index_pair_type(value: str) -> Tuple[int, int]:
+    """Valide un couple d'indices de la forme `m:n`.
+
+    Args:
+        value (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        Tuple[int, int]: Les indices `(m, n)`.
+
+    Raises:
+        argparse.ArgumentTypeError: Si le couple est malformé.
+    """
+    try:
+        m_text, n_text = value.split(":")
+        return (int(m_text), int(n_text))
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"couple d'indices invalide : '{value}' (attendu : m:n)")
+
+
+class _InvalidDefault:
+    """Défaut tiré d'une variable d'environnement invalide.
+
+    L'erreur n'est signalée que si l'option n'est pas donnée explicitement
+    (voir `_check_defaults`) : une option explicite l'emporte sur une
+    variable malformée comme sur une variable valide.
+    """
+
+    def __init__(self, message: str) -> None:
+        self.message = message
+
+
+def _env_value(action: argparse.Action, name: str, raw: str) -> Any:
+    """Valide la valeur `raw` de la variable `name` pour l'option `action`.
+
+    Raises:
+        ValueError: Si la valeur est invalide pour cette option.
+    """
+    if action.nargs == 0:  # Option booléenne ('store_true').
+        if raw.strip().lower() not in _ENV_BOOLEANS:
+            raise ValueError(f"{name} : booléen attendu (1/0, true/false...), '{raw}' reçu")
+        return _ENV_BOOLEANS[raw.strip().lower()]
+    try:
+        value = action.type(raw) if action.type else raw
+    except argparse.ArgumentTypeError as e:
+        raise ValueError(f"{name} : {e}") from e
+    except ValueError as e:
+        raise ValueError(f"{name} : valeur invalide : '{raw}'") from e
+    if action.choices is not None and value not in action.choices:
+        choices = ", ".join(map(str, action.choices))
+        raise ValueError(f"{name} : '{raw}' n'est pas un choix valide ({choices})")
+    return value
+
+
+def _env_defaults(parser: argparse.ArgumentParser) -> Dict[str, Any]:
+    """Lit les valeurs par défaut des options dans les variables d'environnement.
+
+    Chaque option est associée à la variable `ENV_VAR_PREFIX` suivie de son
+    nom en majuscules (`--max-n` : `PYFIBONACCI_MAX_N`). La valeur est
+    validée comme sur la ligne de commande, par le même type et les mêmes
+    choix ; les options booléennes acceptent `1`/`0`, `true`/`false`,
+    `yes`/`no` et `on`/`off`. Une valeur invalide devient un
+    `_InvalidDefault`, signalé seulement si l'option n'est pas donnée. Les
+    options qui choisissent un mode (`_MODE_DESTS`) sont ignorées.
+
+    Args:
+        parser (argparse.ArgumentParser): Le parseur dont les options sont lues.
+
+    Returns:
+        Dict[str, Any]: Les valeurs trouvées, indexées par destination.
+    """
+    defaults: Dict[str, Any] = {}
+    for action in parser._actions:
+        if not action.option_strings or action.default == argparse.SUPPRESS:
+            continue
+        if action.dest in _MODE_DESTS:
+            continue
+        name = ENV_VAR_PREFIX + action.dest.upper()
+        raw = os.environ.get(name)
+        if raw is None:
+            continue
+        try:
+            defaults[action.dest] = _env_value(action, name, raw)
+        except ValueError as e:
+            defaults[action.dest] = _InvalidDefault(str(e))
+    return defaults
+
+
+def _check_defaults(parser: argparse.ArgumentParser, args: argparse.Namespace) -> argparse.Namespace:
+    """Signale la première variable d'environnement invalide restée en vigueur.
+
+    Args:
+        parser (argparse.ArgumentParser): Le parseur qui signale l'erreur.
+        args (argparse.Namespace): Les arguments analysés.
+
+    Returns:
+        argparse.Namespace: `args`, si aucune valeur invalide n'a été retenue.
+    """
+    for value in vars(args).values():
+        if isinstance(value, _InvalidDefault):
+            parser.error(value.message)
+    return args
+
+
+def _add_calculation_arguments(parser: argparse.ArgumentParser) -> None:
+    """Ajoute les options du calcul de F(n) (sous-commande `calc`)."""
     parser.add_argument(
         "-n",
-        type=int,
+        type=_index_type,
         required=False,
-        help="L'indice du nombre de Fibonacci à calculer.",
+        help="""L'indice du nombre de Fibonacci à calculer. Les indices négatifs
+sont acceptés, via F(-n) = (-1)^(n+1) * F(n). L'indice peut aussi être une
+expression sur des entiers positifs, avec + - * ^ et des parenthèses
+(par exemple '2^30' ou '10^8+1'), dont la valeur doit tenir sur 64 bits.""",
     )
 
     parser.add_argument(
-        "--algo",
+        "--repeat",
+        type=_repeat_type,
+        default=1,
+        metavar="N",
+        help="""Exécute chaque algorithme N fois et rapporte les durées minimale,
+médiane et moyenne au lieu d'une mesure unique (par défaut: 1).""",
+    )
+
+    parser.add_argument(
+        "--warmup",
+        action="store_true",
+        help="""Exécute un petit calcul jetable par algorithme avant la mesure,
+pour que le premier exécuté ne paie pas seul le démarrage des processus et
+le chargement des caches. Ses résultats sont ignorés.""",
+    )
+
+    parser.add_argument(
+        "--sequential",
+        action="store_true",
+        help="""Avec '--algo all' ou une liste, exécute les algorithmes l'un après
+l'autre plutôt que simultanément : chacun dispose seul du processeur et du
+pool de processus, et sa durée est comparable à celle d'une exécution
+isolée.""",
+    )
+
+    modular = parser.add_mutually_exclusive_group()
+    modular.add_argument(
+        "--mod",
+        type=_moduli_type,
+        default=None,
+        metavar="MOD[,MOD...]",
+        help="""Calcule F(n) modulo la valeur donnée (entier strictement positif)
+au lieu du nombre complet. Utilise le 'Fast Doubling' modulaire. Une liste
+de modules séparés par des virgules (par exemple pour une reconstruction
+par le théorème des restes chinois) donne un résidu par module.""",
+    )
+    modular.add_argument(
+        "--tail",
+        type=int,
+        default=None,
+        metavar="K",
+        help="""Affiche uniquement les K derniers chiffres décimaux de F(n),
+calculés modulo 10^K sans construire le nombre complet.""",
+    )
+    modular.add_argument(
+        "--edges",
+        type=int,
+        default=None,
+        metavar="K",
+        help="""Affiche uniquement les K premiers et les K derniers chiffres
+décimaux de F(n), ainsi que son nombre de chiffres, sans construire le nombre
+complet : les premiers par la formule de Binet, les derniers modulo 10^K.""",
+    )
+    modular.add_argument(
+        "--digit-range",
+        type=_digit_range_type,
+        default=None,
+        metavar="DÉBUT:FIN",
+        help="""Affiche uniquement les chiffres décimaux de rang DÉBUT à FIN de
+F(n), inclus, comptés à partir de 1 depuis le plus significatif. F(n) est
+calculé, mais sa représentation décimale n'est jamais construite ; un
+intervalle qui dépasse la taille prévue de F(n) est refusé avant le calcul.""",
+    )
+    modular.add_argument(
+        "--ratio",
+        type=_sci_digits_type,
+        nargs="?",
+        const=DEFAULT_RATIO_DIGITS,
+        default=None,
+        metavar="K",
+        help=f"""Affiche le rapport F(n)/F(n-1) avec K chiffres significatifs
+(par défaut: {DEFAULT_RATIO_DIGITS}, au plus {MAX_SCI_DIGITS}), à côté du nombre d'or φ dont
+il s'approche, et l'écart entre les deux. Le couple (F(n-1), F(n)) est
+obtenu par un seul 'Fast Doubling' (n doit valoir au moins 2).""",
+    )
+    modular.add_argument(
+        "--scaled",
+        type=_sci_digits_type,
+        default=None,
+        metavar="K",
+        help=f"""Affiche F(n) en notation scientifique normalisée, mantisse de
+K chiffres significatifs (au plus {MAX_SCI_DIGITS}) et exposant décimal (en mode
+JSON, champs 'mantissa' et 'exponent'), sans construire le nombre complet :
+les chiffres proviennent de la formule de Binet et sont tronqués, non
+arrondis.""",
+    )
+
+    parser.add_argument(
+        "--estimate",
+        action="store_true",
+        help="""N'effectue pas le calcul : affiche le nombre de chiffres, la
+mémoire de pointe et la durée prévus pour F(n), estimés à partir d'un court
+échantillon de mesures sur cette machine.""",
+    )
+
+    parser.add_argument(
+        "--sum",
+        action="store_true",
+        help=f"""Calcule la somme F(1) + F(2) + ... + F(n), par l'identité
+F(1) + ... + F(n) = F(n+2) - 1 : un seul calcul de F(n+2), sans additionner
+les termes (n doit être positif ou nul). Avec '--selfcheck', la somme est
+aussi calculée terme à terme pour n ≤ {SUM_CHECK_MAX_N}.""",
+    )
+
+    parser.add_argument(
+        "--force",
+        action="store_true",
+        help="""Lance le calcul même si la mémoire de pointe prévue pour F(n)
+dépasse la mémoire disponible (le calcul est sinon refusé avant toute
+allocation), et le poursuit sans contrôler ses allocations (voir
+'--memory-fraction').""",
+    )
+
+    parser.add_argument(
+        "--memory-fraction",
+        type=_memory_fraction_type,
+        default=0.9,
+        metavar="FRACTION",
+        help=f"""Fraction de la mémoire disponible que les produits d'une étape
+du calcul peuvent allouer. Avant chacune des grandes étapes, leur taille est
+comparée à la mémoire alors disponible : au-delà, le calcul est interrompu
+avec le code de sortie {MEMORY_EXIT_CODE}, plutôt que d'être tué par le système
+(par défaut: 0.9 ; 0 désactive le contrôle).""",
+    )
+
+    parser.add_argument(
+        "--sci-digits",
+        type=_sci_digits_type,
+        default=None,
+        metavar="K",
+        help=f"""Affiche aussi le résultat en notation scientifique, arrondi à K
+chiffres significatifs (de 1 à {MAX_SCI_DIGITS}), tous exacts.""",
+    )
+
+    parser.add_argument(
+        "--verify",
+        action="store_true",
+        help="""Vérifie le résultat en comparant ses 15 premiers chiffres à la
+formule de Binet, évaluée en précision arbitraire. Le code de sortie est
+non nul en cas de désaccord.""",
+    )
+
+    parser.add_argument(
+        "--selfcheck",
+        action="store_true",
+        help=f"""Vérifie le résultat complet par l'identité de Cassini,
+F(n-1)·F(n+1) - F(n)² = (-1)^n, au prix d'un second calcul par "Fast
+Doubling". Détecte une corruption silencieuse d'un produit ; en cas d'échec,
+le code de sortie vaut {EXPECT_MISMATCH_EXIT_CODE}.""",
+    )
+
+    parser.add_argument(
+        "-d",
+        "--details",
+        action="store_true",
+        help="Affiche des détails supplémentaires sur l'exécution, comme une barre de progression.",
+    )
+
+    parser.add_argument(
+        "--expect",
         type=str,
+        default=None,
+        metavar="FICHIER",
+        help=f"""Compare le résultat à la valeur décimale de référence contenue
+dans le fichier donné (les blancs sont ignorés). En cas de désaccord, la
+position du premier chiffre qui diffère est affichée et le code de sortie
+vaut {EXPECT_MISMATCH_EXIT_CODE}.""",
+    )
+
+    parser.add_argument(
+        "--cache-reference",
+        action="store_true",
+        help="""Enregistre le résultat comme référence de F(n) dans le cache
+(voir '--reference-dir'), pour valider plus tard un autre algorithme avec
+'--compare-cached' sans tout recalculer. Refusé si les algorithmes ne
+concordent pas ; une référence existante est remplacée.""",
+    )
+
+    parser.add_argument(
+        "--compare-cached",
+        action="store_true",
+        help=f"""Compare le résultat de chaque algorithme à la référence de F(n)
+enregistrée par '--cache-reference'. En cas de désaccord, la position du
+premier chiffre qui diffère est affichée et le code de sortie vaut
+{EXPECT_MISMATCH_EXIT_CODE} ; une référence absente est une erreur.""",
+    )
+
+    parser.add_argument(
+        "--reference-dir",
+        type=str,
+        default=None,
+        metavar="RÉPERTOIRE",
+        help="""Répertoire du cache des références (par défaut:
+$XDG_CACHE_HOME/pyfibonacci/references, ou ~/.cache/pyfibonacci/references).""",
+    )
+
+    parser.add_argument(
+        "-o",
+        "--output",
+        type=str,
+        default=None,
+        metavar="FICHIER",
+        help="""Écrit aussi le résultat dans le fichier donné, au format choisi
+par '--format'.""",
+    )
+
+    parser.add_argument(
+        "--format",
+        choices=OUTPUT_FORMATS,
+        default="dec",
+        help="""Format du fichier écrit par '--output' :
+- 'dec': Représentation décimale, suivie d'un saut de ligne (par défaut).
+- 'binary': En-tête suivi de la magnitude en octets gros-boutistes, rapide
+  à écrire et à relire (voir '--decode').""",
+    )
+
+    parser.add_argument(
+        "--csv",
+        type=str,
+        default=None,
+        metavar="FICHIER",
+        help="""Ajoute une ligne par algorithme au journal CSV donné (date,
+machine, nombre de CPU, version de Python, algorithme, n, durée en
+nanosecondes, chiffres, bits, issue). Le fichier est créé avec son en-tête
+s'il n'existe pas, ce qui permet d'agréger plusieurs exécutions.""",
+    )
+
+    parser.add_argument(
+        "--compare-only",
+        action="store_true",
+        help="""N'affiche ni ne convertit la valeur calculée : seuls les statuts,
+les durées et le verdict de concordance des algorithmes sont rapportés (utile
+avec '--algo all' pour de très grands n). Contrairement à '-q', le résumé de
+la comparaison reste affiché.""",
+    )
+
+    parser.add_argument(
+        "--digitsum",
+        action="store_true",
+        help="""Affiche la somme des chiffres décimaux du résultat et sa racine
+numérique (en mode JSON, champs 'digit_sum' et 'digital_root').""",
+    )
+
+    parser.add_argument(
+        "--hash",
+        type=str,
+        default=None,
+        choices=HASH_ALGORITHMS,
+        help="""Affiche l'empreinte du résultat (en mode JSON, champs 'hash' et
+'hash_algorithm'), pour vérifier que deux exécutions ou deux machines
+s'accordent sans échanger le nombre. Elle porte sur l'export binaire
+('--format binary') et ne demande pas de conversion décimale.""",
+    )
+
+    parser.add_argument(
+        "--progress",
+        type=str,
+        default=None,
+        choices=["bar", "plain", "json", "none", "multi"],
+        help="""Mode d'affichage de la progression, sur stderr (implique son
+activation, comme '-d') :
+- 'bar': Barre de progression interactive (par défaut avec '-d').
+- 'plain': Lignes de pourcentage ('37%%'), sans séquences ANSI.
+- 'json': Un objet JSON par ligne ('{"percent": 37.5}').
+- 'none': Aucun affichage.
+- 'multi': Avec '--algo all' ou une liste, une barre par algorithme.""",
+    )
+
+    parser.add_argument(
+        "--trace",
+        type=str,
+        nargs="?",
+        const="-",
+        default=None,
+        metavar="FICHIER",
+        help="""Journalise chaque étape du 'Fast Doubling' (un bit de n) sous la
+forme d'un objet JSON par ligne : rang de l'étape, bit traité, taille en bits
+des opérandes, voie de multiplication ('native', 'gmp' ou 'parallel') et
+durée en secondes. Le journal est écrit dans le fichier donné, ou sur stderr
+si aucun fichier n'est indiqué.""",
+    )
+
+    parser.add_argument(
+        "--warn-at",
+        type=_warn_at_type,
+        default=0.8,
+        metavar="FRACTION",
+        help="""Fraction du timeout à laquelle un calcul dont l'avancement laisse
+prévoir un dépassement est signalé sur stderr, pour relancer avec un délai
+plus long (par défaut: 0.8 ; 0 désactive l'avertissement).""",
+    )
+
+    parser.add_argument(
+        "--profile",
+        type=str,
+        default=None,
+        choices=PROFILE_MODES,
+        help=f"""Profile le calcul et écrit le profil sur disque (seul le
+processus principal est profilé) :
+- 'cpu': profil cProfile, au format pstats ({DEFAULT_PROFILE_PATHS["cpu"]}).
+- 'mem': instantané tracemalloc des allocations vivantes en fin de calcul
+  ({DEFAULT_PROFILE_PATHS["mem"]}).
+- 'trace': trace d'exécution au format « Trace Event », pour chrome://tracing
+  ou Perfetto ({DEFAULT_PROFILE_PATHS["trace"]}).""",
+    )
+
+    parser.add_argument(
+        "--profile-out",
+        type=str,
+        default=None,
+        metavar="FICHIER",
+        help="Avec --profile, fichier de destination du profil.",
+    )
+
+    parser.add_argument(
+        "--watchdog",
+        type=_interval_type,
+        default=None,
+        metavar="SECONDES",
+        help="""Diagnostic d'un calcul figé : si le calcul ne donne aucun signe de
+vie (étape terminée, multiplication déléguée revenue) pendant la durée
+indiquée, les piles d'exécution des threads et des tâches asyncio sont
+écrites sur stderr. La durée doit dépasser celle de la plus longue étape
+calculée dans le processus courant ; l'algorithme itératif, qui ne rapporte
+pas son avancement, n'est pas surveillé utilement.""",
+    )
+
+    parser.add_argument(
+        "--watchdog-abort",
+        action="store_true",
+        help=f"""Avec '--watchdog', arrête le processus après le rapport, avec le
+code de sortie {WATCHDOG_EXIT_CODE}.""",
+    )
+
+
+def _add_engine_arguments(parser: argparse.ArgumentParser) -> None:
+    """Ajoute les options du moteur de calcul : algorithme, timeout, multiplication."""
+    parser.add_argument(
+        "--algo",
+        type=_algo_type,
         default="fast",
-        choices=["iterative", "matrix", "fast", "all"],
+        metavar="{" + ",".join([*ALGORITHM_REGISTRY, "all", "best"]) + "}",
         help="""Spécifie l'algorithme à utiliser :
 - 'iterative': Méthode itérative simple.
 - 'matrix': Méthode d'exponentiation matricielle.
 - 'fast': Méthode du 'Fast Doubling' (par défaut).
-- 'all': Exécute tous les algorithmes disponibles en parallèle.""",
+- 'lucas': Calcule le nombre de Lucas L(n) par 'Fast Doubling'.
+- 'all': Exécute tous les algorithmes de Fibonacci en parallèle.
+- 'best': Alias de l'algorithme le plus rapide (actuellement 'fast').
+Une liste séparée par des virgules ('fast,matrix') exécute et compare les
+seuls algorithmes de Fibonacci indiqués. Les algorithmes enregistrés par des
+extensions (voir 'pyfibonacci.registry') sont également acceptés.""",
     )
 
     parser.add_argument(
@@ -62,10 +997,252 @@ parallélisée est utilisée (par défaut: 10000).""",
     )
 
     parser.add_argument(
-        "-d",
-        "--details",
+        "--mul-algo",
+        type=str,
+        default="auto",
+        choices=["auto", "adaptive", "native", "parallel"],
+        help="""Stratégie de multiplication des grands nombres :
+- 'auto': Parallélise au-delà du seuil '--threshold' (par défaut).
+- 'adaptive': Comme 'auto', mais calcule dans le processus courant lorsque
+  tous les processus du pool sont occupés (mode 'all', serveur chargé).
+- 'native': Multiplie toujours dans le processus courant.
+- 'parallel': Délègue toujours au pool de processus.""",
+    )
+
+    parser.add_argument(
+        "--mul-backend",
+        type=_mul_backend_type,
+        default="auto",
+        metavar="{" + ",".join(MUL_BACKENDS) + "}",
+        help=f"""Moteur des produits de grande taille, pour comparer leurs
+performances sans réinstaller :
+- 'auto': GMP si 'gmpy2' est installé, l'arithmétique de CPython sinon
+  (par défaut ; ici : '{MUL_BACKEND}').
+- 'native': L'arithmétique de CPython (Karatsuba), même si 'gmpy2' est
+  installé.
+- 'gmp': GMP, via 'gmpy2' ; refusé si la dépendance est absente.""",
+    )
+
+    parser.add_argument(
+        "--workers",
+        type=_workers_type,
+        default=None,
+        metavar="N",
+        help="""Nombre de processus du pool auquel les multiplications sont
+déléguées, pour limiter les cœurs utilisés sur une machine partagée (par
+défaut: un par cœur).""",
+    )
+
+    parser.add_argument(
+        "--no-lut",
+        dest="use_lookup_table",
+        action="store_false",
+        help="""Calcule les petits indices par l'algorithme au lieu de les lire
+dans la table précalculée (L(0)..L(92) pour 'lucas'), pour mesurer le coût
+du noyau sur de petites tailles.""",
+    )
+
+
+def _add_report_arguments(parser: argparse.ArgumentParser) -> None:
+    """Ajoute les options de présentation des rapports : JSON, couleurs, séparateur."""
+    parser.add_argument(
+        "--json",
         action="store_true",
-        help="Affiche des détails supplémentaires sur l'exécution, comme une barre de progression.",
+        help="""Émet les résultats sous la forme d'un unique objet JSON sur la
+sortie standard (indice, durée et statut de chaque algorithme, nombre de
+chiffres, longueur en bits et valeur). La progression reste sur stderr.""",
+    )
+
+    parser.add_argument(
+        "--color",
+        choices=COLOR_MODES,
+        default="auto",
+        help="""Coloration des statuts et des barres de progression : 'auto'
+(par défaut) ne colore que si la sortie est un terminal et que la variable
+NO_COLOR n'est pas définie, 'always' et 'never' l'imposent.""",
+    )
+
+    parser.add_argument(
+        "--thousands-sep",
+        type=_separator_type,
+        default=" ",
+        metavar="SEP",
+        help="""Séparateur des milliers des nombres affichés dans les messages
+(nombre de chiffres, taille en bits...) : un caractère tel que ',' ou '.',
+'space' (par défaut) ou 'none'.""",
+    )
+
+
+def _add_value_arguments(parser: argparse.ArgumentParser) -> None:
+    """Ajoute les options d'affichage des valeurs calculées : base, mode silencieux."""
+    parser.add_argument(
+        "--base",
+        type=_base_type,
+        default=10,
+        help="Base (de 2 à 36) dans laquelle le résultat est affiché (par défaut: 10).",
+    )
+
+    parser.add_argument(
+        "--trunc-limit",
+        type=_digit_count_type,
+        default=None,
+        metavar="N",
+        help="""Tronque le résultat affiché s'il compte plus de N chiffres : seuls
+ses premiers et derniers chiffres (voir '--trunc-edges') et son nombre de
+chiffres sont affichés. Sans effet sur '-q', '--json' et '--output', qui
+restent complets (par défaut: le résultat est affiché en entier).""",
+    )
+
+    parser.add_argument(
+        "--trunc-edges",
+        type=_digit_count_type,
+        default=DEFAULT_TRUNCATE_EDGES,
+        metavar="K",
+        help=f"""Avec '--trunc-limit', nombre de chiffres affichés à chaque
+extrémité du résultat tronqué ; 2K doit rester inférieur à la limite (par
+défaut: {DEFAULT_TRUNCATE_EDGES}).""",
+    )
+
+    parser.add_argument(
+        "-q",
+        "--quiet",
+        action="store_true",
+        help="""N'écrit que la valeur calculée sur la sortie standard, sans
+bannière, barre de progression ni résumé. Les erreurs restent sur stderr.""",
+    )
+
+
+def _add_server_arguments(parser: argparse.ArgumentParser) -> None:
+    """Ajoute les options du serveur HTTP (sous-commande `serve`)."""
+    parser.add_argument(
+        "--max-n",
+        type=int,
+        default=DEFAULT_MAX_N,
+        help=f"""Avec --serve, valeur maximale de |n| acceptée ; au-delà, le
+serveur répond 400 (par défaut: {DEFAULT_MAX_N}).""",
+    )
+
+    parser.add_argument(
+        "--cache-size",
+        type=int,
+        default=0,
+        metavar="N",
+        help="""Avec --serve, conserve les N derniers résultats calculés (cache
+LRU par indice et algorithme) pour répondre instantanément aux requêtes
+répétées (par défaut: 0, cache désactivé).""",
+    )
+
+    parser.add_argument(
+        "--cache-idle",
+        type=_interval_type,
+        default=None,
+        metavar="SECONDES",
+        help="""Avec --cache-size, vide le cache lorsqu'il n'a pas été consulté
+depuis SECONDES, pour rendre au système la mémoire des résultats conservés
+pendant les périodes creuses. En contrepartie, les premières requêtes
+suivantes recalculent leurs valeurs (par défaut: le cache n'est jamais vidé).""",
+    )
+
+    parser.add_argument(
+        "--shutdown-grace",
+        type=_interval_type,
+        default=DEFAULT_SHUTDOWN_GRACE,
+        metavar="SECONDES",
+        help=f"""Avec --serve, délai laissé aux calculs en cours pour aboutir
+lorsque le serveur reçoit SIGTERM : il cesse d'accepter de nouveaux calculs
+('/readyz' répond 503), puis annule ceux qui n'ont pas abouti au terme du
+délai (par défaut: {DEFAULT_SHUTDOWN_GRACE:g}).""",
+    )
+
+
+def _apply_defaults(parser: argparse.ArgumentParser) -> None:
+    """Substitue aux défauts codés en dur ceux de la configuration et de l'environnement.
+
+    Les valeurs du fichier de configuration, puis celles des variables
+    d'environnement, remplacent les défauts codés en dur, mais restent
+    surchargées par les options explicites. Une variable malformée n'est
+    signalée qu'à l'analyse, si l'option correspondante n'est pas donnée.
+
+    Args:
+        parser (argparse.ArgumentParser): Le parseur dont les défauts sont
+            remplacés.
+    """
+    try:
+        parser.set_defaults(**load_config())
+        parser.set_defaults(**_env_defaults(parser))
+    except ValueError as e:
+        parser.error(str(e))
+
+
+def _build_legacy_parser(apply_overrides: bool = True) -> argparse.ArgumentParser:
+    """Construit le parseur de l'invocation historique, sans sous-commande.
+
+    Toutes les options y figurent à plat, les modes étant choisis par des
+    options (`--serve`, `--bench`...). Il fournit aussi les valeurs par défaut
+    de toutes les options aux sous-commandes, qui n'en déclarent qu'une
+    partie.
+
+    Args:
+        apply_overrides (bool): Si faux, les défauts restent ceux codés en
+            dur, sans la configuration ni l'environnement.
+
+    Returns:
+        argparse.ArgumentParser: Le parseur, défauts de la configuration et
+        de l'environnement compris si `apply_overrides` est vrai.
+    """
+    parser = argparse.ArgumentParser(
+        description="Calculateur de nombres de Fibonacci haute performance en Python.",
+        formatter_class=argparse.RawTextHelpFormatter,
+    )
+    _add_calculation_arguments(parser)
+    _add_engine_arguments(parser)
+    _add_report_arguments(parser)
+    _add_value_arguments(parser)
+    _add_server_arguments(parser)
+
+    parser.add_argument(
+        "--serve",
+        type=str,
+        default=None,
+        metavar="ADRESSE",
+        help="""Démarre un serveur HTTP exposant 'GET /fib?n=1000&algo=fast'
+(par exemple '--serve :8080'). La réponse est un objet JSON
+{n, algorithm, value, digits, duration_ms}.""",
+    )
+
+    parser.add_argument(
+        "--range",
+        type=_range_type,
+        default=None,
+        metavar="A:B",
+        help="""Calcule F(a), F(a+1), ..., F(b) et écrit une ligne 'n<TAB>valeur'
+par indice (un objet JSON par ligne avec --json). Seuls F(a) et F(a+1) sont
+calculés par 'Fast Doubling' ; les termes suivants sont obtenus par additions.
+Pour une borne négative, utiliser la forme '--range=-5:3'.""",
+    )
+
+    parser.add_argument(
+        "--batch",
+        action="store_true",
+        help="""Lit des indices sur l'entrée standard, un par ligne, et écrit
+une ligne 'n<TAB>valeur' par indice (un objet JSON par ligne avec --json).""",
+    )
+
+    parser.add_argument(
+        "--batch-file",
+        type=str,
+        default=None,
+        metavar="FICHIER",
+        help="Comme --batch, mais lit les indices depuis le fichier donné.",
+    )
+
+    parser.add_argument(
+        "--decode",
+        type=str,
+        default=None,
+        metavar="FICHIER",
+        help="""Relit un fichier écrit avec '--format binary' et affiche ses
+métadonnées (signe, taille en bits, nombre de chiffres), sans calcul.""",
     )
 
     parser.add_argument(
@@ -82,4 +1259,287 @@ parallélisée est utilisée (par défaut: 10000).""",
         help="Lance une session de calibration pour déterminer les seuils optimaux.",
     )
 
-    return parser.parse_args()
+    parser.add_argument(
+        "--bench",
+        action="store_true",
+        help="""Mesure le débit de la machine : calcule F(n) pour n = 1 000 000,
+10 000 000 et 100 000 000 avec l'algorithme choisi et affiche les chiffres et
+bits produits par seconde ainsi qu'un score composite. '--timeout' s'applique
+à chaque indice ; les indices suivant un échec ne sont pas calculés.""",
+    )
+
+    parser.add_argument(
+        "--scaling",
+        type=_scaling_type,
+        nargs="?",
+        const=SCALING_N,
+        default=None,
+        metavar="N",
+        help=_SCALING_HELP,
+    )
+
+    parser.add_argument(
+        "--calibrate-save",
+        action="store_true",
+        help=f"""Avec --calibrate, enregistre le seuil optimal trouvé dans le
+fichier de configuration ({get_config_path()}). Il sert ensuite de
+valeur par défaut à '--threshold'.""",
+    )
+
+    parser.add_argument(
+        "--calibrate-json",
+        type=str,
+        default=None,
+        metavar="FICHIER",
+        help=_CALIBRATION_POINTS_HELP,
+    )
+
+    parser.add_argument(
+        "--calibrate-timeout",
+        type=_interval_type,
+        default=None,
+        metavar="SECONDES",
+        help="""Avec --calibrate, délai accordé aux mesures de chaque taille :
+une taille qui le dépasse est ignorée, et signalée comme telle dans le
+résumé, plutôt que de bloquer la calibration (par défaut: aucun).""",
+    )
+
+    parser.add_argument(
+        "--crossover",
+        action="store_true",
+        help=_CROSSOVER_HELP,
+    )
+
+    # La recherche inverse, l'identité du PGCD, la description des
+    # algorithmes et les requêtes JSON n'existent que sous forme de
+    # sous-commandes (`isfib`, `gcd`, `algos`, `request`).
+    parser.set_defaults(command=None, is_fib=None, gcd=None, algo_info=False, stdin_json=False)
+    if apply_overrides:
+        _apply_defaults(parser)
+    return parser
+
+
+def _build_parser() -> argparse.ArgumentParser:
+    """Construit le parseur des sous-commandes (`calc`, `serve`, `bench`...).
+
+    Chaque sous-commande ne déclare que les options qui la concernent ; les
+    options des modes (`--bench`, `--calibrate`...) de l'invocation
+    historique y sont remplacées par des valeurs par défaut.
+
+    Returns:
+        argparse.ArgumentParser: Le parseur, défauts de la configuration et
+        de l'environnement compris.
+    """
+    parser = argparse.ArgumentParser(
+        description="Calculateur de nombres de Fibonacci haute performance en Python.",
+        formatter_class=argparse.RawTextHelpFormatter,
+        epilog="""L'invocation sans sous-commande ('pyfibonacci -n 100', 'pyfibonacci --serve :8080'...)
+reste acceptée pour cette version, mais est dépréciée.""",
+    )
+    parser.add_argument(
+        "-v",
+        "--version",
+        action="version",
+        version="%(prog)s 0.1.0",
+        help="Affiche la version du programme et quitte.",
+    )
+    commands = parser.add_subparsers(dest="command", metavar="COMMANDE", required=True)
+
+    def command(name: str, summary: str) -> argparse.ArgumentParser:
+        return commands.add_parser(
+            name, help=summary, description=summary, formatter_class=argparse.RawTextHelpFormatter
+        )
+
+    calc = command("calc", "Calcule F(n) avec un ou plusieurs algorithmes.")
+    _add_calculation_arguments(calc)
+    _add_engine_arguments(calc)
+    _add_report_arguments(calc)
+    _add_value_arguments(calc)
+
+    serve = command("serve", "Démarre le serveur HTTP de calcul.")
+    serve.add_argument(
+        "serve",
+        metavar="ADRESSE",
+        help="""Adresse d'écoute, par exemple ':8080'. Le serveur expose
+'GET /fib?n=1000&algo=fast', dont la réponse est un objet JSON
+{n, algorithm, value, digits, duration_ms}.""",
+    )
+    _add_server_arguments(serve)
+    _add_engine_arguments(serve)
+
+    bench = command("bench", "Mesure le débit de la machine pour F(10^6), F(10^7) et F(10^8).")
+    bench.add_argument(
+        "--scaling",
+        type=_scaling_type,
+        nargs="?",
+        const=SCALING_N,
+        default=None,
+        metavar="N",
+        help=_SCALING_HELP,
+    )
+    _add_engine_arguments(bench)
+    _add_report_arguments(bench)
+    bench.set_defaults(bench=True)
+
+    calibrate = command("calibrate", "Détermine le seuil de parallélisation optimal.")
+    calibrate.add_argument(
+        "--save",
+        dest="calibrate_save",
+        action="store_true",
+        help=f"""Enregistre le seuil optimal trouvé dans le fichier de
+configuration ({get_config_path()}). Il sert ensuite de
+valeur par défaut à '--threshold'.""",
+    )
+    calibrate.add_argument(
+        "--timeout",
+        dest="calibrate_timeout",
+        type=_interval_type,
+        default=None,
+        metavar="SECONDES",
+        help="""Délai accordé aux mesures de chaque taille : une taille qui le
+dépasse est ignorée, et signalée comme telle dans le résumé, plutôt que de
+bloquer la calibration (par défaut: aucun).""",
+    )
+    calibrate.add_argument(
+        "--points",
+        dest="calibrate_json",
+        type=str,
+        default=None,
+        metavar="FICHIER",
+        help=_CALIBRATION_POINTS_HELP,
+    )
+    calibrate.add_argument(
+        "--crossover",
+        action="store_true",
+        help=_CROSSOVER_HELP,
+    )
+    calibrate.set_defaults(calibrate=True)
+
+    batch = command("batch", "Calcule F(n) pour chaque indice lu, un par ligne.")
+    batch.add_argument(
+        "batch_file",
+        nargs="?",
+        metavar="FICHIER",
+        help="Le fichier des indices (par défaut: l'entrée standard).",
+    )
+    _add_engine_arguments(batch)
+    _add_report_arguments(batch)
+    _add_value_arguments(batch)
+    batch.set_defaults(batch=True)
+
+    interval = command("range", "Calcule F(a), F(a+1), ..., F(b) par additions successives.")
+    interval.add_argument(
+        "range",
+        type=_range_type,
+        metavar="A:B",
+        help="""Les bornes incluses de l'intervalle. Pour une borne négative,
+les séparer des options par '--' ('pyfibonacci range -- -5:3').""",
+    )
+    _add_engine_arguments(interval)
+    _add_report_arguments(interval)
+    _add_value_arguments(interval)
+
+    decode = command("decode", "Décrit un fichier écrit avec '--format binary'.")
+    decode.add_argument("decode", metavar="FICHIER", help="Le fichier à décrire.")
+    _add_report_arguments(decode)
+
+    inverse = command("isfib", "Indique si un entier est un nombre de Fibonacci, et lequel.")
+    inverse.add_argument(
+        "is_fib",
+        type=int,
+        metavar="M",
+        help="""L'entier à tester. S'il est un nombre de Fibonacci, son indice
+est affiché (1 en a deux : F(1) = F(2) = 1). Pour un entier négatif, le
+séparer des options par '--' ('pyfibonacci isfib -- -8').""",
+    )
+    _add_report_arguments(inverse)
+
+    gcd = command("gcd", "Vérifie l'identité pgcd(F(m), F(n)) = F(pgcd(m, n)).")
+    gcd.add_argument(
+        "gcd",
+        type=_index_pair_type,
+        metavar="M:N",
+        help="""Les deux indices. F(m), F(n) et F(pgcd(m, n)) sont calculés
+en parallèle avec l'algorithme choisi, puis pgcd(F(m), F(n)) est comparé à
+F(pgcd(m, n)). Pour un indice négatif, le séparer des options par '--'
+('pyfibonacci gcd -- -12:18').""",
+    )
+    _add_engine_arguments(gcd)
+    _add_report_arguments(gcd)
+    _add_value_arguments(gcd)
+
+    algos = command("algos", "Décrit les algorithmes disponibles, extensions comprises.")
+    _add_report_arguments(algos)
+    algos.set_defaults(algo_info=True)
+
+    request = command("request", "Calcule F(n) pour une requête JSON lue sur l'entrée standard.")
+    request.description += """
+
+La requête est un objet JSON, par exemple {"n": 1000, "algo": "fast"} ;
+ses champs 'n', 'algo' et 'mod' remplacent les options '-n', '--algo' et
+'--mod'. Le résultat est émis en JSON sur la sortie standard, comme avec
+'calc --json'."""
+    _add_engine_arguments(request)
+    _add_report_arguments(request)
+    _add_value_arguments(request)
+    request.set_defaults(stdin_json=True)
+
+    for subparser in commands.choices.values():
+        _apply_defaults(subparser)
+    return parser
+
+
+def _subcommand_parser(parser: argparse.ArgumentParser, name: str) -> argparse.ArgumentParser:
+    """Retourne le parseur de la sous-commande `name` de `parser`."""
+    commands = next(a for a in parser._actions if isinstance(a, argparse._SubParsersAction))
+    return commands.choices[name]
+
+
+def parse_args(argv: Optional[Sequence[str]] = None) -> argparse.Namespace:
+    """Configure et exécute l'analyse des arguments de la ligne de commande.
+
+    Les modes de l'application sont des sous-commandes (`SUBCOMMANDS`), dont
+    chacune n'accepte que ses propres options : `pyfibonacci calc -n 100`,
+    `pyfibonacci serve :8080`, `pyfibonacci bench`... L'invocation historique,
+    sans sous-commande et où les modes sont des options (`--serve`,
+    `--bench`...), reste acceptée pour cette version, avec un avertissement.
+    Dans les deux cas, l'espace de noms retourné contient toutes les options,
+    celles qui ne concernent pas la sous-commande valant leur défaut.
+
+    Par ordre de priorité croissante, la valeur d'une option provient du
+    défaut codé en dur, du fichier de configuration, de la variable
+    d'environnement associée (voir `ENV_VAR_PREFIX`) puis de la ligne de
+    commande.
+
+    Args:
+        argv (Optional[Sequence[str]]): Les arguments à analyser ; par
+            défaut, ceux de la ligne de commande.
+
+    Returns:
+        argparse.Namespace: Un objet contenant les arguments analysés. Chaque
+        argument est accessible en tant qu'attribut de cet objet (par exemple,
+        `args.n`, `args.algo`), et `args.command` désigne la sous-commande
+        (`None` pour l'invocation historique).
+    """
+    argv = sys.argv[1:] if argv is None else list(argv)
+    legacy = _build_legacy_parser()
+    if argv and (argv[0] in SUBCOMMANDS or argv[0] in ("-h", "--help")):
+        parser = _build_parser()
+        chosen = parser.parse_args(argv)
+        # Une variable invalide n'est signalée que pour les options de la
+        # sous-commande choisie ; les autres reprennent leur défaut codé en dur.
+        args = legacy.parse_args([])
+        pristine = _build_legacy_parser(apply_overrides=False).parse_args([])
+        for dest, value in vars(args).items():
+            if isinstance(value, _InvalidDefault):
+                setattr(args, dest, getattr(pristine, dest))
+        vars(args).update(vars(chosen))
+        return _check_defaults(_subcommand_parser(parser, chosen.command), args)
+    if argv and argv[0] not in ("-v", "--version"):
+        print(
+            "AVERTISSEMENT: L'invocation sans sous-commande est dépréciée et sera retirée "
+            "dans la prochaine version ; utiliser par exemple 'pyfibonacci calc -n 100' "
+            "(voir 'pyfibonacci -h').",
+            file=sys.stderr,
+        )
+    return _check_defaults(legacy, legacy.parse_args(argv))
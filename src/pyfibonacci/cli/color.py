@@ -0,0 +1,53 @@
+"""
+Module pour la coloration ANSI des messages de l'interface CLI.
+
+La coloration est pilotée par l'option `--color` : `always` et `never`
+l'imposent, tandis que `auto` ne l'active que si le flux de destination est
+un terminal (et que la variable d'environnement `NO_COLOR` n'est pas
+définie), afin que les sorties redirigées et les journaux de CI restent du
+texte brut.
+"""
+
+import os
+from typing import TextIO
+
+# Modes acceptés par l'option `--color`.
+COLOR_MODES = ("auto", "always", "never")
+
+# Codes ANSI des couleurs utilisées.
+_ANSI_CODES = {"green": "32", "yellow": "33", "red": "31"}
+
+
+def color_enabled(mode: str, stream: TextIO) -> bool:
+    """Indique si la coloration doit être utilisée pour un flux donné.
+
+    Args:
+        mode (str): Le mode de coloration (voir `COLOR_MODES`).
+        stream (TextIO): Le flux de destination.
+
+    Returns:
+        bool: `True` si les messages écrits sur `stream` doivent être colorés.
+    """
+    if mode == "always":
+        return True
+    if mode == "never" or "NO_COLOR" in os.environ:
+        return False
+    isatty = getattr(stream, "isatty", None)
+    return bool(isatty and isatty())
+
+
+def paint(text: str, color: str, mode: str, stream: TextIO) -> str:
+    """Colore un texte si la coloration est active pour le flux donné.
+
+    Args:
+        text (str): Le texte à colorer.
+        color (str): La couleur (`green`, `yellow` ou `red`).
+        mode (str): Le mode de coloration (voir `COLOR_MODES`).
+        stream (TextIO): Le flux sur lequel le texte sera écrit.
+
+    Returns:
+        str: Le texte, encadré de séquences ANSI si la coloration est active.
+    """
+    if not color_enabled(mode, stream):
+        return text
+    return f"\033[{_ANSI_CODES[color]}m{text}\033[0m"
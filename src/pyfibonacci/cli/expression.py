@@ -0,0 +1,159 @@
+"""
+Module d'évaluation des expressions d'indice de l'option `-n`.
+
+Des indices comme 2^30 ou 10^8+1 sont plus sûrs à écrire qu'à développer à
+la main : `-n` accepte donc une petite expression arithmétique sur des
+entiers positifs ou nuls, avec `+`, `-`, `*`, `^` (exponentiation, associative
+à droite) et des parenthèses. Les priorités sont les priorités usuelles.
+
+Le résultat et chaque résultat intermédiaire doivent tenir dans un entier
+non signé de 64 bits : une expression qui en sort est refusée, et non
+ramenée modulo 2^64. L'exponentiation est bornée avant d'être calculée, de
+sorte qu'une expression comme `2^2^100` échoue immédiatement.
+"""
+
+import re
+from typing import List, Tuple
+
+# Plus grande valeur d'une expression (et de ses étapes) : 2^64 - 1.
+MAX_EXPRESSION_VALUE = 2**64 - 1
+
+# Lexèmes : un entier en chiffres décimaux, ou un opérateur ou une parenthèse.
+_TOKEN = re.compile(r"\s*(?:([0-9]+)|([-+*^()]))")
+
+
+class ExpressionError(ValueError):
+    """Erreur de syntaxe ou de dépassement dans une expression d'indice."""
+
+
+def _tokenize(text: str) -> List[Tuple[str, int]]:
+    """Découpe l'expression en lexèmes, avec leur position (à partir de 1).
+
+    Raises:
+        ExpressionError: Si un caractère n'appartient à aucun lexème.
+    """
+    tokens = []
+    position = 0
+    text = text.rstrip()
+    while position < len(text):
+        match = _TOKEN.match(text, position)
+        if match is None:
+            offset = len(text) - len(text[position:].lstrip())
+            raise ExpressionError(f"caractère inattendu '{text[offset]}' en position {offset + 1}")
+        tokens.append((match.group(1) or match.group(2), match.start(match.lastindex) + 1))
+        position = match.end()
+    return tokens
+
+
+class _Parser:
+    """Analyseur descendant récursif de la grammaire :
+
+        expression := terme (('+' | '-') terme)*
+        terme      := puissance ('*' puissance)*
+        puissance  := atome ('^' puissance)?
+        atome      := entier | '(' expression ')'
+    """
+
+    def __init__(self, tokens: List[Tuple[str, int]], length: int) -> None:
+        self._tokens = tokens
+        self._index = 0
+        self._end = length + 1
+
+    def _peek(self) -> str:
+        return self._tokens[self._index][0] if self._index < len(self._tokens) else ""
+
+    def _unexpected(self) -> ExpressionError:
+        if self._index >= len(self._tokens):
+            return ExpressionError("expression incomplète")
+        token, position = self._tokens[self._index]
+        return ExpressionError(f"'{token}' inattendu en position {position}")
+
+    def parse(self) -> int:
+        value = self._expression()
+        if self._index < len(self._tokens):
+            raise self._unexpected()
+        return value
+
+    def _expression(self) -> int:
+        value = self._term()
+        while self._peek() in ("+", "-"):
+            operator = self._peek()
+            self._index += 1
+            operand = self._term()
+            value = _checked(value + operand if operator == "+" else value - operand)
+        return value
+
+    def _term(self) -> int:
+        value = self._power()
+        while self._peek() == "*":
+            self._index += 1
+            value = _checked(value * self._power())
+        return value
+
+    def _power(self) -> int:
+        base = self._atom()
+        if self._peek() != "^":
+            return base
+        self._index += 1
+        exponent = self._power()
+        # Au-delà de 64, seules les bases 0 et 1 restent dans l'intervalle :
+        # le test évite de calculer une puissance gigantesque pour rien.
+        if base > 1 and exponent >= 64:
+            raise _overflow()
+        return _checked(base**exponent)
+
+    def _atom(self) -> int:
+        token = self._peek()
+        if token.isdigit():
+            self._index += 1
+            return _checked(int(token))
+        if token == "(":
+            self._index += 1
+            value = self._expression()
+            if self._peek() != ")":
+                if self._index >= len(self._tokens):
+                    raise ExpressionError(f"parenthèse fermante manquante en position {self._end}")
+                raise self._unexpected()
+            self._index += 1
+            return value
+        raise self._unexpected()
+
+
+def _overflow() -> ExpressionError:
+    return ExpressionError("dépassement : la valeur excède 2^64 - 1")
+
+
+def _checked(value: int) -> int:
+    """Vérifie qu'une valeur tient dans un entier non signé de 64 bits."""
+    if value < 0:
+        raise ExpressionError("dépassement : la valeur devient négative")
+    if value > MAX_EXPRESSION_VALUE:
+        raise _overflow()
+    return value
+
+
+def evaluate_index(text: str) -> int:
+    """Évalue l'indice donné à `-n`, nombre ou expression arithmétique.
+
+    Un nombre simple est converti tel quel, comme par `int` : il peut être
+    négatif et n'est pas borné. Toute autre valeur est évaluée comme une
+    expression (voir le module).
+
+    Args:
+        text (str): La valeur brute fournie sur la ligne de commande.
+
+    Returns:
+        int: L'indice.
+
+    Raises:
+        ExpressionError: Si l'expression est malformée, ou si sa valeur ou
+            l'une de ses étapes sort de l'intervalle [0, 2^64 - 1].
+    """
+    try:
+        return int(text)
+    except ValueError:
+        pass
+    tokens = _tokenize(text)
+    if not tokens:
+        raise ExpressionError("expression vide")
+    return _Parser(tokens, len(text.rstrip())).parse()
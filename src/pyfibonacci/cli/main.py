@@ -9,16 +9,19 @@ grands nombres et d'initier l'exécution de la logique applicative asynchrone.
 import asyncio
 import sys
 from pyfibonacci.app import main_async
+from pyfibonacci.registry import load_plugins
 
 
 def main() -> None:
     """Point d'entrée synchrone qui initialise et lance l'application asynchrone.
 
-    Cette fonction effectue deux actions critiques :
+    Cette fonction effectue trois actions critiques :
     1.  Elle configure `sys.set_int_max_str_digits(0)` pour désactiver la
         limite de taille sur la conversion des entiers en chaînes, ce qui est
         indispensable pour les grands nombres de Fibonacci.
-    2.  Elle utilise `asyncio.run()` pour démarrer l'exécution de la coroutine
+    2.  Elle enregistre les algorithmes déclarés par les extensions installées
+        (voir `registry.load_plugins`), avant l'analyse de `--algo`.
+    3.  Elle utilise `asyncio.run()` pour démarrer l'exécution de la coroutine
         `main_async`, qui contient la logique principale de l'application.
 
     La fonction gère également l'exception `KeyboardInterrupt` pour permettre
@@ -26,6 +29,11 @@ def main() -> None:
     """
     # Désactive la limite de conversion int<->str, nécessaire pour les grands nombres.
     sys.set_int_max_str_digits(0)
+    try:
+        load_plugins()
+    except (ImportError, TypeError, ValueError) as e:
+        print(f"ERREUR: Extension invalide : {e}", file=sys.stderr)
+        sys.exit(1)
     try:
         asyncio.run(main_async())
     except KeyboardInterrupt:
@@ -0,0 +1,123 @@
+"""
+Module de mesure de la consommation mémoire d'un calcul.
+
+Avec `-d`, l'application relève l'état de la mémoire avant et après le
+calcul : les octets alloués par Python (suivis par `tracemalloc`, dont le pic
+est remis à zéro au départ), le nombre de passes du ramasse-miettes et la
+taille résidente maximale du processus. Seules les allocations du processus
+principal sont suivies : les produits délégués au pool de processus, et les
+algorithmes synchrones qui s'y exécutent, n'apparaissent que par leurs
+résultats. En mode comparaison, les algorithmes s'exécutant simultanément, le
+pic rapporté est celui de l'ensemble.
+"""
+
+import gc
+import sys
+import tracemalloc
+from dataclasses import dataclass
+from typing import Optional
+
+try:
+    import resource
+except ImportError:  # pragma: no cover - indisponible sous Windows
+    resource = None
+
+from .output import _format_bytes
+
+# Vrai si `start_memory_tracking` a lui-même démarré `tracemalloc` : un suivi
+# démarré auparavant (par `--profile mem`) n'est pas arrêté à la fin du calcul.
+_owns_tracing = False
+
+
+@dataclass(frozen=True)
+class MemorySnapshot:
+    """État de la mémoire du processus à un instant donné.
+
+    Attributes:
+        allocated (int): Les octets alloués par Python et encore vivants.
+        peak (int): Le pic d'octets alloués depuis le début du suivi.
+        collections (int): Le nombre total de passes du ramasse-miettes.
+        max_rss (Optional[int]): La taille résidente maximale du processus,
+            en octets, si la plate-forme l'expose.
+    """
+
+    allocated: int
+    peak: int
+    collections: int
+    max_rss: Optional[int] = None
+
+
+def _max_rss() -> Optional[int]:
+    """Retourne la taille résidente maximale du processus, en octets."""
+    if resource is None:
+        return None
+    max_rss = resource.getrusage(resource.RUSAGE_SELF).ru_maxrss
+    # macOS l'exprime en octets, Linux en kilooctets.
+    return max_rss if sys.platform == "darwin" else max_rss * 1024
+
+
+def take_memory_snapshot() -> MemorySnapshot:
+    """Relève l'état courant de la mémoire.
+
+    Returns:
+        MemorySnapshot: L'état relevé ; les octets alloués valent 0 si le
+        suivi n'a pas été démarré par `start_memory_tracking`.
+    """
+    allocated, peak = tracemalloc.get_traced_memory()
+    collections = sum(stats["collections"] for stats in gc.get_stats())
+    return MemorySnapshot(allocated, peak, collections, _max_rss())
+
+
+def start_memory_tracking() -> MemorySnapshot:
+    """Démarre le suivi des allocations et relève l'état de départ.
+
+    Returns:
+        MemorySnapshot: L'état de la mémoire avant le calcul, le pic étant
+        remis au niveau des allocations courantes.
+    """
+    global _owns_tracing
+    if not tracemalloc.is_tracing():
+        tracemalloc.start()
+        _owns_tracing = True
+    tracemalloc.reset_peak()
+    return take_memory_snapshot()
+
+
+def stop_memory_tracking() -> MemorySnapshot:
+    """Relève l'état final de la mémoire puis arrête le suivi des allocations.
+
+    Le suivi n'est arrêté que s'il a été démarré par `start_memory_tracking`.
+
+    Returns:
+        MemorySnapshot: L'état de la mémoire après le calcul.
+    """
+    global _owns_tracing
+    snapshot = take_memory_snapshot()
+    if _owns_tracing:
+        tracemalloc.stop()
+        _owns_tracing = False
+    return snapshot
+
+
+def describe_memory(before: MemorySnapshot, after: MemorySnapshot) -> str:
+    """Résume la consommation mémoire d'un calcul, sur une ligne.
+
+    Args:
+        before (MemorySnapshot): L'état relevé avant le calcul.
+        after (MemorySnapshot): L'état relevé après le calcul.
+
+    Returns:
+        str: Le pic d'allocation au-delà de l'état de départ, la mémoire
+        restée allouée, le nombre de passes du ramasse-miettes et, si elle
+        est connue, la taille résidente maximale.
+    """
+    peak = max(after.peak - before.allocated, 0)
+    retained = after.allocated - before.allocated
+    sign = "-" if retained < 0 else "+"
+    description = (
+        f"Mémoire : pic de {_format_bytes(peak)}, {sign}{_format_bytes(abs(retained))} "
+        f"retenus, {after.collections - before.collections} passes du ramasse-miettes"
+    )
+    if after.max_rss is not None:
+        description += f", taille résidente max. {_format_bytes(after.max_rss)}"
+    return description
@@ -0,0 +1,803 @@
+"""
+Module pour la mise en forme des résultats de calcul.
+
+Ce module regroupe la représentation des résultats produits par les
+algorithmes ainsi que leur sérialisation pour la sortie standard, qu'elle
+soit destinée à un humain ou à un programme (mode JSON).
+
+Chaque exécution peut enfin être ajoutée à un journal CSV (`append_csv`),
+une ligne par algorithme, pour agréger les mesures de plusieurs machines
+dans un tableur.
+
+Le résultat peut aussi être exporté dans un format binaire, bien plus rapide
+à produire et à relire qu'une représentation décimale de plusieurs millions
+de chiffres : un en-tête de 14 octets (`BINARY_MAGIC`, la version du format,
+le signe, puis la taille de la magnitude sur 8 octets gros-boutistes) suivi
+de la magnitude, en gros-boutiste. Un consommateur la relit par
+`int.from_bytes(données, "big")`.
+"""
+
+import csv
+import hashlib
+import json
+import math
+import os
+import platform
+import socket
+import statistics
+import struct
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from typing import Any, BinaryIO, Dict, Iterable, Iterator, List, Optional, TextIO, Tuple
+
+from ..core.context import CalculationProgress
+from ..core.errors import CalculationError
+
+# Alphabet des chiffres pour les bases 2 à 36.
+_DIGITS = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+# Nombre de chiffres convertis par la boucle naïve au bas de la récursion.
+_BASE_CHUNK_DIGITS = 64
+
+# Nombre maximal de chiffres de chaque fragment produit par
+# `iter_decimal_chunks`, sous la limite par défaut de conversion de CPython.
+DECIMAL_CHUNK_DIGITS = 4096
+
+
+# Colonnes du journal CSV des exécutions, dans l'ordre.
+CSV_COLUMNS = (
+    "timestamp",
+    "hostname",
+    "cpu_count",
+    "python_version",
+    "algorithm",
+    "n",
+    "duration_ns",
+    "digits",
+    "bit_length",
+    "outcome",
+)
+
+# Formats d'export du résultat dans un fichier.
+OUTPUT_FORMATS = ("dec", "binary")
+
+# Signature et version du format binaire.
+BINARY_MAGIC = b"PYFB"
+BINARY_VERSION = 1
+
+# En-tête du format binaire : signature, version, signe, taille de la magnitude.
+_BINARY_HEADER = struct.Struct(">4sBBQ")
+
+# Algorithmes d'empreinte acceptés par `--hash`.
+HASH_ALGORITHMS = ("sha256", "blake2b")
+
+# Nombre de chiffres affichés à chaque extrémité d'un résultat tronqué
+# ('--trunc-edges').
+DEFAULT_TRUNCATE_EDGES = 25
+
+
+@dataclass
+class DisplayOptions:
+    """Options de présentation des résultats sur la sortie standard.
+
+    Attributes:
+        json (bool): Si vrai, la sortie standard est réservée à un unique
+            document JSON ; les messages destinés à l'humain sont omis.
+        base (int): La base (de 2 à 36) de représentation des valeurs.
+        quiet (bool): Si vrai, la sortie standard ne contient que la valeur
+            calculée, sans bannière ni décoration.
+        color (str): Le mode de coloration des messages (voir
+            `cli.color.COLOR_MODES`).
+        thousands_sep (str): Le séparateur des milliers des nombres affichés
+            dans les messages (nombre de chiffres, taille en bits...).
+        sci_digits (Optional[int]): Si défini, le nombre de chiffres
+            significatifs de la notation scientifique du résultat, affichée
+            en complément de sa valeur.
+        show_value (bool): Si faux, la valeur calculée n'est ni affichée ni
+            convertie (mode `--compare-only`) : seuls les statuts, les
+            durées et le verdict de concordance sont rapportés.
+        truncate_limit (Optional[int]): Si défini, le nombre de chiffres
+            au-delà duquel le résultat affiché est tronqué (voir
+            `truncate_digits`) ; par défaut, il est affiché en entier.
+        truncate_edges (int): Le nombre de chiffres conservés à chaque
+            extrémité d'un résultat tronqué.
+    """
+
+    json: bool = False
+    base: int = 10
+    quiet: bool = False
+    color: str = "auto"
+    thousands_sep: str = " "
+    sci_digits: Optional[int] = None
+    show_value: bool = True
+    truncate_limit: Optional[int] = None
+    truncate_edges: int = DEFAULT_TRUNCATE_EDGES
+
+    @property
+    def show_messages(self) -> bool:
+        """Indique si les messages destinés à l'humain sont écrits sur la sortie standard."""
+        return not (self.json or self.quiet)
+
+    def format_result(self, value: int) -> str:
+        """Représente un résultat pour l'affichage, tronqué s'il le faut.
+
+        En base 10, un résultat tronqué n'est pas converti en entier : ses
+        chiffres sont comptés (voir `count_digits`), et seules ses extrémités
+        sont extraites par division et reste, sans le coût de la conversion
+        décimale complète.
+
+        Args:
+            value (int): Le résultat à afficher.
+
+        Returns:
+            str: Sa représentation dans la base choisie (voir
+            `format_value`), tronquée selon `truncate_limit`.
+        """
+        if self.base == 10 and self.truncate_limit is not None:
+            digits, power = _decimal_magnitude(abs(value))
+            edges = self.truncate_edges
+            if digits > self.truncate_limit and edges < digits:
+                sign = "-" if value < 0 else ""
+                head = abs(value) // (power // 10 ** (edges - 1))
+                tail = str(abs(value) % 10**edges).zfill(edges)
+                total = group_digits(digits, self.thousands_sep)
+                return f"{sign}{head}...{tail} ({total} chiffres)"
+        return truncate_digits(
+            format_value(value, self.base), self.truncate_limit, self.truncate_edges, self.thousands_sep
+        )
+
+    def write_result(self, stream: TextIO, prefix: str, value: int) -> None:
+        """Écrit un résultat précédé de `prefix`, tronqué s'il le faut.
+
+        Un résultat affiché en entier est écrit par fragments (voir
+        `write_value`) plutôt que mis en forme puis concaténé à `prefix` :
+        afficher F(10^8), vingt millions de chiffres, ne double pas la
+        mémoire occupée par sa représentation décimale.
+
+        Args:
+            stream (TextIO): Le flux de destination.
+            prefix (str): Le libellé qui précède le résultat.
+            value (int): Le résultat à afficher.
+        """
+        if self.truncate_limit is not None:
+            stream.write(f"{prefix}{self.format_result(value)}\n")
+            return
+        stream.write(prefix)
+        write_value(stream, value, self.base)
+
+
+@dataclass
+class CalculationResult:
+    """Résultat de l'exécution d'un algorithme pour un indice donné.
+
+    Attributes:
+        algorithm (str): Le nom de l'algorithme (clé du registre).
+        value (Optional[int]): La valeur calculée, ou `None` en cas d'échec.
+        duration (float): La durée d'exécution, en secondes.
+        error (Optional[str]): Un message décrivant l'échec, le cas échéant.
+        timed_out (bool): Indique si l'échec est dû au dépassement du timeout.
+        progress (Optional[CalculationProgress]): L'avancement atteint, pour
+            les algorithmes qui le rapportent.
+        failure (Optional[CalculationError]): L'erreur structurée décrivant
+            l'échec, lorsqu'elle est connue.
+        durations (List[float]): Les durées de chaque exécution lorsque le
+            calcul est répété (`--repeat`) ; `duration` en est alors la
+            médiane.
+    """
+
+    algorithm: str
+    value: Optional[int]
+    duration: float
+    error: Optional[str] = None
+    timed_out: bool = False
+    progress: Optional[CalculationProgress] = None
+    failure: Optional[CalculationError] = None
+    durations: List[float] = field(default_factory=list)
+
+    @property
+    def success(self) -> bool:
+        """Indique si l'algorithme a produit une valeur."""
+        return self.error is None
+
+    @property
+    def outcome(self) -> str:
+        """L'issue du calcul, parmi celles publiées par le module `metrics`."""
+        if self.failure is not None:
+            return self.failure.category.value
+        if self.timed_out:
+            return "timeout"
+        return "success" if self.success else "error"
+
+
+def describe_progress(progress: Optional[CalculationProgress]) -> Optional[str]:
+    """Décrit l'avancement atteint par un calcul, par exemple `~63% (bit 18/29)`.
+
+    Args:
+        progress (Optional[CalculationProgress]): L'avancement du calcul.
+
+    Returns:
+        Optional[str]: La description, ou `None` si l'algorithme ne rapporte
+        pas son avancement.
+    """
+    if progress is None or not progress.total:
+        return None
+    return f"~{int(100 * progress.fraction)}% (bit {progress.completed}/{progress.total})"
+
+
+def group_digits(value: int, sep: str = " ") -> str:
+    """Représente un entier en groupant ses chiffres par milliers.
+
+    Args:
+        value (int): L'entier à représenter (éventuellement négatif).
+        sep (str): Le séparateur des milliers (vide pour ne pas grouper).
+
+    Returns:
+        str: La représentation groupée, par exemple `1 234 567`.
+    """
+    return f"{value:,}".replace(",", sep)
+
+
+def _format_duration(seconds: float) -> str:
+    """Représente une durée dans l'unité la plus lisible (µs, ms ou s)."""
+    if seconds < 1e-3:
+        return f"{seconds * 1e6:.1f} µs"
+    if seconds < 1:
+        return f"{seconds * 1e3:.2f} ms"
+    return f"{seconds:.3f} s"
+
+
+def _format_bytes(size: int) -> str:
+    """Représente une taille en octets dans l'unité binaire la plus lisible."""
+    if size < 1024:
+        return f"{size} o"
+    value = size / 1024
+    for unit in ("Kio", "Mio", "Gio"):
+        if value < 1024:
+            return f"{value:.1f} {unit}"
+        value /= 1024
+    return f"{value:.1f} Tio"
+
+
+def describe_durations(durations: List[float]) -> Optional[str]:
+    """Résume les durées d'un calcul répété, par exemple `min 1.20 ms, ...`.
+
+    Args:
+        durations (List[float]): Les durées de chaque exécution, en secondes.
+
+    Returns:
+        Optional[str]: Les durées minimale, médiane et moyenne et le nombre
+        d'exécutions, ou `None` si le calcul n'a pas été répété.
+    """
+    if len(durations) < 2:
+        return None
+    return (
+        f"min {_format_duration(min(durations))}, "
+        f"médiane {_format_duration(statistics.median(durations))}, "
+        f"moyenne {_format_duration(statistics.fmean(durations))} "
+        f"sur {len(durations)} exécutions"
+    )
+
+
+def digit_sum(value: int) -> Tuple[int, int]:
+    """Calcule la somme des chiffres décimaux d'un entier et sa racine numérique.
+
+    La représentation décimale est construite une seule fois ; chaque chiffre
+    y est ensuite compté par `str.count`, ce qui reste rapide même pour des
+    dizaines de millions de chiffres. Le signe est ignoré.
+
+    Args:
+        value (int): L'entier à analyser.
+
+    Returns:
+        Tuple[int, int]: La somme des chiffres et la racine numérique
+        (somme itérée jusqu'à obtenir un seul chiffre).
+    """
+    text = str(abs(value))
+    total = sum(int(d) * text.count(d) for d in "123456789")
+    root = 1 + (total - 1) % 9 if total else 0
+    return total, root
+
+
+def format_scientific(value: int, digits: int = 6) -> str:
+    """Représente un entier en notation scientifique, arrondi à `digits` chiffres significatifs.
+
+    Le calcul est exact, en arithmétique entière : une conversion en flottant
+    limiterait la mantisse à environ 17 chiffres significatifs (53 bits) et
+    l'exposant à 308, alors qu'obtenir K chiffres exacts requiert une mantisse
+    d'environ K × log2(10) ≈ 3,32 × K bits. Seule la tête du nombre est
+    convertie en texte : le nombre de chiffres est estimé à partir de la
+    longueur en bits, puis une division entière par une puissance de 10
+    élimine la queue. L'arrondi se fait au plus proche, les égalités vers le
+    haut.
+
+    Args:
+        value (int): L'entier à représenter (éventuellement négatif).
+        digits (int): Le nombre de chiffres significatifs (au moins 1).
+
+    Returns:
+        str: La représentation, au format de Python (`4.34666e+208`).
+    """
+    if value < 0:
+        return "-" + format_scientific(-value, digits)
+    shift = max(int(value.bit_length() * math.log10(2)) - digits - 2, 0)
+    head = value // 10**shift
+    exponent = len(str(head)) - 1 + shift
+    excess = len(str(head)) - digits
+    if excess > 0:
+        mantissa = str((head + 5 * 10 ** (excess - 1)) // 10**excess)
+        if len(mantissa) > digits:  # L'arrondi a ajouté un chiffre (999... -> 1000...).
+            mantissa, exponent = mantissa[:digits], exponent + 1
+    else:
+        mantissa = str(head) + "0" * -excess
+    fraction = "." + mantissa[1:] if digits > 1 else ""
+    return f"{mantissa[0]}{fraction}e{exponent:+03d}"
+
+
+def format_value(value: int, base: int = 10) -> str:
+    """Représente un entier dans une base comprise entre 2 et 36.
+
+    Les bases 2, 8, 10 et 16 s'appuient sur les conversions natives de
+    Python. Les autres bases utilisent une conversion "diviser pour régner"
+    par puissances carrées successives de la base, qui évite le coût
+    quadratique d'une division répétée chiffre par chiffre. Les chiffres
+    au-delà de 9 sont écrits en minuscules.
+
+    Args:
+        value (int): L'entier à représenter (éventuellement négatif).
+        base (int): La base de représentation.
+
+    Returns:
+        str: La représentation de `value`, sans préfixe (`0x`, `0b`...).
+
+    Raises:
+        ValueError: Si `base` n'est pas comprise entre 2 et 36.
+    """
+    if not 2 <= base <= 36:
+        raise ValueError("La base doit être comprise entre 2 et 36.")
+    if value < 0:
+        return "-" + format_value(-value, base)
+    if base == 10:
+        return str(value)
+    if base in (2, 8, 16):
+        return format(value, {2: "b", 8: "o", 16: "x"}[base])
+
+    def _small(x: int) -> str:
+        digits = []
+        while x:
+            x, r = divmod(x, base)
+            digits.append(_DIGITS[r])
+        return "".join(reversed(digits)) or "0"
+
+    # powers[k] = base ** (_BASE_CHUNK_DIGITS * 2**k)
+    powers = [base**_BASE_CHUNK_DIGITS]
+    while powers[-1] * powers[-1] <= value:
+        powers.append(powers[-1] * powers[-1])
+
+    def _convert(x: int, k: int, width: int) -> str:
+        """Convertit `x`, complété par des zéros à `width` chiffres si non nul."""
+        if k < 0:
+            text = _small(x)
+            return text.rjust(width, "0") if width else text
+        high, low = divmod(x, powers[k])
+        low_width = _BASE_CHUNK_DIGITS << k
+        if high == 0 and not width:
+            return _convert(low, k - 1, 0)
+        high_width = width - low_width if width else 0
+        return _convert(high, k - 1, high_width) + _convert(low, k - 1, low_width)
+
+    return _convert(value, len(powers) - 1, 0)
+
+
+def truncate_digits(text: str, limit: Optional[int], edges: int, sep: str = " ") -> str:
+    """Tronque la représentation d'un nombre trop long à ses extrémités.
+
+    Args:
+        text (str): La représentation du nombre, éventuellement précédée
+            d'un signe moins.
+        limit (Optional[int]): Le nombre de chiffres au-delà duquel le
+            nombre est tronqué ; `None` désactive la troncature.
+        edges (int): Le nombre de chiffres conservés à chaque extrémité.
+        sep (str): Le séparateur des milliers du nombre total de chiffres.
+
+    Returns:
+        str: `text` s'il compte au plus `limit` chiffres, sinon ses `edges`
+        premiers et derniers chiffres séparés par `...`, suivis du nombre
+        total de chiffres.
+    """
+    sign = "-" if text.startswith("-") else ""
+    digits = text[len(sign):]
+    if limit is None or len(digits) <= limit:
+        return text
+    return f"{sign}{digits[:edges]}...{digits[-edges:]} ({group_digits(len(digits), sep)} chiffres)"
+
+
+def iter_decimal_chunks(value: int, chunk_digits: int = DECIMAL_CHUNK_DIGITS) -> Iterator[str]:
+    """Produit la représentation décimale d'un entier par fragments, dans l'ordre.
+
+    Contrairement à `str(value)`, la chaîne complète n'est jamais construite :
+    l'entier est scindé récursivement par des puissances carrées successives
+    de `10**chunk_digits`, et seuls des fragments d'au plus `chunk_digits`
+    chiffres sont convertis. La mémoire consommée en plus de `value` reste
+    ainsi de l'ordre de sa taille binaire, ce qui permet d'écrire un résultat
+    de plusieurs dizaines de millions de chiffres dans un fichier ou un socket.
+
+    Args:
+        value (int): L'entier à représenter (éventuellement négatif).
+        chunk_digits (int): Le nombre maximal de chiffres d'un fragment.
+
+    Yields:
+        str: Les fragments successifs, dont la concaténation vaut `str(value)`.
+    """
+    return iter_base_chunks(value, 10, chunk_digits)
+
+
+def iter_base_chunks(
+    value: int, base: int = 10, chunk_digits: int = DECIMAL_CHUNK_DIGITS
+) -> Iterator[str]:
+    """Produit la représentation d'un entier dans une base donnée, par fragments.
+
+    C'est l'analogue de `iter_decimal_chunks` pour les bases 2 à 36. Dans
+    une base puissance de deux, chaque fragment est découpé directement
+    dans les octets de la magnitude, sans division ; dans les autres bases,
+    l'entier est scindé par des puissances carrées successives de
+    `base**chunk_digits`, comme en décimal.
+
+    Args:
+        value (int): L'entier à représenter (éventuellement négatif).
+        base (int): La base de représentation.
+        chunk_digits (int): Le nombre maximal de chiffres d'un fragment.
+
+    Yields:
+        str: Les fragments successifs, dont la concaténation vaut
+        `format_value(value, base)`.
+
+    Raises:
+        ValueError: Si `base` n'est pas comprise entre 2 et 36.
+    """
+    if not 2 <= base <= 36:
+        raise ValueError("La base doit être comprise entre 2 et 36.")
+    if base & (base - 1):
+        return _iter_divided_chunks(value, base, chunk_digits)
+    return _iter_sliced_chunks(value, base, chunk_digits)
+
+
+def _iter_sliced_chunks(value: int, base: int, chunk_digits: int) -> Iterator[str]:
+    """Produit les fragments d'un entier dans une base puissance de deux."""
+    if value < 0:
+        yield "-"
+        value = -value
+    digit_bits = base.bit_length() - 1
+    chunk_bits = digit_bits * chunk_digits
+    count = max(1, -(-value.bit_length() // chunk_bits))
+    magnitude = value.to_bytes((count * chunk_bits + 7) // 8, "big")
+    del value  # La magnitude suffit désormais.
+    for i in range(count - 1, -1, -1):
+        low = i * chunk_bits
+        high = low + chunk_bits
+        # Les octets qui couvrent les bits [low, high), en partant de la fin.
+        window = magnitude[len(magnitude) - (high + 7) // 8 : len(magnitude) - low // 8]
+        chunk = (int.from_bytes(window, "big") >> (low % 8)) & ((1 << chunk_bits) - 1)
+        text = format_value(chunk, base)
+        yield text if i == count - 1 else text.rjust(chunk_digits, "0")
+
+
+def _iter_divided_chunks(value: int, base: int, chunk_digits: int) -> Iterator[str]:
+    """Produit les fragments d'un entier par divisions successives (toute base)."""
+    if value < 0:
+        yield "-"
+        value = -value
+
+    # powers[k] = base ** (chunk_digits * 2**k)
+    powers = [base**chunk_digits]
+    while powers[-1] * powers[-1] <= value:
+        powers.append(powers[-1] * powers[-1])
+
+    def _chunks(x: int, k: int, width: int) -> Iterator[str]:
+        """Produit `x`, complété par des zéros à `width` chiffres si non nul."""
+        if k < 0:
+            text = format_value(x, base)
+            yield text.rjust(width, "0") if width else text
+            return
+        high, low = divmod(x, powers[k])
+        low_width = chunk_digits << k
+        if high == 0 and not width:
+            yield from _chunks(low, k - 1, 0)
+            return
+        high_width = width - low_width if width else 0
+        yield from _chunks(high, k - 1, high_width)
+        del high  # Libère la moitié haute avant de convertir la moitié basse.
+        yield from _chunks(low, k - 1, low_width)
+
+    yield from _chunks(value, len(powers) - 1, 0)
+
+
+def first_mismatch(value: int, expected: Iterable[str]) -> Optional[int]:
+    """Compare un entier à une représentation décimale de référence, par fragments.
+
+    Ni la représentation de `value` ni la référence ne sont construites en
+    entier : les fragments de `iter_decimal_chunks` sont confrontés au fil de
+    l'eau à ceux de `expected`, dont les blancs (sauts de ligne compris) sont
+    ignorés.
+
+    Args:
+        value (int): L'entier calculé.
+        expected (Iterable[str]): Les fragments successifs de la référence,
+            par exemple les blocs lus dans un fichier.
+
+    Returns:
+        Optional[int]: La position (à partir de 1) du premier caractère qui
+        diffère, ou `None` si les deux représentations sont identiques. Si
+        l'une est un préfixe de l'autre, la position est celle du premier
+        caractère excédentaire.
+    """
+    reference = ("".join(chunk.split()) for chunk in expected)
+    position = 0
+    pending = ""
+    for chunk in iter_decimal_chunks(value):
+        while chunk:
+            if not pending:
+                pending = next(reference, None)
+                if pending is None:
+                    return position + 1
+                continue
+            size = min(len(chunk), len(pending))
+            common = len(os.path.commonprefix([chunk[:size], pending[:size]]))
+            if common < size:
+                return position + common + 1
+            position += size
+            chunk, pending = chunk[size:], pending[size:]
+    if pending or any(reference):
+        return position + 1
+    return None
+
+
+def write_value(stream: TextIO, value: int, base: int = 10) -> None:
+    """Écrit un entier sur un flux, suivi d'un saut de ligne.
+
+    La valeur est écrite par fragments (voir `iter_base_chunks`), sans
+    construire sa représentation complète, quelle que soit la base.
+
+    Args:
+        stream (TextIO): Le flux de destination.
+        value (int): L'entier à écrire.
+        base (int): La base de représentation.
+    """
+    stream.writelines(iter_base_chunks(value, base))
+    stream.write("\n")
+
+
+def _encode_binary(value: int) -> Tuple[bytes, bytes]:
+    """Retourne l'en-tête et la magnitude d'un entier, au format d'export binaire."""
+    magnitude = abs(value).to_bytes((abs(value).bit_length() + 7) // 8, "big")
+    return _BINARY_HEADER.pack(BINARY_MAGIC, BINARY_VERSION, value < 0, len(magnitude)), magnitude
+
+
+def write_binary(stream: BinaryIO, value: int) -> int:
+    """Écrit un entier sur un flux binaire, au format d'export binaire.
+
+    Args:
+        stream (BinaryIO): Le flux de destination.
+        value (int): L'entier à écrire.
+
+    Returns:
+        int: Le nombre d'octets écrits, en-tête compris.
+    """
+    header, magnitude = _encode_binary(value)
+    stream.write(header)
+    stream.write(magnitude)
+    return len(header) + len(magnitude)
+
+
+def hash_value(value: int, algorithm: str = "sha256") -> str:
+    """Calcule l'empreinte d'un entier, pour comparer deux résultats sans les échanger.
+
+    L'empreinte porte sur la représentation canonique de l'export binaire
+    (voir `write_binary`) : elle est identique à celle du fichier écrit par
+    `--format binary` (`sha256sum f.bin`), quelle que soit la machine. La
+    conversion décimale, de loin l'étape la plus lente, est évitée ; la
+    seule copie est celle de la magnitude, de la taille de l'entier lui-même.
+
+    Args:
+        value (int): L'entier considéré (éventuellement négatif).
+        algorithm (str): L'algorithme d'empreinte (voir `HASH_ALGORITHMS`).
+
+    Returns:
+        str: L'empreinte, en hexadécimal.
+    """
+    hasher = hashlib.new(algorithm)
+    for part in _encode_binary(value):
+        hasher.update(part)
+    return hasher.hexdigest()
+
+
+def read_binary(stream: BinaryIO) -> int:
+    """Relit un entier écrit par `write_binary`.
+
+    Args:
+        stream (BinaryIO): Le flux source.
+
+    Returns:
+        int: L'entier lu.
+
+    Raises:
+        ValueError: Si le flux n'est pas au format binaire attendu, ou s'il
+            est tronqué.
+    """
+    header = stream.read(_BINARY_HEADER.size)
+    if not header.startswith(BINARY_MAGIC):
+        raise ValueError("Ce fichier n'est pas un export binaire de PyFibonacci.")
+    if len(header) < _BINARY_HEADER.size:
+        raise ValueError("Fichier binaire tronqué : en-tête incomplet.")
+    _, version, negative, size = _BINARY_HEADER.unpack(header)
+    if version != BINARY_VERSION:
+        raise ValueError(f"Version du format binaire non prise en charge : {version}.")
+    magnitude = stream.read(size)
+    if len(magnitude) < size:
+        raise ValueError(
+            f"Fichier binaire tronqué : {len(magnitude)} octets lus sur {size} annoncés."
+        )
+    value = int.from_bytes(magnitude, "big")
+    return -value if negative else value
+
+
+def encode_json_result(
+    n: int,
+    results: List[CalculationResult],
+    base: int = 10,
+    digitsum: bool = False,
+    show_value: bool = True,
+    hash_algorithm: Optional[str] = None,
+) -> str:
+    """Sérialise les résultats d'un calcul en un unique objet JSON.
+
+    L'objet contient l'indice, les métadonnées de chaque algorithme (nom,
+    durée en nanosecondes, succès, erreur, et durées de chaque exécution si
+    le calcul a été répété) et, à partir du premier résultat valide, le
+    nombre de chiffres décimaux, la longueur en bits et la valeur complète.
+    La valeur est encodée comme une chaîne, dans la base demandée, pour
+    rester exploitable par les parseurs JSON limités aux entiers 64 bits.
+
+    Args:
+        n (int): L'indice calculé.
+        results (List[CalculationResult]): Les résultats de chaque algorithme.
+        base (int): La base de représentation de la valeur.
+        digitsum (bool): Si vrai, ajoute la somme des chiffres décimaux
+            (`digit_sum`) et la racine numérique (`digital_root`).
+        show_value (bool): Si faux, la valeur et le nombre de chiffres, dont
+            le calcul exige la conversion décimale, sont omis (ainsi que la
+            somme des chiffres).
+        hash_algorithm (Optional[str]): Si fourni, ajoute l'empreinte de
+            la valeur (`hash_value`) et son algorithme (`hash_algorithm`),
+            même lorsque la valeur est omise.
+
+    Returns:
+        str: Le document JSON, sur une seule ligne.
+    """
+    value = next((r.value for r in results if r.success), None)
+    entries = []
+    for r in results:
+        entry: Dict[str, Any] = {
+            "algorithm": r.algorithm,
+            "duration_ns": int(r.duration * 1e9),
+            "success": r.success,
+            "error": r.error,
+        }
+        if len(r.durations) > 1:
+            entry["durations_ns"] = [int(d * 1e9) for d in r.durations]
+        entries.append(entry)
+    document: Dict[str, Any] = {
+        "n": n,
+        "results": entries,
+        "digits": len(str(abs(value))) if value is not None and show_value else None,
+        "bit_length": value.bit_length() if value is not None else None,
+        "base": base,
+        "value": format_value(value, base) if value is not None and show_value else None,
+    }
+    if not show_value:
+        del document["digits"], document["value"]
+    elif digitsum:
+        total, root = digit_sum(value) if value is not None else (None, None)
+        document["digit_sum"] = total
+        document["digital_root"] = root
+    if hash_algorithm is not None:
+        document["hash_algorithm"] = hash_algorithm
+        document["hash"] = hash_value(value, hash_algorithm) if value is not None else None
+    return json.dumps(document)
+
+
+def count_digits(value: int) -> int:
+    """Compte les chiffres décimaux d'un entier, sans le convertir en texte.
+
+    La longueur en bits fixe le nombre de chiffres à une unité près ; une
+    comparaison à la puissance de 10 correspondante tranche, pour un coût
+    bien moindre que celui de la conversion décimale.
+
+    Args:
+        value (int): L'entier considéré (éventuellement négatif).
+
+    Returns:
+        int: Le nombre de chiffres de `|value|`, signe exclu.
+    """
+    return _decimal_magnitude(abs(value))[0]
+
+
+def _decimal_magnitude(value: int) -> Tuple[int, int]:
+    """Retourne le nombre de chiffres d'un entier positif et `10**(chiffres - 1)`.
+
+    Une seule grande puissance de 10 est calculée : l'ajustement de
+    l'estimation la multiplie ou la divise par 10, en temps linéaire.
+    """
+    digits = int((value.bit_length() - 1) * math.log10(2)) + 1 if value else 1
+    power = 10 ** (digits - 1)
+    while digits > 1 and value < power:
+        digits -= 1
+        power //= 10
+    while value >= power * 10:
+        digits += 1
+        power *= 10
+    return digits, power
+
+
+def digit_range(value: int, start: int, end: int) -> str:
+    """Extrait les chiffres décimaux de rang `start` à `end` d'un entier.
+
+    Les rangs partent de 1 et sont comptés depuis le chiffre le plus
+    significatif de `|value|`. Aucune représentation décimale n'est
+    construite : une division par une puissance de 10 écarte les chiffres
+    de poids faible, un reste les chiffres de tête, et seuls les chiffres
+    demandés sont convertis en texte.
+
+    Args:
+        value (int): L'entier considéré (éventuellement négatif).
+        start (int): Le rang du premier chiffre, inclus.
+        end (int): Le rang du dernier chiffre, inclus.
+
+    Returns:
+        str: Les `end - start + 1` chiffres demandés, zéros de tête compris.
+
+    Raises:
+        ValueError: Si l'intervalle est vide ou dépasse le nombre de
+            chiffres de `value`.
+    """
+    digits = count_digits(value)
+    if not 1 <= start <= end <= digits:
+        raise ValueError(
+            f"Les chiffres {start} à {end} sont hors du nombre, qui compte {digits} chiffres."
+        )
+    width = end - start + 1
+    return str(abs(value) // 10 ** (digits - end) % 10**width).zfill(width)
+
+
+def append_csv(path: str, n: int, results: List[CalculationResult]) -> None:
+    """Ajoute les résultats d'une exécution au journal CSV, une ligne par algorithme.
+
+    Le fichier est créé avec sa ligne d'en-tête (`CSV_COLUMNS`) s'il n'existe
+    pas ou est vide ; les exécutions suivantes y ajoutent leurs lignes. Les
+    résultats valides d'algorithmes en désaccord ont pour issue `mismatch`.
+
+    Args:
+        path (str): Le chemin du journal.
+        n (int): L'indice calculé.
+        results (List[CalculationResult]): Les résultats des algorithmes.
+
+    Raises:
+        OSError: Si le fichier ne peut pas être écrit.
+    """
+    timestamp = datetime.now(timezone.utc).isoformat(timespec="seconds")
+    machine = [socket.gethostname(), os.cpu_count(), platform.python_version()]
+    mismatch = len({r.value for r in results if r.success}) > 1
+    digits: Dict[int, int] = {}
+    with open(path, "a", newline="", encoding="utf-8") as stream:
+        writer = csv.writer(stream)
+        if stream.tell() == 0:
+            writer.writerow(CSV_COLUMNS)
+        for r in results:
+            size: List[Any] = ["", ""]
+            if r.success:
+                if r.value not in digits:
+                    digits[r.value] = count_digits(r.value)
+                size = [digits[r.value], r.value.bit_length()]
+            outcome = "mismatch" if mismatch and r.success else r.outcome
+            writer.writerow(
+                [timestamp, *machine, r.algorithm, n, int(r.duration * 1e9), *size, outcome]
+            )
@@ -0,0 +1,156 @@
+"""
+Module du profilage d'un calcul, pour optimiser les chemins critiques.
+
+Avec `--profile`, l'application profile le calcul lui-même (et non le
+démarrage ni l'affichage du résultat) et écrit le profil sur disque, sans
+qu'il soit nécessaire d'enrober la CLI d'un harnais séparé :
+
+- `cpu` : profil `cProfile` des fonctions exécutées, au format `pstats`
+  (`python -m pstats pyfibonacci.prof`, ou un visualiseur comme snakeviz) ;
+- `mem` : instantané `tracemalloc` des allocations encore vivantes en fin de
+  calcul, avec leur pile d'appel (`tracemalloc.Snapshot.load`) ;
+- `trace` : trace d'exécution au format « Trace Event » (JSON), lisible par
+  `chrome://tracing` ou Perfetto. Chaque reprise d'une coroutine y apparaît
+  comme une tranche distincte, ce qui montre l'entrelacement des tâches sur
+  la boucle d'événements.
+
+Comme pour la mesure de la mémoire (voir `memory`), seul le processus
+principal est profilé : les produits délégués au pool de processus
+n'apparaissent que par leur attente.
+"""
+
+import cProfile
+import json
+import os
+import sys
+import threading
+import time
+import tracemalloc
+from typing import Any, Dict, List, Optional, Tuple
+
+# Modes de profilage acceptés par `--profile`.
+PROFILE_MODES = ("cpu", "mem", "trace")
+
+# Fichier de destination par défaut de chaque mode.
+DEFAULT_PROFILE_PATHS = {
+    "cpu": "pyfibonacci.prof",
+    "mem": "pyfibonacci.tracemalloc",
+    "trace": "pyfibonacci.trace.json",
+}
+
+# Nombre de cadres de pile conservés par allocation en mode `mem`.
+_TRACEMALLOC_FRAMES = 25
+
+# Événement de la trace : (phase, nom, fichier, horodatage en ns, thread).
+_TraceEvent = Tuple[str, str, str, int, int]
+
+
+class Profiler:
+    """Profileur d'un calcul, démarré par `start_profiling`.
+
+    Attributes:
+        mode (str): Le mode de profilage (voir `PROFILE_MODES`).
+    """
+
+    def __init__(self, mode: str) -> None:
+        if mode not in PROFILE_MODES:
+            raise ValueError(f"Mode de profilage inconnu : '{mode}'.")
+        self.mode = mode
+        self._profile: Optional[cProfile.Profile] = None
+        self._snapshot: Optional[tracemalloc.Snapshot] = None
+        self._owns_tracemalloc = False
+        self._events: List[_TraceEvent] = []
+        self._depths: Dict[int, int] = {}
+        self._origin = 0
+
+    def start(self) -> None:
+        """Démarre la collecte."""
+        if self.mode == "cpu":
+            self._profile = cProfile.Profile()
+            self._profile.enable()
+        elif self.mode == "mem":
+            if not tracemalloc.is_tracing():
+                tracemalloc.start(_TRACEMALLOC_FRAMES)
+                self._owns_tracemalloc = True
+        else:
+            self._origin = time.perf_counter_ns()
+            threading.setprofile(self._record)
+            sys.setprofile(self._record)
+
+    def stop(self) -> None:
+        """Arrête la collecte ; le profil reste à écrire par `write`."""
+        if self.mode == "cpu" and self._profile is not None:
+            self._profile.disable()
+        elif self.mode == "mem" and tracemalloc.is_tracing():
+            self._snapshot = tracemalloc.take_snapshot()
+            if self._owns_tracemalloc:
+                tracemalloc.stop()
+        elif self.mode == "trace":
+            sys.setprofile(None)
+            threading.setprofile(None)
+
+    def _record(self, frame: Any, event: str, arg: Any) -> None:
+        """Enregistre l'entrée dans une fonction Python ou sa sortie.
+
+        Les sorties des fonctions entrées avant le démarrage (celle de
+        `start`, notamment) n'ont pas d'entrée correspondante : elles sont
+        ignorées, pour que la trace reste bien parenthésée.
+        """
+        if event != "call" and event != "return":
+            return
+        tid = threading.get_ident()
+        depth = self._depths.get(tid, 0)
+        if event == "return" and depth == 0:
+            return
+        self._depths[tid] = depth + 1 if event == "call" else depth - 1
+        code = frame.f_code
+        self._events.append(
+            (
+                "B" if event == "call" else "E",
+                code.co_qualname,
+                f"{code.co_filename}:{code.co_firstlineno}",
+                time.perf_counter_ns() - self._origin,
+                tid,
+            )
+        )
+
+    def write(self, path: str) -> None:
+        """Écrit le profil collecté.
+
+        Args:
+            path (str): Le fichier de destination.
+
+        Raises:
+            OSError: Si le fichier ne peut être écrit.
+        """
+        if self.mode == "cpu":
+            if self._profile is not None:
+                self._profile.dump_stats(path)
+        elif self.mode == "mem":
+            if self._snapshot is not None:
+                self._snapshot.dump(path)
+        else:
+            pid = os.getpid()
+            document = {
+                "traceEvents": [
+                    {"ph": phase, "name": name, "cat": location, "ts": ns / 1000, "pid": pid, "tid": tid}
+                    for phase, name, location, ns, tid in self._events
+                ],
+                "displayTimeUnit": "ms",
+            }
+            with open(path, "w", encoding="utf-8") as stream:
+                json.dump(document, stream)
+
+
+def start_profiling(mode: str) -> Profiler:
+    """Démarre le profilage d'un calcul.
+
+    Args:
+        mode (str): Le mode de profilage (voir `PROFILE_MODES`).
+
+    Returns:
+        Profiler: Le profileur, à arrêter par `Profiler.stop`.
+    """
+    profiler = Profiler(mode)
+    profiler.start()
+    return profiler
@@ -3,15 +3,150 @@ Module pour la gestion de la barre de progression de l'interface CLI.
 
 Ce module fournit un gestionnaire asynchrone pour afficher et mettre à jour
 une barre de progression `tqdm` en fonction des messages reçus via une
-`asyncio.Queue`.
+`asyncio.Queue`. Des modes textuels (lignes de pourcentage ou événements
+JSON) sont également proposés pour les environnements non interactifs.
 """
 
 import asyncio
+import json
+import sys
+import time
+from typing import Any, Callable, Dict, Optional, TextIO
 from tqdm.asyncio import tqdm
 
+# Modes d'affichage de la progression acceptés par `progress_bar_manager`.
+# Le mode `multi` est propre à `multi_progress_manager`.
+PROGRESS_MODES = ("bar", "plain", "json", "none", "multi")
+
+# Intervalle minimal, en secondes, entre deux lignes des modes textuels.
+REFRESH_INTERVAL = 0.1
+
+# Statuts du message final d'une file de progression : `done` pour un calcul
+# abouti, sinon l'issue de l'échec (voir `CalculationResult.outcome`).
+FINAL_STATUSES = ("done", "timeout", "cancel", "memory", "error")
+
+
+def final_status(outcome: str) -> str:
+    """Retourne le statut du message final correspondant à l'issue d'un calcul.
+
+    Args:
+        outcome (str): L'issue du calcul (`success`, `timeout`, `cancel`...).
+
+    Returns:
+        str: `"done"` pour un succès, l'issue elle-même sinon.
+    """
+    return "done" if outcome == "success" else outcome
+
+
+class _TextProgress:
+    """Rapporteur de progression écrivant des lignes de texte.
+
+    En mode `plain`, chaque ligne est un pourcentage entier (`37%`) ; en mode
+    `json`, chaque ligne est un objet `{"percent": 37.5}`, et le dernier porte
+    en plus le statut final (`{"percent": 100.0, "status": "done"}`), que le
+    calcul ait abouti ou non. Aucun retour chariot ni séquence ANSI n'est
+    émis, ce qui rend la sortie exploitable dans des journaux de CI ou par un
+    autre programme. En mode `none`, rien n'est écrit.
+    """
+
+    def __init__(self, total: int, mode: str, stream: TextIO) -> None:
+        self.total = max(total, 1)
+        self.mode = mode
+        self.stream = stream
+        self.count = 0
+        self.last_emit = 0.0
+        self.last_percent = -1.0
+
+    def _emit(self, percent: float, status: Optional[str] = None) -> None:
+        if self.mode == "plain":
+            suffix = f" ({status})" if status not in (None, "done") else ""
+            print(f"{int(percent)}%{suffix}", file=self.stream, flush=True)
+        elif self.mode == "json":
+            event: Dict[str, Any] = {"percent": round(percent, 2)}
+            if status is not None:
+                event["status"] = status
+            print(json.dumps(event), file=self.stream, flush=True)
+        self.last_emit = time.monotonic()
+        self.last_percent = percent
+
+    def advance(self, steps: int) -> None:
+        """Avance de `steps` pas, en respectant l'intervalle de rafraîchissement."""
+        self.count += steps
+        percent = min(100.0, 100.0 * self.count / self.total)
+        if percent != self.last_percent and time.monotonic() - self.last_emit >= REFRESH_INTERVAL:
+            self._emit(percent)
+
+    def finish(self, status: str = "done") -> None:
+        """Émet l'événement final.
+
+        Un calcul abouti termine à 100 % ; un échec conserve le dernier
+        pourcentage atteint. En mode `plain`, la ligne à 100 % d'un succès
+        n'est pas répétée si elle a déjà été écrite.
+        """
+        percent = 100.0 if status == "done" else min(100.0, 100.0 * self.count / self.total)
+        if self.mode == "json" or status != "done" or self.last_percent < 100.0:
+            self._emit(percent, status)
+
+
+async def _consume_progress(
+    queue: asyncio.Queue,
+    advance: Callable[[int], None],
+    finish: Callable[[str], None],
+    idle_timeout: Optional[float] = 1.0,
+) -> None:
+    """Lit les messages de progression et les transmet à un rapporteur.
+
+    Args:
+        queue (asyncio.Queue): La file d'attente des messages.
+        advance (Callable[[int], None]): Appelée pour chaque avancée.
+        finish (Callable[[str], None]): Appelée à la réception du message
+            final, avec son statut (voir `FINAL_STATUSES`).
+        idle_timeout (Optional[float]): Durée d'inactivité, en secondes, au
+            terme de laquelle une file vide est considérée comme abandonnée.
+            `None` attend le message final indéfiniment.
+    """
+    while True:
+        try:
+            # Attend un message avec un timeout pour éviter un blocage infini.
+            message = await asyncio.wait_for(queue.get(), timeout=idle_timeout)
+
+            if message in FINAL_STATUSES:
+                finish(message)
+                break
+
+            if isinstance(message, int):
+                advance(message)
+
+            queue.task_done()
+        except asyncio.TimeoutError:
+            # Si la file est vide après le timeout, on suppose que la tâche
+            # productrice s'est terminée sans envoyer de message final.
+            if queue.empty():
+                break
+        except Exception:
+            # En cas d'autre erreur, on interrompt la barre de progression.
+            break
+
+
+def _finisher(pbar: tqdm) -> Callable[[str], None]:
+    """Retourne la fonction qui termine une barre `tqdm` selon le statut final."""
+
+    def _finish(status: str) -> None:
+        if status == "done":
+            pbar.n = pbar.total  # Assure que la barre atteint 100%
+        else:
+            pbar.set_postfix_str(status)
+        pbar.refresh()
+
+    return _finish
+
 
 async def progress_bar_manager(
-    queue: asyncio.Queue, total: int, description: str
+    queue: asyncio.Queue,
+    total: int,
+    description: str,
+    mode: str = "bar",
+    colour: Optional[str] = None,
 ) -> None:
     """Gère l'affichage et la mise à jour asynchrones d'une barre de progression.
 
@@ -20,35 +155,70 @@ async def progress_bar_manager(
     `tqdm`. La communication est unidirectionnelle : le gestionnaire reçoit des
     commandes de l'algorithme qui effectue le calcul.
 
+    Quel que soit le mode, la progression est écrite sur la sortie d'erreur,
+    de sorte que la sortie standard reste réservée aux résultats. Une étape
+    peut durer longtemps sans que la file soit abandonnée : l'appelant doit
+    garantir l'envoi du message final, même en cas d'échec.
+
     Args:
         queue (asyncio.Queue): La file d'attente pour recevoir les messages.
             Les messages attendus sont soit des entiers, indiquant le nombre
-            de pas à avancer, soit la chaîne "done" pour signaler la fin.
+            de pas à avancer, soit un statut final (voir `FINAL_STATUSES`) :
+            `"done"` porte la barre à 100 %, un échec la laisse au dernier
+            pas atteint et y affiche son statut.
         total (int): La valeur maximale de la barre de progression, correspondant
             à l'achèvement complet de la tâche.
         description (str): Un texte descriptif affiché à côté de la barre de
             progression.
+        mode (str): Le mode d'affichage (voir `PROGRESS_MODES`) : `bar` pour
+            la barre `tqdm`, `plain` pour des lignes de pourcentage, `json`
+            pour des événements JSON délimités par des sauts de ligne, et
+            `none` pour vider la file sans rien afficher.
+        colour (Optional[str]): La couleur de la barre `tqdm`, ou `None` pour
+            une barre sans couleur.
     """
-    with tqdm(total=total, desc=description, unit=" steps") as pbar:
-        while True:
-            try:
-                # Attend un message avec un timeout pour éviter un blocage infini.
-                message = await asyncio.wait_for(queue.get(), timeout=1.0)
-
-                if message == "done":
-                    pbar.n = pbar.total  # Assure que la barre atteint 100%
-                    pbar.refresh()
-                    break
-
-                if isinstance(message, int):
-                    pbar.update(message)
-
-                queue.task_done()
-            except asyncio.TimeoutError:
-                # Si la file est vide après le timeout, on suppose que la tâche
-                # productrice s'est terminée sans envoyer "done".
-                if queue.empty():
-                    break
-            except Exception:
-                # En cas d'autre erreur, on interrompt la barre de progression.
-                break
+    if mode != "bar":
+        reporter = _TextProgress(total, mode, sys.stderr)
+        await _consume_progress(queue, reporter.advance, reporter.finish, idle_timeout=None)
+        return
+
+    bar_options = {"colour": colour} if colour else {}
+    with tqdm(total=total, desc=description, unit=" steps", **bar_options) as pbar:
+        await _consume_progress(queue, pbar.update, _finisher(pbar), idle_timeout=None)
+
+
+async def multi_progress_manager(
+    queues: Dict[str, asyncio.Queue], total: int, colour: Optional[str] = None
+) -> None:
+    """Affiche une barre de progression par algorithme, sur des lignes distinctes.
+
+    Chaque algorithme dispose de sa propre file ; sa barre, étiquetée par son
+    nom, est redessinée en place (via la position `tqdm`, qui gère les
+    déplacements du curseur). Comme pour `progress_bar_manager`, une
+    file inactive n'est pas abandonnée : un algorithme qui ne rapporte pas de
+    progression intermédiaire (comme l'algorithme itératif) voit sa barre
+    rester à 0 % jusqu'à son message final, que l'appelant doit garantir.
+
+    Args:
+        queues (Dict[str, asyncio.Queue]): Les files de progression, indexées
+            par nom d'algorithme.
+        total (int): La valeur maximale de chaque barre.
+        colour (Optional[str]): La couleur des barres, ou `None` pour des
+            barres sans couleur.
+    """
+    bar_options = {"colour": colour} if colour else {}
+    width = max((len(name) for name in queues), default=0)
+    bars = [
+        tqdm(total=total, desc=name.ljust(width), unit=" steps", position=position, **bar_options)
+        for position, name in enumerate(queues)
+    ]
+    try:
+        await asyncio.gather(
+            *(
+                _consume_progress(queue, pbar.update, _finisher(pbar), idle_timeout=None)
+                for queue, pbar in zip(queues.values(), bars)
+            )
+        )
+    finally:
+        for pbar in bars:
+            pbar.close()
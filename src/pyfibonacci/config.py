@@ -0,0 +1,107 @@
+"""
+Module pour la persistance de la configuration de l'utilisateur.
+
+Ce module lit et écrit un fichier JSON (par défaut `~/.pyfibonacci.json`)
+contenant des valeurs par défaut pour les options de la ligne de commande,
+typiquement le seuil optimal découvert par la calibration. L'ordre de
+priorité est : option explicite > variable d'environnement
+(`PYFIBONACCI_<OPTION>`) > fichier de configuration > valeur par défaut
+codée en dur.
+"""
+
+import json
+import os
+from pathlib import Path
+from typing import Any, Dict, Optional
+
+# Variable d'environnement permettant de changer l'emplacement du fichier.
+CONFIG_PATH_ENV_VAR = "PYFIBONACCI_CONFIG"
+
+# Options de la ligne de commande pouvant être lues depuis le fichier, avec
+# leur type attendu.
+CONFIGURABLE_OPTIONS: Dict[str, type] = {
+    "threshold": int,
+}
+
+
+def get_config_path() -> Path:
+    """Retourne l'emplacement du fichier de configuration.
+
+    Returns:
+        Path: Le chemin indiqué par `PYFIBONACCI_CONFIG`, ou à défaut
+        `~/.pyfibonacci.json`.
+    """
+    override = os.environ.get(CONFIG_PATH_ENV_VAR)
+    if override:
+        return Path(override)
+    return Path.home() / ".pyfibonacci.json"
+
+
+def load_config(path: Optional[Path] = None) -> Dict[str, Any]:
+    """Charge les valeurs par défaut enregistrées dans le fichier de configuration.
+
+    Seules les options listées dans `CONFIGURABLE_OPTIONS` sont retenues ; les
+    clés inconnues sont ignorées pour rester compatible avec d'autres versions.
+
+    Args:
+        path (Optional[Path]): Le fichier à lire. Par défaut, celui retourné
+            par `get_config_path`.
+
+    Returns:
+        Dict[str, Any]: Les valeurs lues, ou un dictionnaire vide si le
+        fichier n'existe pas.
+
+    Raises:
+        ValueError: Si le fichier n'est pas un objet JSON valide ou si une
+            valeur n'a pas le type attendu.
+    """
+    path = path or get_config_path()
+    if not path.exists():
+        return {}
+
+    try:
+        data = json.loads(path.read_text(encoding="utf-8"))
+    except json.JSONDecodeError as e:
+        raise ValueError(f"Fichier de configuration invalide '{path}': {e}") from e
+    if not isinstance(data, dict):
+        raise ValueError(f"Fichier de configuration invalide '{path}': objet JSON attendu.")
+
+    config = {}
+    for key, expected_type in CONFIGURABLE_OPTIONS.items():
+        if key not in data:
+            continue
+        if not isinstance(data[key], expected_type) or isinstance(data[key], bool):
+            raise ValueError(
+                f"Fichier de configuration invalide '{path}': "
+                f"'{key}' doit être de type {expected_type.__name__}."
+            )
+        config[key] = data[key]
+    return config
+
+
+def save_config(values: Dict[str, Any], path: Optional[Path] = None) -> Path:
+    """Enregistre des valeurs dans le fichier de configuration.
+
+    Les valeurs sont fusionnées avec le contenu existant du fichier, de sorte
+    que les autres clés sont préservées.
+
+    Args:
+        values (Dict[str, Any]): Les valeurs à enregistrer.
+        path (Optional[Path]): Le fichier à écrire. Par défaut, celui retourné
+            par `get_config_path`.
+
+    Returns:
+        Path: Le chemin du fichier écrit.
+    """
+    path = path or get_config_path()
+    data: Dict[str, Any] = {}
+    if path.exists():
+        try:
+            existing = json.loads(path.read_text(encoding="utf-8"))
+            if isinstance(existing, dict):
+                data = existing
+        except json.JSONDecodeError:
+            pass
+    data.update(values)
+    path.write_text(json.dumps(data, indent=2) + "\n", encoding="utf-8")
+    return path
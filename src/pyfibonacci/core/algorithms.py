@@ -7,10 +7,97 @@ différentes.
 """
 
 import asyncio
-from typing import Tuple
+import contextlib
+import math
+import time
+from typing import AsyncIterator, Awaitable, Dict, Iterator, List, Optional, Tuple
 
-from .context import CalculationContext
-from .multiplication import multiply
+from .context import CalculationContext, CalculationProgress, StepTrace, current_progress, track_progress
+from .errors import MemoryRiskError
+from .multiplication import multiplication_path, multiply, should_parallelize, square
+from .resources import available_memory
+
+# Constantes de la formule de Binet, F(n) ≈ φ^n / √5, en base 10.
+_LOG10_PHI = math.log10((1 + math.sqrt(5)) / 2)
+_LOG10_SQRT5 = math.log10(math.sqrt(5))
+_LOG2_PHI = math.log2((1 + math.sqrt(5)) / 2)
+_LOG2_SQRT5 = math.log2(math.sqrt(5))
+
+# En deçà de cet indice, la taille de F(n) est obtenue par un calcul exact :
+# l'estimation de Binet y est faussée par le terme ψ^n, non négligeable.
+_EXACT_SIZE_LIMIT = 64
+
+# Rang d'apparition de quelques diviseurs : le plus petit indice k > 0 tel
+# que d divise F(k). Pour ces diviseurs, d divise F(n) si et seulement si k
+# divise n (F(n) est ainsi pair si et seulement si 3 divise n).
+DIVISIBILITY_RANKS: Dict[int, int] = {2: 3, 3: 4, 4: 6, 5: 5, 7: 8, 8: 6, 11: 10, 13: 7}
+
+# Taille (en bits) des opérandes à partir de laquelle les allocations d'une
+# étape sont comparées à la mémoire disponible (voir `_check_step_memory`) :
+# seules les dernières étapes, les plus coûteuses, consultent le système.
+MEMORY_CHECK_BITS = 1 << 23
+
+
+def _check_step_memory(context: CalculationContext, largest: int, count: int) -> None:
+    """Vérifie que les produits d'une étape tiennent dans la mémoire disponible.
+
+    Chaque produit compte au plus deux fois les bits du plus grand opérande ;
+    leur taille est ainsi connue avant d'être allouée.
+
+    Raises:
+        MemoryRiskError: Si les produits dépasseraient la fraction permise
+            (`CalculationContext.memory_fraction`) de la mémoire disponible.
+    """
+    bits = abs(largest).bit_length()
+    if context.memory_fraction is None or bits < MEMORY_CHECK_BITS:
+        return
+    available = available_memory()
+    if available is None:
+        return
+    required = count * (2 * bits // 8)
+    allowed = int(available * context.memory_fraction)
+    if required > allowed:
+        raise MemoryRiskError(required, allowed)
+
+
+async def _gather_products(
+    context: CalculationContext, largest: int, *products: Awaitable[int]
+) -> List[int]:
+    """Attend des multiplications indépendantes, en parallèle si elles le justifient.
+
+    Lancer les produits ensemble (`asyncio.gather`) crée une tâche par produit,
+    ce qui n'a d'intérêt que si certains sont délégués à l'exécuteur. Sous le
+    seuil de parallélisation, où toutes les multiplications sont natives et
+    s'exécutent d'un bloc, ce coût est pur surcoût : les produits sont alors
+    attendus l'un après l'autre. Le parallélisme reste borné par l'exécuteur
+    du contexte, partagé par tous les appels.
+
+    Avant les produits des grandes étapes, leur taille est comparée à la
+    mémoire disponible (voir `_check_step_memory`).
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        largest (int): Le plus grand opérande des produits, qui détermine
+            si l'un d'eux peut être délégué.
+        *products (Awaitable[int]): Les multiplications à attendre.
+
+    Returns:
+        List[int]: Les produits, dans l'ordre des arguments.
+
+    Raises:
+        MemoryRiskError: Si les produits risqueraient d'épuiser la mémoire ;
+            aucun n'est alors calculé.
+    """
+    try:
+        _check_step_memory(context, largest, len(products))
+    except MemoryRiskError:
+        for product in products:
+            if asyncio.iscoroutine(product):
+                product.close()
+        raise
+    if should_parallelize(context, largest, largest):
+        return await asyncio.gather(*products)
+    return [await product for product in products]
 
 
 def fib_iterative(n: int) -> int:
@@ -40,6 +127,171 @@ def fib_iterative(n: int) -> int:
     return b
 
 
+def fib_sum_iterative(n: int) -> int:
+    """Calcule F(1) + F(2) + ... + F(n) en additionnant les termes un à un.
+
+    La somme vaut F(n+2) - 1 ; ce calcul linéaire, indépendant de
+    l'identité, sert de référence pour la vérifier sur de petits indices.
+
+    Args:
+        n (int): Le nombre de termes (entier non-négatif).
+
+    Returns:
+        int: La somme des `n` premiers nombres de Fibonacci.
+
+    Raises:
+        ValueError: Si `n` est un entier négatif.
+    """
+    if n < 0:
+        raise ValueError("Le nombre de termes ne peut pas être négatif.")
+    total, a, b = 0, 0, 1
+    for _ in range(n):
+        a, b = b, a + b
+        total += a
+    return total
+
+
+def apply_negafibonacci_sign(n: int, value: int) -> int:
+    """Déduit F(n) pour un indice signé à partir de F(|n|).
+
+    La suite s'étend aux indices négatifs ("negafibonacci") par l'identité
+    F(-n) = (-1)^(n+1) * F(n) : pour un indice négatif pair, le résultat
+    change de signe ; pour un indice impair ou positif, il est inchangé.
+
+    Args:
+        n (int): L'indice signé demandé.
+        value (int): La valeur de F(|n|), calculée par un des algorithmes.
+
+    Returns:
+        int: La valeur de F(n), correctement signée.
+    """
+    if n < 0 and n % 2 == 0:
+        return -value
+    return value
+
+
+def fib_fast_doubling_mod(n: int, m: int) -> int:
+    """Calcule F(n) mod m via l'algorithme "Fast Doubling" modulaire.
+
+    Les identités du "Fast Doubling" sont réduites modulo `m` après chaque
+    multiplication, ce qui maintient les entiers intermédiaires à la taille
+    de `m`. Le calcul reste en O(log n) opérations, mais chacune porte sur de
+    petits nombres : même des indices astronomiques sont traités quasi
+    instantanément. Aucun seuil de parallélisation n'est donc nécessaire.
+
+    Args:
+        n (int): L'indice (entier non-négatif) de la suite.
+        m (int): Le module (entier strictement positif).
+
+    Returns:
+        int: Le plus petit résidu non-négatif de F(n) modulo `m`.
+
+    Raises:
+        ValueError: Si `n` est négatif ou si `m` n'est pas strictement positif.
+    """
+    if n < 0:
+        raise ValueError("L'indice de Fibonacci ne peut pas être négatif.")
+    if m <= 0:
+        raise ValueError("Le module doit être un entier strictement positif.")
+
+    # On parcourt les bits de n du plus significatif au moins significatif en
+    # maintenant le couple (F(k), F(k+1)) mod m.
+    fk, fk1 = 0, 1
+    for bit in bin(n)[2:]:
+        f2k = fk * ((2 * fk1 - fk) % m) % m
+        f2k1 = (fk * fk + fk1 * fk1) % m
+        if bit == "1":
+            fk, fk1 = f2k1, (f2k + f2k1) % m
+        else:
+            fk, fk1 = f2k, f2k1
+    return fk % m
+
+
+def fib_last_digits(n: int, k: int) -> str:
+    """Retourne les `k` derniers chiffres décimaux de F(n).
+
+    Le calcul est effectué modulo une puissance de 10 via
+    `fib_fast_doubling_mod`, sans jamais construire F(n). Le résultat est
+    complété par des zéros à gauche jusqu'à `k` chiffres, sauf si F(n)
+    compte lui-même moins de `k` chiffres : il est alors écrit tel quel.
+
+    Pour trancher ce dernier cas, log10 F(n) est estimé par la formule de
+    Binet. Si F(n) peut être inférieur à 10^(k+1), le calcul est mené modulo
+    10^(k+2) : le résidu est alors F(n) lui-même, et la comparaison à 10^k
+    est exacte, même lorsque l'estimation flottante est imprécise.
+
+    Args:
+        n (int): L'indice (entier non-négatif) de la suite.
+        k (int): Le nombre de chiffres souhaités (entier strictement positif).
+
+    Returns:
+        str: Les `k` derniers chiffres de F(n), ou F(n) complet s'il est plus
+        court.
+
+    Raises:
+        ValueError: Si `n` est négatif ou si `k` n'est pas strictement positif.
+    """
+    if k <= 0:
+        raise ValueError("Le nombre de chiffres doit être un entier strictement positif.")
+
+    if n * _LOG10_PHI - _LOG10_SQRT5 < k + 1:
+        value = fib_fast_doubling_mod(n, 10 ** (k + 2))
+        if value < 10**k:
+            return str(value)
+        return str(value % 10**k).zfill(k)
+    return str(fib_fast_doubling_mod(n, 10**k)).zfill(k)
+
+
+def predict_bit_length(n: int) -> int:
+    """Estime la longueur en bits de F(n) sans calculer F(n).
+
+    D'après la formule de Binet, log2 |F(n)| ≈ n·log2(φ) − log2(√5). Pour de
+    grands indices, l'imprécision du calcul flottant peut décaler le
+    résultat d'une unité lorsque n·log2(φ) − log2(√5) est très proche d'un
+    entier ; l'estimation est sinon exacte.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite ; |F(-n)| = F(n).
+
+    Returns:
+        int: La longueur en bits de |F(n)|, à une unité près.
+    """
+    n = abs(n)
+    if n < _EXACT_SIZE_LIMIT:
+        return fib_iterative(n).bit_length()
+    return math.floor(n * _LOG2_PHI - _LOG2_SQRT5) + 1
+
+
+def predict_digits(n: int) -> int:
+    """Estime le nombre de chiffres décimaux de F(n) sans calculer F(n).
+
+    Voir `predict_bit_length` : l'estimation repose sur la même formule, en
+    base 10, et peut différer d'une unité pour de très grands indices.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite.
+
+    Returns:
+        int: Le nombre de chiffres de |F(n)|, signe exclu, à une unité près.
+    """
+    n = abs(n)
+    if n < _EXACT_SIZE_LIMIT:
+        return len(str(fib_iterative(n)))
+    return math.floor(n * _LOG10_PHI - _LOG10_SQRT5) + 1
+
+
+def divisibility_facts(n: int) -> Dict[int, bool]:
+    """Détermine, à partir du seul indice, les diviseurs de F(n) parmi `DIVISIBILITY_RANKS`.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite.
+
+    Returns:
+        Dict[int, bool]: Pour chaque diviseur, `True` s'il divise F(n).
+    """
+    return {divisor: n % rank == 0 for divisor, rank in DIVISIBILITY_RANKS.items()}
+
+
 async def fib_matrix(context: CalculationContext, n: int) -> int:
     """Calcule F(n) via l'exponentiation matricielle.
 
@@ -48,6 +300,13 @@ async def fib_matrix(context: CalculationContext, n: int) -> int:
     l'exponentiation par carré confère à cet algorithme une complexité
     temporelle de O(log n).
 
+    Comme pour le "Fast Doubling", chaque bit de l'exposant n-1 constitue une
+    étape, signalée à la `progress_queue` du contexte, au suivi d'avancement
+    (voir `track_progress`) et au rapporteur du contexte une fois son carré
+    terminé : le travail estimé (`CalculationProgress.work_fraction`), qui
+    pondère les étapes par la taille croissante des opérandes, s'applique
+    ainsi aux deux algorithmes.
+
     Args:
         context (CalculationContext): Le contexte de calcul pour la
             multiplication parallélisée des grands nombres.
@@ -71,7 +330,10 @@ async def fib_matrix(context: CalculationContext, n: int) -> int:
         a, b, c, d = A
         e, f, g, h = B
 
-        ae, bg, af, bh, ce, dg, cf, dh = await asyncio.gather(
+        largest = max(map(abs, A + B))
+        ae, bg, af, bh, ce, dg, cf, dh = await _gather_products(
+            context,
+            largest,
             multiply(context, a, e),
             multiply(context, b, g),
             multiply(context, a, f),
@@ -83,26 +345,142 @@ async def fib_matrix(context: CalculationContext, n: int) -> int:
         )
         return (ae + bg, af + bh, ce + dg, cf + dh)
 
+    async def square_matrix(A: Matrix) -> Matrix:
+        """Élève une matrice 2x2 au carré en 5 multiplications, dont 3 carrés.
+
+        Les puissances de la matrice de Fibonacci sont symétriques (b == c) :
+        le produit b·c est alors lui-même un carré.
+        """
+        a, b, c, d = A
+        bc_product = square(context, b) if b == c else multiply(context, b, c)
+        aa, dd, bc, b_trace, c_trace = await _gather_products(
+            context,
+            abs(a) + abs(d),
+            square(context, a),
+            square(context, d),
+            bc_product,
+            multiply(context, b, a + d),
+            multiply(context, c, a + d),
+        )
+        return (aa + bc, b_trace, c_trace, dd + bc)
+
     async def matrix_power(A: Matrix, m: int) -> Matrix:
         """Élève une matrice à la puissance m par exponentiation par carré."""
         if m == 0:
             return (1, 0, 0, 1)  # Matrice identité
         if m == 1:
+            _report_step(context)  # Le bit de poids fort, sans calcul.
             return A
 
         if m % 2 == 0:
             half = await matrix_power(A, m // 2)
-            return await multiply_matrices(half, half)
+            result = await square_matrix(half)
         else:
             half = await matrix_power(A, (m - 1) // 2)
-            temp = await multiply_matrices(half, half)
-            return await multiply_matrices(A, temp)
-
-    F: Matrix = (1, 1, 1, 0)
-    result_matrix = await matrix_power(F, n - 1)
+            temp = await square_matrix(half)
+            result = await multiply_matrices(A, temp)
+        _report_step(context)
+        return result
+
+    with _reported_progress(context) as progress:
+        if progress is not None:
+            progress.completed, progress.total = 0, (n - 1).bit_length()
+        F: Matrix = (1, 1, 1, 0)
+        result_matrix = await matrix_power(F, n - 1)
     return result_matrix[0]
 
 
+@contextlib.contextmanager
+def _reported_progress(context: CalculationContext) -> Iterator[Optional[CalculationProgress]]:
+    """Retourne l'avancement suivi par `track_progress`, s'il y en a un.
+
+    Un algorithme appelé directement, hors de `track_progress`, suit alors
+    son propre avancement si le contexte a un rapporteur (voir
+    `CalculationContext.reporter`), pour pouvoir le lui transmettre.
+    """
+    progress = current_progress()
+    if progress is not None or context.reporter is None:
+        yield progress
+        return
+    with track_progress() as progress:
+        yield progress
+
+
+def _report_step(context: CalculationContext) -> None:
+    """Signale une étape terminée à la progression, au suivi d'avancement et au chien de garde."""
+    if context.progress_queue:
+        context.progress_queue.put_nowait(1)
+    if context.watchdog is not None:
+        context.watchdog.feed()
+    progress = current_progress()
+    if progress is not None:
+        progress.completed += 1
+        if context.reporter is not None:
+            context.reporter(progress)
+
+
+async def _fast_doubling_pair(context: CalculationContext, m: int) -> Tuple[int, int]:
+    """Calcule le couple (F(m), F(m+1)) par "Fast Doubling".
+
+    Chaque bit de `m` constitue une étape, signalée à la `progress_queue` du
+    contexte, au suivi d'avancement (voir `track_progress`) et au rapporteur
+    du contexte une fois ses multiplications terminées, puis mesurée par le
+    traceur du contexte s'il y en a un. Tous les algorithmes bâtis sur ce
+    noyau (Fibonacci, Lucas) rapportent ainsi leur progression de la même
+    manière.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        m (int): L'indice (entier non-négatif) de la suite.
+
+    Returns:
+        Tuple[int, int]: Le couple (F(m), F(m+1)).
+    """
+    with _reported_progress(context) as progress:
+        if progress is not None:
+            progress.completed, progress.total = 0, m.bit_length()
+        return await _fast_doubling_step(context, m)
+
+
+async def _fast_doubling_step(context: CalculationContext, m: int) -> Tuple[int, int]:
+    """Calcule récursivement (F(m), F(m+1)), une étape par bit de `m`."""
+    if m == 0:
+        return (0, 1)
+
+    fk, fk1 = await _fast_doubling_step(context, m // 2)
+    start = time.perf_counter() if context.tracer is not None else 0.0
+
+    # Les trois multiplications sont indépendantes : elles sont lancées
+    # ensemble lorsque `multiply` peut déléguer au pool de processus celles
+    # dont les opérandes dépassent le seuil.
+    term = 2 * fk1 - fk
+    largest = max(fk1, term)
+    fk_squared, fk1_squared, f2k = await _gather_products(
+        context,
+        largest,
+        square(context, fk),
+        square(context, fk1),
+        multiply(context, fk, term),
+    )
+    f2k1 = fk1_squared + fk_squared
+    _report_step(context)
+    if context.tracer is not None:
+        context.tracer(
+            StepTrace(
+                step=m.bit_length(),
+                bit=m & 1,
+                operand_bits=largest.bit_length(),
+                path=multiplication_path(context, largest, largest),
+                duration=time.perf_counter() - start,
+            )
+        )
+
+    if m % 2 == 0:
+        return (f2k, f2k1)
+    else:
+        return (f2k1, f2k + f2k1)
+
+
 async def fib_fast_doubling(context: CalculationContext, n: int) -> int:
     """Calcule F(n) via l'algorithme "Fast Doubling".
 
@@ -129,28 +507,154 @@ async def fib_fast_doubling(context: CalculationContext, n: int) -> int:
     if n == 0:
         return 0
 
-    async def _fib_fast_doubling(m: int) -> Tuple[int, int]:
-        """Fonction récursive qui calcule et retourne (F(m), F(m+1))."""
-        if context.progress_queue:
-            context.progress_queue.put_nowait(1)
+    result, _ = await _fast_doubling_pair(context, n)
+    return result
+
 
-        if m == 0:
-            return (0, 1)
+async def fib_fast_doubling_pair(context: CalculationContext, n: int) -> Tuple[int, int]:
+    """Calcule le couple (F(n), F(n+1)) via l'algorithme "Fast Doubling".
 
-        fk, fk1 = await _fib_fast_doubling(m // 2)
+    Le "Fast Doubling" produit naturellement les deux termes consécutifs ;
+    cette variante les retourne ensemble, ce qui évite un second calcul
+    complet pour poursuivre la suite ou vérifier une identité.
 
-        fk_squared, fk1_squared = await asyncio.gather(
-            multiply(context, fk, fk), multiply(context, fk1, fk1)
-        )
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (entier non-négatif) de la suite.
 
-        term = 2 * fk1 - fk
-        f2k = await multiply(context, fk, term)
-        f2k1 = fk1_squared + fk_squared
+    Returns:
+        Tuple[int, int]: Le couple (F(n), F(n+1)).
 
-        if m % 2 == 0:
-            return (f2k, f2k1)
-        else:
-            return (f2k1, f2k + f2k1)
+    Raises:
+        ValueError: Si `n` est un entier négatif.
+    """
+    if n < 0:
+        raise ValueError("L'indice de Fibonacci ne peut pas être négatif.")
+    return await _fast_doubling_pair(context, n)
 
-    result, _ = await _fib_fast_doubling(n)
-    return result
+
+async def fib_sequence(context: CalculationContext, start: int) -> AsyncIterator[int]:
+    """Produit F(start), F(start+1), F(start+2)... sans fin.
+
+    Seul le couple initial est calculé par "Fast Doubling" (via
+    `fib_fast_doubling_pair`) ; chaque terme suivant est la somme des deux
+    précédents, soit une simple addition par terme. La récurrence restant
+    valable pour les indices négatifs, `start` peut l'être.
+
+    Le générateur rend la main à la boucle d'événements avant chaque terme :
+    une annulation de l'appelant l'interrompt sans délai. Il ne détient
+    aucune ressource, mais un consommateur qui s'arrête avant la fin doit le
+    fermer (`contextlib.aclosing`) pour qu'il soit libéré sans attendre le
+    ramasse-miettes.
+
+    Args:
+        context (CalculationContext): Le contexte du calcul du couple initial.
+        start (int): L'indice (éventuellement négatif) du premier terme.
+
+    Yields:
+        int: Les termes successifs de la suite, à partir de F(start).
+    """
+    if start >= 0:
+        current, following = await fib_fast_doubling_pair(context, start)
+    else:
+        # (F(|a|-1), F(|a|)) fournit F(a) et F(a+1) au signe près.
+        below, above = await fib_fast_doubling_pair(context, -start - 1)
+        current = apply_negafibonacci_sign(start, above)
+        following = apply_negafibonacci_sign(start + 1, below)
+    while True:
+        await asyncio.sleep(0)
+        yield current
+        current, following = following, current + following
+
+
+async def verify_cassini(context: CalculationContext, n: int, value: int) -> None:
+    """Vérifie un résultat F(n) par l'identité de Cassini.
+
+    Le couple (F(m), F(m+1)), avec m = |n|, est recalculé par
+    `fib_fast_doubling_pair` ; F(m-1) s'en déduit par différence avec le
+    résultat à vérifier, puis l'identité F(m-1)·F(m+1) − F(m)² = (−1)^m est
+    évaluée sur les entiers complets. Contrairement à la vérification de
+    Binet, limitée aux premiers chiffres, elle porte sur tous les bits du
+    résultat : une corruption silencieuse d'un produit (à une taille que les
+    tests ne couvrent pas, par exemple) la met en défaut.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (éventuellement négatif) de la suite.
+        value (int): La valeur calculée de F(n).
+
+    Raises:
+        ValueError: Si le résultat ne vérifie pas l'identité de Cassini.
+    """
+    m = abs(n)
+    current = apply_negafibonacci_sign(n, value)
+    if m == 0:
+        if current != 0:
+            raise ValueError(f"F(0) = 0 attendu, {value} obtenu.")
+        return
+    _, following = await fib_fast_doubling_pair(context, m)
+    previous = following - current
+    cassini = await multiply(context, previous, following) - await square(context, current)
+    if cassini != (-1) ** m:
+        raise ValueError(f"L'identité de Cassini n'est pas vérifiée pour F({n}).")
+
+
+def _build_lucas_lookup_table(size: int) -> Tuple[int, ...]:
+    """Construit la table des `size` premiers nombres de Lucas."""
+    table = [2, 1]
+    while len(table) < size:
+        table.append(table[-1] + table[-2])
+    return tuple(table[:size])
+
+
+# Table des nombres de Lucas L(0)..L(92). Comme pour les entiers 64 bits de
+# l'implémentation d'origine, ces valeurs sont servies sans aucun calcul.
+LUCAS_LOOKUP_TABLE: Tuple[int, ...] = _build_lucas_lookup_table(93)
+
+
+def apply_negalucas_sign(n: int, value: int) -> int:
+    """Déduit L(n) pour un indice signé à partir de L(|n|).
+
+    Les nombres de Lucas s'étendent aux indices négatifs par l'identité
+    L(-n) = (-1)^n * L(n) : pour un indice négatif impair, le résultat
+    change de signe.
+
+    Args:
+        n (int): L'indice signé demandé.
+        value (int): La valeur de L(|n|).
+
+    Returns:
+        int: La valeur de L(n), correctement signée.
+    """
+    if n < 0 and n % 2 != 0:
+        return -value
+    return value
+
+
+async def lucas_fast_doubling(context: CalculationContext, n: int) -> int:
+    """Calcule le n-ième nombre de Lucas L(n) via le "Fast Doubling".
+
+    Les nombres de Lucas (2, 1, 3, 4, 7, 11, ...) obéissent à la même
+    récurrence que ceux de Fibonacci. L'algorithme réutilise le noyau
+    `_fast_doubling_pair`, qui fournit F(n) et F(n+1), puis applique
+    l'identité L(n) = F(n-1) + F(n+1) = 2*F(n+1) - F(n). Les petits indices
+    sont servis directement par `LUCAS_LOOKUP_TABLE`, sauf si le contexte
+    l'interdit (`use_lookup_table`).
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (entier non-négatif) de la suite.
+
+    Returns:
+        int: Le n-ième nombre de Lucas.
+
+    Raises:
+        ValueError: Si `n` est un entier négatif.
+    """
+    if n < 0:
+        raise ValueError("L'indice de Lucas ne peut pas être négatif.")
+    if context.use_lookup_table and n < len(LUCAS_LOOKUP_TABLE):
+        return LUCAS_LOOKUP_TABLE[n]
+
+    fn, fn1 = await _fast_doubling_pair(context, n)
+    return 2 * fn1 - fn
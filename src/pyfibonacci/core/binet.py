@@ -0,0 +1,176 @@
+"""
+Module de vérification des résultats par la formule de Binet.
+
+La formule close F(n) = (φ^n - ψ^n) / √5 est évaluée en précision décimale
+arbitraire (module `decimal`) pour obtenir les premiers chiffres significatifs
+de F(n) sans passer par les algorithmes de "doubling". Comparer ces chiffres à
+un résultat exact constitue une vérification peu coûteuse et indépendante :
+elle détecte un bug même lorsque tous les algorithmes, qui partagent leur
+code de multiplication, s'accordent sur une valeur erronée.
+
+Le module fournit aussi le nombre d'or φ en précision arbitraire et le
+rapport F(n)/F(n-1), dont la convergence vers φ illustre la même formule :
+ψ^n s'évanouissant, deux termes consécutifs sont dans le rapport φ.
+"""
+
+import math
+from decimal import MAX_EMAX, MIN_EMIN, Decimal, localcontext
+from typing import Tuple
+
+from .algorithms import apply_negafibonacci_sign
+
+# Nombre de chiffres significatifs comparés par défaut.
+DEFAULT_VERIFIED_DIGITS = 15
+
+# Nombre de bits de tête conservés par chiffre demandé dans `fibonacci_ratio`,
+# un peu plus que log2(10).
+_RATIO_BITS_PER_DIGIT = 4
+
+
+def _binet(n: int, digits: int) -> Decimal:
+    """Évalue (φ^n - ψ^n) / √5 avec une précision suffisante pour `digits` chiffres.
+
+    L'erreur relative sur φ est amplifiée d'un facteur n par l'exponentiation ;
+    la précision de travail inclut donc autant de chiffres que n en compte.
+    """
+    with localcontext() as ctx:
+        ctx.prec = digits + len(str(n)) + 10
+        ctx.Emax, ctx.Emin = MAX_EMAX, MIN_EMIN
+        sqrt5 = Decimal(5).sqrt()
+        phi = (1 + sqrt5) / 2
+        psi = (1 - sqrt5) / 2
+        return (phi**n - psi**n) / sqrt5
+
+
+def _leading_digits(value: int, count: int) -> Tuple[int, int]:
+    """Retourne les `count` premiers chiffres d'un entier positif et son nombre de chiffres.
+
+    Seule la tête du nombre est convertie en texte : le nombre de chiffres est
+    estimé à partir de la longueur en bits, puis une division entière par une
+    puissance de 10 élimine la queue.
+    """
+    estimate = int(value.bit_length() * math.log10(2))
+    shift = max(estimate - count - 2, 0)
+    head = str(value // 10**shift)
+    return int(head[:count]), len(head) + shift
+
+
+def leading_digits(n: int, count: int) -> Tuple[str, int]:
+    """Retourne les `count` premiers chiffres de |F(n)| sans calculer F(n).
+
+    La formule de Binet est évaluée avec `count` chiffres significatifs, plus
+    une marge (voir `_binet`), puis tronquée : le coût ne dépend que de
+    `count` et du nombre de chiffres de `n`, et non de la taille de F(n). Le
+    dernier chiffre retourné n'est faux que si les chiffres suivants de F(n)
+    forment une suite de 9 (ou de 0) plus longue que la marge, ce qui ne se
+    produit pas en pratique.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite.
+        count (int): Le nombre de chiffres souhaités (strictement positif).
+
+    Returns:
+        Tuple[str, int]: Les premiers chiffres de |F(n)| (moins de `count` si
+        F(n) est plus court) et le nombre total de chiffres de F(n).
+
+    Raises:
+        ValueError: Si `count` n'est pas strictement positif.
+    """
+    if count <= 0:
+        raise ValueError("Le nombre de chiffres doit être un entier strictement positif.")
+    if n == 0:
+        return "0", 1
+    approximation = _binet(abs(n), count)
+    length = approximation.adjusted() + 1
+    if length <= count:
+        return str(int(approximation.to_integral_value())), length
+    coefficient = approximation.as_tuple().digits
+    return "".join(map(str, coefficient[:count])), length
+
+
+def verify_leading_digits(
+    result: int, n: int, digits: int = DEFAULT_VERIFIED_DIGITS
+) -> None:
+    """Vérifie un résultat F(n) contre la formule de Binet.
+
+    Si F(n) compte au plus `digits` chiffres, la comparaison est exacte. Sinon,
+    le signe, le nombre de chiffres et les `digits` premiers chiffres sont
+    comparés ; une différence d'une unité sur le dernier chiffre comparé est
+    tolérée, l'approximation pouvant tomber de part et d'autre d'un arrondi.
+
+    Args:
+        result (int): La valeur calculée de F(n).
+        n (int): L'indice (éventuellement négatif) de la suite.
+        digits (int): Le nombre de chiffres significatifs à comparer.
+
+    Raises:
+        ValueError: Si le résultat ne concorde pas avec la formule de Binet.
+    """
+    if n == 0:
+        if result != 0:
+            raise ValueError(f"F(0) = 0 attendu, {result} obtenu.")
+        return
+
+    approximation = _binet(abs(n), digits)
+    expected_length = approximation.adjusted() + 1
+
+    if expected_length <= digits:
+        expected = apply_negafibonacci_sign(n, int(approximation.to_integral_value()))
+        if result != expected:
+            raise ValueError(f"F({n}) = {expected} attendu, {result} obtenu.")
+        return
+
+    if (result < 0) != (apply_negafibonacci_sign(n, 1) < 0) or result == 0:
+        raise ValueError(f"Le signe de F({n}) est incorrect.")
+    head, length = _leading_digits(abs(result), digits)
+    if length != expected_length:
+        raise ValueError(
+            f"F({n}) devrait compter {expected_length} chiffres, le résultat en compte {length}."
+        )
+    expected_head = int(approximation.scaleb(digits - expected_length))
+    if abs(head - expected_head) > 1:
+        raise ValueError(
+            f"Les {digits} premiers chiffres de F({n}) devraient être {expected_head}, "
+            f"le résultat commence par {head}."
+        )
+
+
+def golden_ratio(digits: int) -> Decimal:
+    """Retourne le nombre d'or φ = (1 + √5) / 2.
+
+    Args:
+        digits (int): Le nombre de chiffres significatifs souhaités.
+
+    Returns:
+        Decimal: φ, arrondi à `digits` chiffres significatifs.
+    """
+    with localcontext() as ctx:
+        ctx.prec = digits
+        return (1 + Decimal(5).sqrt()) / 2
+
+
+def fibonacci_ratio(current: int, previous: int, digits: int) -> Decimal:
+    """Retourne le rapport de deux termes consécutifs, F(n)/F(n-1).
+
+    Seuls les bits de tête des deux termes sont convertis en `Decimal` : le
+    coût ne dépend que de `digits`, et non de la taille des termes, ce qui
+    évite la conversion décimale complète d'un grand F(n).
+
+    Args:
+        current (int): Le terme F(n).
+        previous (int): Le terme F(n-1), non nul.
+        digits (int): Le nombre de chiffres significatifs souhaités.
+
+    Returns:
+        Decimal: Le rapport, arrondi à `digits` chiffres significatifs.
+
+    Raises:
+        ZeroDivisionError: Si `previous` est nul.
+    """
+    if previous == 0:
+        raise ZeroDivisionError("Le rapport F(n)/F(n-1) n'est pas défini pour F(n-1) = 0.")
+    kept = _RATIO_BITS_PER_DIGIT * digits + 64
+    shift = max(min(abs(current).bit_length(), abs(previous).bit_length()) - kept, 0)
+    with localcontext() as ctx:
+        ctx.prec = digits
+        return Decimal(current >> shift) / Decimal(previous >> shift)
@@ -3,9 +3,75 @@ Module définissant le contexte d'exécution pour les calculs de Fibonacci.
 """
 
 import asyncio
+import contextlib
+from contextvars import ContextVar
 from dataclasses import dataclass
 from concurrent.futures import ProcessPoolExecutor
-from typing import Optional
+from typing import Callable, Iterator, Optional
+
+from .snapshot import ProgressSnapshot
+from .watchdog import Watchdog
+
+# Taille (en bits) par défaut à partir de laquelle une multiplication native
+# est encadrée de points d'annulation (voir `multiplication.multiply`).
+CANCELLATION_CHECK_BITS = 1 << 20
+
+# Rapport entre les coûts de deux étapes successives d'un calcul par bit de
+# `n` : les opérandes doublent de taille, et un produit Karatsuba coûte alors
+# trois fois plus (voir `CalculationProgress.work_fraction`).
+_STEP_COST_GROWTH = 3
+
+
+@dataclass(frozen=True)
+class StepTrace:
+    """Mesure d'une étape du "Fast Doubling", transmise au traceur du contexte.
+
+    Attributes:
+        step (int): Le rang de l'étape, à partir de 1 (le nombre de bits de
+            l'indice déjà traités, celui-ci compris).
+        bit (int): Le bit de l'indice traité par l'étape (0 ou 1).
+        operand_bits (int): La longueur en bits du plus grand opérande des
+            multiplications de l'étape.
+        path (str): La voie empruntée par la plus grande multiplication (voir
+            `multiplication.multiplication_path`).
+        duration (float): La durée de l'étape, en secondes.
+    """
+
+    step: int
+    bit: int
+    operand_bits: int
+    path: str
+    duration: float
+
+
+# Fonction appelée après chaque étape du "Fast Doubling" (voir `StepTrace`).
+Tracer = Callable[[StepTrace], None]
+
+# Fonction appelée après chaque étape d'un calcul avec son avancement (voir
+# `CalculationProgress`).
+ProgressReporter = Callable[["CalculationProgress"], None]
+
+
+@dataclass
+class ExecutorLoad:
+    """Occupation de l'exécuteur, partagée par les calculs qui l'utilisent.
+
+    Les calculs concurrents (mode 'all', serveur) délèguent leurs
+    multiplications au même pool de processus ; ce compteur, commun à leurs
+    contextes, leur permet de savoir si un processus de travail est libre.
+
+    Attributes:
+        workers (int): Le nombre de processus de l'exécuteur.
+        in_flight (int): Le nombre de multiplications déléguées en cours.
+    """
+
+    workers: int
+    in_flight: int = 0
+
+    @property
+    def saturated(self) -> bool:
+        """Indique si tous les processus de travail sont occupés."""
+        return self.in_flight >= self.workers
 
 
 @dataclass
@@ -27,8 +93,122 @@ class CalculationContext:
         progress_queue (Optional[asyncio.Queue]): Une file asynchrone pour
             communiquer l'avancement du calcul à l'interface utilisateur,
             notamment pour la barre de progression.
+        mul_algo (str): La stratégie de multiplication : `"auto"` (selon le
+            seuil), `"adaptive"` (selon le seuil, si un processus de travail
+            est libre), `"native"` (toujours dans le processus courant) ou
+            `"parallel"` (toujours déléguée à l'exécuteur).
+        mul_backend (str): Le moteur des produits de grande taille : `"auto"`
+            (GMP si `gmpy2` est installé), `"native"` (l'arithmétique de
+            CPython) ou `"gmp"` (voir `multiplication.MUL_BACKENDS`).
+        cancellation_check_bits (int): La taille, en bits, à partir de
+            laquelle une multiplication native rend la main à la boucle
+            d'événements avant et après son calcul, afin d'honorer un timeout
+            ou une annulation.
+        cancellation_chunks (int): Le nombre de tranches en lesquelles une
+            telle multiplication est découpée, avec un point d'annulation
+            entre chaque tranche. La valeur par défaut, 1, ne découpe pas.
+        warn_at (Optional[float]): La fraction du timeout à laquelle un
+            calcul dont l'avancement laisse prévoir un dépassement est
+            signalé sur la sortie d'erreur. Si `None`, rien n'est signalé.
+        tracer (Optional[Tracer]): La fonction qui reçoit la mesure de chaque
+            étape du "Fast Doubling". Si `None`, aucune mesure n'est prise.
+        executor_load (Optional[ExecutorLoad]): L'occupation de l'exécuteur,
+            partagée par tous les contextes qui l'utilisent et consultée par
+            la stratégie `"adaptive"`. Si `None`, elle n'est pas suivie.
+        watchdog (Optional[Watchdog]): Le chien de garde qui reçoit les
+            signes de vie du calcul et rapporte un blocage apparent. Si
+            `None`, le calcul n'est pas surveillé.
+        use_lookup_table (bool): Si `False`, les petits indices sont calculés
+            par l'algorithme au lieu d'être lus dans une table précalculée
+            (voir `LUCAS_LOOKUP_TABLE`), pour mesurer ou vérifier le noyau.
+        memory_fraction (Optional[float]): La fraction de la mémoire
+            disponible que les produits d'une étape peuvent allouer ; au-delà,
+            le calcul est interrompu par une `MemoryRiskError`. Si `None`,
+            les allocations ne sont pas contrôlées.
+        snapshot (Optional[ProgressSnapshot]): Le registre auquel le calcul
+            s'inscrit, pour que son avancement soit rapporté à la réception
+            de SIGUSR1. Si `None`, il n'est pas inscrit.
+        reporter (Optional[ProgressReporter]): La fonction qui reçoit
+            l'avancement du calcul après chaque étape, sans file ni tâche à
+            mettre en place : c'est l'interface de progression destinée au
+            code qui embarque PyFibonacci. Elle est appelée dans la boucle
+            d'événements, entre deux étapes, et doit donc rester brève. Si
+            `None`, l'avancement n'est pas rapporté.
     """
 
     threshold: int
     executor: Optional[ProcessPoolExecutor] = None
     progress_queue: Optional[asyncio.Queue] = None
+    mul_algo: str = "auto"
+    mul_backend: str = "auto"
+    cancellation_check_bits: int = CANCELLATION_CHECK_BITS
+    cancellation_chunks: int = 1
+    warn_at: Optional[float] = None
+    tracer: Optional[Tracer] = None
+    executor_load: Optional[ExecutorLoad] = None
+    watchdog: Optional[Watchdog] = None
+    use_lookup_table: bool = True
+    memory_fraction: Optional[float] = None
+    snapshot: Optional[ProgressSnapshot] = None
+    reporter: Optional[ProgressReporter] = None
+
+
+@dataclass
+class CalculationProgress:
+    """Avancement d'un calcul, mis à jour par l'algorithme au fil des étapes.
+
+    Attributes:
+        completed (int): Le nombre d'étapes terminées.
+        total (int): Le nombre total d'étapes, ou 0 si l'algorithme ne
+            rapporte pas son avancement.
+    """
+
+    completed: int = 0
+    total: int = 0
+
+    @property
+    def fraction(self) -> float:
+        """La fraction (entre 0 et 1) des étapes terminées."""
+        return self.completed / self.total if self.total else 0.0
+
+    @property
+    def work_fraction(self) -> float:
+        """La fraction (entre 0 et 1) du travail effectué, estimée.
+
+        Les étapes ne se valent pas : chacune coûte environ
+        `_STEP_COST_GROWTH` fois la précédente, si bien que la dernière
+        représente à elle seule les deux tiers du calcul. Contrairement à
+        `fraction`, cette estimation se prête à l'extrapolation de la durée.
+        """
+        if not self.total:
+            return 0.0
+        return (_STEP_COST_GROWTH**self.completed - 1) / (_STEP_COST_GROWTH**self.total - 1)
+
+
+# Avancement du calcul en cours. Une variable de contexte, plutôt qu'un champ
+# de `CalculationContext`, isole les calculs concurrents (mode 'all') qui
+# partagent le même contexte : chaque tâche `asyncio` en possède une copie.
+_current_progress: ContextVar[Optional[CalculationProgress]] = ContextVar(
+    "current_progress", default=None
+)
+
+
+@contextlib.contextmanager
+def track_progress() -> Iterator[CalculationProgress]:
+    """Suit l'avancement des algorithmes exécutés dans le bloc `with`.
+
+    Yields:
+        CalculationProgress: L'avancement, qui reste consultable après la
+        sortie du bloc (par exemple après un timeout).
+    """
+    progress = CalculationProgress()
+    token = _current_progress.set(progress)
+    try:
+        yield progress
+    finally:
+        _current_progress.reset(token)
+
+
+def current_progress() -> Optional[CalculationProgress]:
+    """Retourne l'avancement suivi par `track_progress`, s'il y en a un."""
+    return _current_progress.get()
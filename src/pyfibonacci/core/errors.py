@@ -0,0 +1,93 @@
+"""
+Module définissant les erreurs structurées des calculs.
+
+Un échec de calcul est décrit par une `CalculationError`, qui précise
+l'algorithme, l'indice et la catégorie de l'échec (`ErrorCategory`). Les
+appelants peuvent ainsi distinguer un timeout d'une annulation ou d'une
+erreur interne sans analyser de message. L'exception d'origine reste
+accessible par chaînage (`__cause__`), ce qui permet par exemple de tester
+`isinstance(error.__cause__, TimeoutError)`.
+"""
+
+import asyncio
+import enum
+
+
+class ErrorCategory(enum.Enum):
+    """Catégorie d'un échec de calcul.
+
+    Les valeurs correspondent aux issues publiées par le module `metrics`.
+    """
+
+    TIMEOUT = "timeout"
+    CANCELED = "cancel"
+    MEMORY = "memory"
+    INTERNAL = "error"
+
+
+class CalculationError(Exception):
+    """Échec d'un calcul, accompagné de son contexte.
+
+    Attributes:
+        algorithm (str): Le nom de l'algorithme qui a échoué.
+        n (int): L'indice dont le calcul a échoué.
+        category (ErrorCategory): La catégorie de l'échec.
+    """
+
+    def __init__(self, algorithm: str, n: int, category: ErrorCategory, message: str) -> None:
+        super().__init__(message)
+        self.algorithm = algorithm
+        self.n = n
+        self.category = category
+
+
+class MemoryRiskError(MemoryError):
+    """Calcul interrompu avant une étape qui risquerait d'épuiser la mémoire.
+
+    Attributes:
+        required (int): La mémoire, en octets, que l'étape allouerait.
+        allowed (int): La mémoire, en octets, que l'étape pouvait allouer.
+    """
+
+    def __init__(self, required: int, allowed: int) -> None:
+        super().__init__(
+            f"l'étape suivante allouerait environ {required // 2**20} Mio, au-delà des "
+            f"{allowed // 2**20} Mio permis ; calcul interrompu pour éviter un arrêt "
+            "du processus par le système"
+        )
+        self.required = required
+        self.allowed = allowed
+
+
+def categorize(error: BaseException) -> ErrorCategory:
+    """Détermine la catégorie d'une exception levée pendant un calcul.
+
+    Args:
+        error (BaseException): L'exception levée par l'algorithme.
+
+    Returns:
+        ErrorCategory: `TIMEOUT` pour un dépassement de délai, `CANCELED` pour
+        une annulation, `MEMORY` pour un manque de mémoire (avéré ou évité
+        par `MemoryRiskError`) et `INTERNAL` pour toute autre erreur.
+    """
+    if isinstance(error, TimeoutError):
+        return ErrorCategory.TIMEOUT
+    if isinstance(error, asyncio.CancelledError):
+        return ErrorCategory.CANCELED
+    if isinstance(error, MemoryError):
+        return ErrorCategory.MEMORY
+    return ErrorCategory.INTERNAL
+
+
+def is_caller_cancelled() -> bool:
+    """Indique si la tâche courante fait l'objet d'une demande d'annulation.
+
+    Une `asyncio.CancelledError` peut provenir de l'annulation de l'appelant,
+    qui doit être propagée telle quelle, ou de celle d'une opération interne
+    (un exécuteur arrêté, par exemple), qui constitue un échec du calcul.
+
+    Returns:
+        bool: `True` si l'annulation vise la tâche courante.
+    """
+    task = asyncio.current_task()
+    return task is not None and task.cancelling() > 0
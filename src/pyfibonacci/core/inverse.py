@@ -0,0 +1,110 @@
+"""
+Module de la recherche inverse : retrouver l'indice d'un nombre de Fibonacci.
+
+Un entier positif M est un nombre de Fibonacci si et seulement si 5M² + 4 ou
+5M² - 4 est un carré parfait (théorème de Gessel). Ce test ne demande qu'une
+racine carrée entière ; l'indice est ensuite estimé par la formule de Binet,
+n ≈ log_φ(M·√5), puis confirmé par le calcul exact de F(n).
+
+Les indices négatifs suivent l'identité du "negafibonacci" : un entier
+négatif M est un nombre de Fibonacci s'il vaut F(-k) = -F(k), k pair.
+"""
+
+import math
+from typing import Tuple
+
+from .algorithms import apply_negafibonacci_sign, fib_fast_doubling_pair
+from .context import CalculationContext
+
+# Logarithmes naturels de φ et de √5, pour estimer l'indice d'un terme.
+_LN_PHI = math.log((1 + math.sqrt(5)) / 2)
+_LN_SQRT5 = math.log(math.sqrt(5))
+
+
+def is_perfect_square(m: int) -> bool:
+    """Indique si un entier est un carré parfait.
+
+    La racine carrée entière (`math.isqrt`, une itération de Newton sur les
+    entiers) est exacte quelle que soit la taille de `m`, contrairement à
+    une racine en virgule flottante.
+
+    Args:
+        m (int): L'entier à tester.
+
+    Returns:
+        bool: `True` si `m` est le carré d'un entier (0 compris).
+    """
+    if m < 0:
+        return False
+    root = math.isqrt(m)
+    return root * root == m
+
+
+def is_fibonacci(m: int) -> bool:
+    """Indique si un entier positif ou nul est un nombre de Fibonacci.
+
+    Args:
+        m (int): L'entier à tester.
+
+    Returns:
+        bool: `True` si 5m² + 4 ou 5m² - 4 est un carré parfait.
+    """
+    if m < 0:
+        return False
+    square = 5 * m * m
+    return is_perfect_square(square + 4) or is_perfect_square(square - 4)
+
+
+def estimate_index(m: int) -> int:
+    """Estime l'indice d'un nombre de Fibonacci strictement positif.
+
+    Args:
+        m (int): Un nombre de Fibonacci strictement positif.
+
+    Returns:
+        int: L'arrondi de log_φ(m·√5), exact à une unité près.
+    """
+    return max(round((math.log(m) + _LN_SQRT5) / _LN_PHI), 1)
+
+
+async def fibonacci_indices(context: CalculationContext, m: int) -> Tuple[int, ...]:
+    """Retrouve les indices n tels que F(n) = m.
+
+    Pour m ≥ 0, seuls les indices positifs ou nuls sont retenus : 1 en a
+    deux, F(1) = F(2) = 1, et 0 un seul. Pour m < 0, l'indice retourné est
+    l'indice négatif pair dont F est égal à m.
+
+    Args:
+        context (CalculationContext): Le contexte du calcul de confirmation.
+        m (int): L'entier recherché.
+
+    Returns:
+        Tuple[int, ...]: Les indices trouvés, par ordre croissant (vide si
+        `m` n'est pas un nombre de Fibonacci).
+
+    Raises:
+        ArithmeticError: Si l'estimation ne mène à aucun terme égal à `m`,
+            alors que le test de Gessel l'a reconnu (une erreur de calcul).
+    """
+    magnitude = abs(m)
+    if not is_fibonacci(magnitude):
+        return ()
+    if magnitude == 0:
+        return (0,)
+    if magnitude == 1 and m > 0:
+        return (1, 2)
+
+    # L'estimation est exacte à une unité près : le couple (F(n), F(n+1))
+    # donne F(n-1) par différence et couvre les trois candidats.
+    n = estimate_index(magnitude)
+    current, following = await fib_fast_doubling_pair(context, n)
+    candidates = {n - 1: following - current, n: current, n + 1: following}
+    index = next((k for k, value in candidates.items() if value == magnitude), None)
+    if index is None:
+        raise ArithmeticError(f"Aucun terme proche de F({n}) ne vaut la valeur recherchée.")
+    if m > 0:
+        return (index,)
+    # F(-k) = -F(k) pour k pair ; 1 = F(2) a aussi pour opposé F(-2).
+    if magnitude == 1:
+        index = 2
+    return (-index,) if apply_negafibonacci_sign(-index, magnitude) == m else ()
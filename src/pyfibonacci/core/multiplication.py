@@ -3,15 +3,216 @@ Module de répartition pour la multiplication de haute précision.
 
 Ce module fournit une fonction `multiply` qui agit comme un répartiteur
 (dispatcher), choisissant entre une multiplication standard et une
-multiplication parallélisée en fonction de la taille des nombres.
+multiplication parallélisée en fonction de la taille des nombres. Tous les
+algorithmes passent par ce point unique, qui partage ainsi la même logique
+de sélection. Les carrés passent par `square`, qui applique la même logique
+à un unique opérande.
+
+Si la dépendance optionnelle `gmpy2` est installée (`pip install
+pyfibonacci[gmp]`), les produits de grande taille sont confiés à GMP, plus
+rapide que l'arithmétique native de CPython pour les très grands nombres.
+Sans elle, seule l'arithmétique native est utilisée. Le moteur peut aussi
+être imposé par `CalculationContext.mul_backend` (option `--mul-backend`),
+pour mesurer l'apport de GMP sans désinstaller la dépendance. Un produit GMP qui
+échoue (une exception levée par l'extension) ou dont la taille est
+incohérente est recalculé nativement, avec un avertissement : une
+défaillance de la bibliothèque sur une taille pathologique coûte du temps,
+jamais la justesse du résultat.
+
+La stratégie `"adaptive"` tient compte, en plus du seuil, de l'occupation du
+pool de processus partagé par les calculs concurrents : lorsque tous les
+processus de travail sont occupés (un mode 'all' sur une machine dont les
+cœurs sont déjà saturés, par exemple), déléguer un produit ne ferait que
+l'ajouter à la file d'attente, sérialisation des opérandes en sus. Il est
+alors calculé dans le processus courant, dont le cœur serait sinon inactif.
 """
 
 import asyncio
 import math
+import sys
+from typing import Callable
+
 from .context import CalculationContext
 
+try:
+    import gmpy2
+except ImportError:  # Dépendance optionnelle.
+    gmpy2 = None
+
+# Stratégies de multiplication reconnues par `CalculationContext.mul_algo`.
+MUL_ALGORITHMS = ("auto", "adaptive", "native", "parallel")
+
+# Taille (en bits) à partir de laquelle un produit est confié à GMP, lorsque
+# `gmpy2` est disponible. En deçà, la conversion vers et depuis `mpz` coûte
+# plus qu'elle ne rapporte (voir les benchmarks `test_benchmark_gmp_*`).
+GMP_THRESHOLD_BITS = 1 << 14
+
+# Moteur utilisé pour les produits de grande taille, choisi au chargement.
+MUL_BACKEND = "gmp" if gmpy2 is not None else "native"
+
+# Moteurs reconnus par `CalculationContext.mul_backend` : `"auto"` désigne
+# `MUL_BACKEND`.
+MUL_BACKENDS = ("auto", "native", "gmp")
+
+
+def resolve_mul_backend(backend: str = "auto") -> str:
+    """Retourne le moteur effectif des produits de grande taille.
+
+    Args:
+        backend (str): Le moteur demandé, parmi `MUL_BACKENDS`.
+
+    Returns:
+        str: `"gmp"` ou `"native"`.
+
+    Raises:
+        ValueError: Si le moteur est inconnu, ou si `"gmp"` est demandé sans
+            que `gmpy2` soit installé.
+    """
+    if backend not in MUL_BACKENDS:
+        raise ValueError(f"Moteur de multiplication inconnu : '{backend}'.")
+    if backend == "auto":
+        return "gmp" if gmpy2 is not None else "native"
+    if backend == "gmp" and gmpy2 is None:
+        raise ValueError(
+            "Le moteur de multiplication 'gmp' requiert la dépendance optionnelle "
+            "'gmpy2' (pip install pyfibonacci[gmp])."
+        )
+    return backend
+
+
+def _uses_gmp(backend: str, bits: int) -> bool:
+    """Indique si un produit dont le plus grand opérande compte `bits` bits est confié à GMP."""
+    return bits >= GMP_THRESHOLD_BITS and resolve_mul_backend(backend) == "gmp"
+
+
+def _gmp_multiply(a: int, b: int) -> int:
+    """Calcule `a * b` avec GMP, par sa voie dédiée aux carrés si `a is b`."""
+    if a is b:
+        x = gmpy2.mpz(a)
+        return int(x * x)
+    return int(gmpy2.mpz(a) * gmpy2.mpz(b))
+
+
+def _checked_product(
+    a: int, b: int, multiply: Callable[[int, int], int] = _gmp_multiply
+) -> int:
+    """Calcule `a * b` par `multiply`, ou nativement si celui-ci défaille.
+
+    Le produit de deux entiers non nuls de `p` et `q` bits compte `p + q`
+    ou `p + q - 1` bits, et son signe se déduit de ceux des opérandes : ce
+    contrôle, gratuit au regard du produit, écarte un résultat tronqué ou
+    corrompu. En cas d'exception ou de résultat incohérent, un avertissement
+    est écrit sur la sortie d'erreur et le produit est recalculé nativement.
+
+    Args:
+        a (int): Le premier opérande.
+        b (int): Le second opérande.
+        multiply (Callable[[int, int], int]): La multiplication contrôlée ;
+            par défaut, celle de GMP.
+
+    Returns:
+        int: Le produit de `a` et `b`.
+    """
+    try:
+        product = multiply(a, b)
+    except Exception as e:
+        reason = f"{type(e).__name__}: {e}"
+    else:
+        if not a or not b:
+            plausible = product == 0
+        else:
+            expected = a.bit_length() + b.bit_length()
+            plausible = (
+                product.bit_length() in (expected - 1, expected)
+                and (product < 0) == ((a < 0) != (b < 0))
+            )
+        if plausible:
+            return product
+        reason = f"résultat incohérent de {product.bit_length()} bits"
+    print(
+        f"AVERTISSEMENT: la multiplication GMP a échoué ({reason}) ; "
+        "le produit est recalculé nativement.",
+        file=sys.stderr,
+    )
+    return a * b
+
+
+def _product(a: int, b: int, backend: str = "auto") -> int:
+    """Calcule `a * b` avec GMP si le produit est assez grand, nativement sinon."""
+    if _uses_gmp(backend, max(a.bit_length(), b.bit_length())):
+        return _checked_product(a, b)
+    return a * b
+
+
+def _square(a: int, backend: str = "auto") -> int:
+    """Calcule `a * a` avec GMP si l'opérande est assez grand, nativement sinon."""
+    if _uses_gmp(backend, a.bit_length()):
+        return _checked_product(a, a)
+    return a * a
 
-def _parallel_multiply(a: int, b: int) -> int:
+
+async def _chunked_product(a: int, b: int, chunks: int, backend: str = "auto") -> int:
+    """Calcule `a * b` par tranches de `a`, en rendant la main entre chacune.
+
+    `a` est découpé en `chunks` tranches de bits contiguës, multipliées
+    chacune par `b` puis recombinées par décalage. Chaque produit partiel
+    coûte moins que le produit complet, mais leur somme coûte davantage :
+    Karatsuba perd en efficacité sur des opérandes déséquilibrés, et un carré
+    perd sa voie dédiée. Deux tranches coûtent ainsi environ un tiers de plus
+    qu'un produit d'un bloc.
+
+    Args:
+        a (int): L'opérande découpé.
+        b (int): Le second opérande.
+        chunks (int): Le nombre de tranches.
+        backend (str): Le moteur des produits partiels (voir `MUL_BACKENDS`).
+
+    Returns:
+        int: Le produit de `a` et `b`.
+    """
+    negative = (a < 0) != (b < 0)
+    a, b = abs(a), abs(b)
+    width = max(-(-a.bit_length() // chunks), 1)
+    mask = (1 << width) - 1
+    product = 0
+    for index in range(chunks):
+        if index:
+            await asyncio.sleep(0)  # Honore un timeout échu pendant la tranche précédente.
+        piece = (a >> (index * width)) & mask
+        product += _product(piece, b, backend) << (index * width)
+    return -product if negative else product
+
+
+async def _native_product(context: CalculationContext, a: int, b: int) -> int:
+    """Calcule `a * b` dans le processus courant, en honorant les annulations.
+
+    Les produits dont le plus grand opérande atteint
+    `context.cancellation_check_bits` sont encadrés de points d'annulation,
+    et découpés en `context.cancellation_chunks` tranches si celui-ci
+    dépasse 1. Les plus petits sont calculés d'un bloc, sans rendre la main.
+
+    Args:
+        context (CalculationContext): Le contexte contenant la granularité des
+            points d'annulation et le moteur de multiplication.
+        a (int): Le premier opérande.
+        b (int): Le second opérande.
+
+    Returns:
+        int: Le produit de `a` et `b`.
+    """
+    backend = context.mul_backend
+    if max(a.bit_length(), b.bit_length()) < context.cancellation_check_bits:
+        return _square(a, backend) if a is b else _product(a, b, backend)
+    await asyncio.sleep(0)  # Honore un timeout échu pendant le calcul précédent.
+    if context.cancellation_chunks > 1:
+        product = await _chunked_product(a, b, context.cancellation_chunks, backend)
+    else:
+        product = _square(a, backend) if a is b else _product(a, b, backend)
+    await asyncio.sleep(0)  # Honore un timeout échu pendant ce calcul.
+    return product
+
+
+def _parallel_multiply(a: int, b: int, backend: str = "auto") -> int:
     """Effectue une multiplication simple `a * b` dans un processus séparé.
 
     Cette fonction est conçue pour être exécutée par un `ProcessPoolExecutor`.
@@ -21,11 +222,125 @@ def _parallel_multiply(a: int, b: int) -> int:
     Args:
         a (int): Le premier opérande.
         b (int): Le second opérande.
+        backend (str): Le moteur de multiplication (voir `MUL_BACKENDS`) ;
+            il est transmis avec les opérandes, le processus de travail ne
+            partageant pas le contexte.
 
     Returns:
         int: Le produit de `a` et `b`.
     """
-    return a * b
+    return _product(a, b, backend)
+
+
+def _parallel_square(a: int, backend: str = "auto") -> int:
+    """Calcule le carré `a * a` dans un processus séparé.
+
+    Un seul opérande est sérialisé, ce qui divise par deux le volume transmis
+    au processus de travail. L'opérande y est de plus multiplié par lui-même,
+    ce qui permet à CPython d'emprunter sa voie dédiée aux carrés, que perd
+    `_parallel_multiply` lorsqu'il reçoit deux copies distinctes de la même
+    valeur.
+
+    Args:
+        a (int): L'entier à élever au carré.
+        backend (str): Le moteur de multiplication (voir `MUL_BACKENDS`).
+
+    Returns:
+        int: Le carré de `a`.
+    """
+    return _square(a, backend)
+
+
+def should_parallelize(context: CalculationContext, a: int, b: int) -> bool:
+    """Décide si la multiplication `a * b` doit être déléguée à l'exécuteur.
+
+    En mode `"auto"`, le point de bascule est atteint lorsque la longueur en
+    bits du plus grand opérande dépasse le seuil du contexte (converti de
+    chiffres décimaux en bits). Le mode `"adaptive"` y ajoute une condition :
+    un processus de travail doit être libre (voir
+    `CalculationContext.executor_load`). Les modes `"native"` et
+    `"parallel"` forcent respectivement l'une ou l'autre voie, ce qui permet
+    de mesurer chaque stratégie de part et d'autre du seuil. Sans exécuteur,
+    la multiplication reste toujours native.
+
+    Args:
+        context (CalculationContext): Le contexte contenant le seuil, la
+            stratégie et l'exécuteur.
+        a (int): Le premier opérande.
+        b (int): Le second opérande.
+
+    Returns:
+        bool: `True` si la multiplication doit être parallélisée.
+
+    Raises:
+        ValueError: Si la stratégie du contexte est inconnue.
+    """
+    if context.mul_algo not in MUL_ALGORITHMS:
+        raise ValueError(f"Stratégie de multiplication inconnue : '{context.mul_algo}'.")
+    if context.executor is None or context.mul_algo == "native":
+        return False
+    if context.mul_algo == "parallel":
+        return True
+
+    # Le seuil est en nombre de chiffres décimaux; on le convertit en bits.
+    # 1 chiffre décimal équivaut à environ log2(10) bits.
+    threshold_in_bits = context.threshold * math.log2(10)
+    if max(a.bit_length(), b.bit_length()) <= threshold_in_bits:
+        return False
+    load = context.executor_load
+    return context.mul_algo == "auto" or load is None or not load.saturated
+
+
+async def _delegate(context: CalculationContext, func: Callable[..., int], *operands: int) -> int:
+    """Exécute une multiplication dans l'exécuteur, en tenant son occupation à jour.
+
+    Le retour du produit est un signe de vie pour le chien de garde du
+    contexte : une étape du "doubling" peut durer longtemps, mais ses
+    produits délégués reviennent au fil de l'eau.
+
+    Args:
+        context (CalculationContext): Le contexte contenant l'exécuteur et,
+            le cas échéant, le compteur d'occupation partagé et le chien de
+            garde.
+        func (Callable[..., int]): La fonction de premier niveau exécutée
+            (`_parallel_multiply` ou `_parallel_square`).
+        *operands (int): Les opérandes transmis à `func`, suivis du moteur
+            de multiplication du contexte.
+
+    Returns:
+        int: Le produit calculé par le processus de travail.
+    """
+    loop = asyncio.get_running_loop()
+    load = context.executor_load
+    if load is not None:
+        load.in_flight += 1
+    try:
+        product = await loop.run_in_executor(context.executor, func, *operands, context.mul_backend)
+    finally:
+        if load is not None:
+            load.in_flight -= 1
+    if context.watchdog is not None:
+        context.watchdog.feed()
+    return product
+
+
+def multiplication_path(context: CalculationContext, a: int, b: int) -> str:
+    """Indique la voie qu'emprunte la multiplication `a * b`.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        a (int): Le premier opérande.
+        b (int): Le second opérande.
+
+    Returns:
+        str: `"parallel"` si elle est déléguée à l'exécuteur, `"gmp"` si elle
+        est confiée à GMP dans le processus courant, `"native"` sinon.
+    """
+    if should_parallelize(context, a, b):
+        return "parallel"
+    if _uses_gmp(context.mul_backend, max(a.bit_length(), b.bit_length())):
+        return "gmp"
+    return "native"
 
 
 async def multiply(context: CalculationContext, a: int, b: int) -> int:
@@ -37,6 +352,27 @@ async def multiply(context: CalculationContext, a: int, b: int) -> int:
     est exécutée dans un processus séparé pour ne pas bloquer la boucle
     d'événements principale.
 
+    La décision est prise par `should_parallelize`, qui tient compte de la
+    stratégie `mul_algo` du contexte.
+
+    Une multiplication d'entiers Python ne peut pas être interrompue : elle
+    s'exécute d'un bloc en conservant le GIL, et un timeout ou une annulation
+    n'est pris en compte qu'au point d'attente suivant. Pour que cette
+    latence se limite à une seule multiplication, les multiplications natives
+    de grande taille (voir `CalculationContext.cancellation_check_bits`)
+    rendent la main à la boucle d'événements juste avant et juste après le
+    calcul ; les plus petites s'exécutent sans point d'attente, pour ne pas
+    pénaliser les algorithmes qui en enchaînent beaucoup. Le "Fast Doubling"
+    n'effectuant que trois produits par bit de `n`, la latence d'annulation
+    est au plus la durée de la dernière de ces multiplications, de l'ordre
+    d'un cinquième de la durée totale du calcul.
+    `CalculationContext.cancellation_chunks` réduit cette latence en
+    découpant les grandes multiplications en tranches séparées par des points
+    d'annulation, au prix d'un surcoût de calcul (voir `_chunked_product`).
+    Côté exécuteur, l'annulation est immédiate pour l'appelant, mais le
+    processus de travail termine la multiplication en cours avant d'être
+    disponible.
+
     Args:
         context (CalculationContext): Le contexte contenant le seuil et
             l'exécuteur de processus.
@@ -46,16 +382,29 @@ async def multiply(context: CalculationContext, a: int, b: int) -> int:
     Returns:
         int: Le produit de `a` et `b`.
     """
-    if context.executor is None:
-        return a * b
+    if should_parallelize(context, a, b):
+        return await _delegate(context, _parallel_multiply, a, b)
+    # Pour les nombres sous le seuil, la multiplication native est plus rapide.
+    return await _native_product(context, a, b)
 
-    # Le seuil est en nombre de chiffres décimaux; on le convertit en bits.
-    # 1 chiffre décimal équivaut à environ log2(10) bits.
-    threshold_in_bits = context.threshold * math.log2(10)
 
-    if max(a.bit_length(), b.bit_length()) > threshold_in_bits:
-        loop = asyncio.get_running_loop()
-        return await loop.run_in_executor(context.executor, _parallel_multiply, a, b)
-    else:
-        # Pour les nombres sous le seuil, la multiplication native est plus rapide.
-        return a * b
+async def square(context: CalculationContext, a: int) -> int:
+    """Élève un entier au carré, en déléguant si sa taille dépasse un seuil.
+
+    Le choix entre les voies native et parallèle est celui de `multiply`.
+    CPython reconnaît un produit dont les deux opérandes sont le même objet
+    et exploite alors la symétrie du calcul, ce qui le rend sensiblement plus
+    rapide qu'une multiplication générale de même taille ; côté exécuteur,
+    `_parallel_square` ne transmet qu'un opérande et préserve cet avantage.
+
+    Args:
+        context (CalculationContext): Le contexte contenant le seuil et
+            l'exécuteur de processus.
+        a (int): L'entier à élever au carré.
+
+    Returns:
+        int: Le carré de `a`.
+    """
+    if should_parallelize(context, a, a):
+        return await _delegate(context, _parallel_square, a)
+    return await _native_product(context, a, a)
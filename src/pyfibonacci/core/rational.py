@@ -0,0 +1,152 @@
+"""
+Module des identités rationnelles de la suite de Fibonacci.
+
+Certaines identités portent naturellement sur des rationnels : sommes
+d'inverses, rapports de termes consécutifs. `fib_fraction` retourne F(n) sous
+la forme d'une `Fraction` calculée par les algorithmes entiers du paquet, ce
+qui permet de poursuivre en arithmétique rationnelle exacte sans réécrire la
+conversion. Les vérifications qui suivent confrontent un membre obtenu par
+sommation terme à terme à l'autre, obtenu par "Fast Doubling" :
+
+- la série de Millin : 1/F(1) + 1/F(2) + 1/F(4) + ... + 1/F(2^m)
+  = 3 - F(2^m - 1)/F(2^m) ;
+- la somme télescopique : 1/(F(1)F(3)) + ... + 1/(F(n)F(n+2))
+  = 1 - 1/(F(n+1)F(n+2)) ;
+- les réduites du nombre d'or : la fraction continue [1; 1, ..., 1] à n
+  termes vaut F(n+1)/F(n).
+"""
+
+from fractions import Fraction
+from typing import Awaitable, Callable, Dict, Iterator, Tuple
+
+from .algorithms import apply_negafibonacci_sign, fib_fast_doubling
+from .context import CalculationContext
+from .result import Algorithm, calculate_with_stats
+
+
+async def fib_fraction(
+    context: CalculationContext, n: int, algorithm: Algorithm = fib_fast_doubling
+) -> Fraction:
+    """Calcule F(n) sous la forme d'un rationnel exact.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (éventuellement négatif) de la suite.
+        algorithm (Algorithm): L'algorithme entier qui calcule F(|n|).
+
+    Returns:
+        Fraction: F(n), de dénominateur 1.
+
+    Raises:
+        CalculationError: Si l'algorithme échoue (voir `calculate_with_stats`).
+    """
+    result = await calculate_with_stats(context, abs(n), algorithm)
+    return Fraction(apply_negafibonacci_sign(n, result.value))
+
+
+def _terms(count: int) -> Iterator[Tuple[int, int]]:
+    """Énumère les couples (k, F(k)) pour k = 1..count."""
+    current, following = 1, 1
+    for k in range(1, count + 1):
+        yield k, current
+        current, following = following, current + following
+
+
+def reciprocal_sum(n: int) -> Fraction:
+    """Calcule la somme partielle 1/F(1) + 1/F(2) + ... + 1/F(n).
+
+    La série converge vers la constante de Fibonacci inverse (≈ 3,3599),
+    dont on ignore si elle admet une forme close : seules ses sommes
+    partielles sont exactes.
+
+    Args:
+        n (int): Le nombre de termes (positif ou nul).
+
+    Returns:
+        Fraction: La somme partielle, exacte.
+
+    Raises:
+        ValueError: Si `n` est négatif.
+    """
+    if n < 0:
+        raise ValueError("Le nombre de termes ne peut pas être négatif.")
+    return sum((Fraction(1, term) for _, term in _terms(n)), Fraction(0))
+
+
+async def check_millin_series(context: CalculationContext, m: int) -> bool:
+    """Vérifie la série de Millin jusqu'au terme 1/F(2^m).
+
+    Args:
+        context (CalculationContext): Le contexte du calcul de F(2^m - 1) et
+            de F(2^m).
+        m (int): L'exposant du dernier terme, au moins 1.
+
+    Returns:
+        bool: `True` si la somme des 1/F(2^k), k = 0..m, vaut
+        3 - F(2^m - 1)/F(2^m).
+
+    Raises:
+        ValueError: Si `m` est inférieur à 1.
+    """
+    if m < 1:
+        raise ValueError("L'exposant de la série de Millin doit valoir au moins 1.")
+    powers = {2**k for k in range(m + 1)}
+    series = sum((Fraction(1, term) for k, term in _terms(2**m) if k in powers), Fraction(0))
+    closed_form = 3 - await fib_fraction(context, 2**m - 1) / await fib_fraction(context, 2**m)
+    return series == closed_form
+
+
+async def check_reciprocal_products(context: CalculationContext, n: int) -> bool:
+    """Vérifie la somme télescopique des 1/(F(k)F(k+2)), k = 1..n.
+
+    Chaque terme vaut 1/(F(k)F(k+1)) - 1/(F(k+1)F(k+2)) : la somme se réduit
+    à ses extrémités.
+
+    Args:
+        context (CalculationContext): Le contexte du calcul de F(n+1) et de
+            F(n+2).
+        n (int): Le nombre de termes (positif ou nul).
+
+    Returns:
+        bool: `True` si la somme vaut 1 - 1/(F(n+1)F(n+2)).
+
+    Raises:
+        ValueError: Si `n` est négatif.
+    """
+    if n < 0:
+        raise ValueError("Le nombre de termes ne peut pas être négatif.")
+    terms = [term for _, term in _terms(n + 2)]
+    series = sum((Fraction(1, terms[k] * terms[k + 2]) for k in range(n)), Fraction(0))
+    closed_form = 1 - 1 / (await fib_fraction(context, n + 1) * await fib_fraction(context, n + 2))
+    return series == closed_form
+
+
+async def check_golden_convergent(context: CalculationContext, n: int) -> bool:
+    """Vérifie que la n-ième réduite de φ = [1; 1, 1, ...] vaut F(n+1)/F(n).
+
+    Args:
+        context (CalculationContext): Le contexte du calcul de F(n) et de
+            F(n+1).
+        n (int): Le nombre de termes de la fraction continue, au moins 1.
+
+    Returns:
+        bool: `True` si la fraction continue vaut F(n+1)/F(n).
+
+    Raises:
+        ValueError: Si `n` est inférieur à 1.
+    """
+    if n < 1:
+        raise ValueError("La fraction continue doit compter au moins un terme.")
+    convergent = Fraction(1)
+    for _ in range(n - 1):
+        convergent = 1 + 1 / convergent
+    return convergent == await fib_fraction(context, n + 1) / await fib_fraction(context, n)
+
+
+# Vérifications des identités rationnelles, par nom. Chacune prend le
+# contexte de calcul et son paramètre entier.
+RATIONAL_IDENTITIES: Dict[str, Callable[[CalculationContext, int], Awaitable[bool]]] = {
+    "millin": check_millin_series,
+    "reciprocal_products": check_reciprocal_products,
+    "golden_convergent": check_golden_convergent,
+}
@@ -0,0 +1,37 @@
+"""
+Module des ressources de la machine consultées pendant un calcul.
+
+La mémoire disponible sert à deux garde-fous : l'un refuse, avant toute
+allocation, un calcul dont la mémoire de pointe prévue dépasse celle de la
+machine (voir `estimate.check_memory`) ; l'autre interrompt un calcul en
+cours avant une étape dont les produits ne tiendraient plus dans la mémoire
+restante (voir `CalculationContext.memory_fraction`). Sans eux, le système
+tuerait le processus sans le moindre message.
+"""
+
+import os
+from typing import Optional
+
+
+def available_memory() -> Optional[int]:
+    """Retourne la mémoire disponible sur la machine, en octets.
+
+    La valeur `MemAvailable` de `/proc/meminfo` est préférée, car elle tient
+    compte des caches récupérables ; à défaut, la mémoire physique totale est
+    retenue.
+
+    Returns:
+        Optional[int]: La mémoire disponible, ou `None` si elle est inconnue
+        (sur une plate-forme qui n'expose ni l'une ni l'autre).
+    """
+    try:
+        with open("/proc/meminfo", encoding="ascii") as meminfo:
+            for line in meminfo:
+                if line.startswith("MemAvailable:"):
+                    return int(line.split()[1]) * 1024
+    except (OSError, ValueError, IndexError):
+        pass
+    try:
+        return os.sysconf("SC_PHYS_PAGES") * os.sysconf("SC_PAGE_SIZE")
+    except (AttributeError, OSError, ValueError):
+        return None
@@ -0,0 +1,93 @@
+"""
+Module définissant le résultat d'un calcul, utilisable comme bibliothèque.
+
+Ce module permet d'utiliser les algorithmes du paquet `core` sans dépendre
+de la couche CLI : `calculate_with_stats` exécute un algorithme et retourne
+un `FibonacciResult` regroupant la valeur, l'indice, l'algorithme et la
+durée, ainsi que des métadonnées dérivées (longueur en bits, chiffres). Un
+échec est signalé par une `CalculationError` (voir le module `errors`).
+"""
+
+import asyncio
+import time
+from dataclasses import dataclass
+from typing import Awaitable, Callable, Optional, Union
+
+from .context import CalculationContext
+from .errors import CalculationError, ErrorCategory, categorize, is_caller_cancelled
+
+# Un algorithme est soit une coroutine `(context, n)`, soit une fonction
+# synchrone `(n)`, comme `fib_iterative`.
+Algorithm = Callable[..., Union[Awaitable[int], int]]
+
+
+@dataclass(frozen=True)
+class FibonacciResult:
+    """Résultat d'un calcul de Fibonacci, accompagné de ses métadonnées.
+
+    Attributes:
+        value (int): La valeur calculée.
+        n (int): L'indice calculé.
+        algorithm (str): Le nom de l'algorithme utilisé.
+        duration (float): La durée du calcul, en secondes.
+    """
+
+    value: int
+    n: int
+    algorithm: str
+    duration: float
+
+    @property
+    def bit_length(self) -> int:
+        """La longueur en bits de la valeur absolue du résultat."""
+        return self.value.bit_length()
+
+    @property
+    def digits(self) -> int:
+        """Le nombre de chiffres décimaux du résultat, signe exclu."""
+        return len(str(abs(self.value)))
+
+
+async def calculate_with_stats(
+    context: CalculationContext,
+    n: int,
+    algorithm: Algorithm,
+    name: Optional[str] = None,
+) -> FibonacciResult:
+    """Exécute un algorithme et retourne son résultat chronométré.
+
+    Les algorithmes asynchrones sont attendus directement ; les algorithmes
+    synchrones sont exécutés dans un thread (`asyncio.to_thread`) afin de ne
+    pas bloquer la boucle d'événements de l'appelant.
+
+    Args:
+        context (CalculationContext): Le contexte de calcul.
+        n (int): L'indice (entier non-négatif) de la suite.
+        algorithm (Algorithm): La fonction de calcul, par exemple
+            `fib_fast_doubling`.
+        name (Optional[str]): Le nom à associer au résultat. Par défaut, le
+            nom de la fonction.
+
+    Returns:
+        FibonacciResult: La valeur et les métadonnées du calcul.
+
+    Raises:
+        CalculationError: Si l'algorithme échoue ; l'exception d'origine est
+            chaînée (`__cause__`). L'annulation de l'appelant est propagée
+            telle quelle.
+    """
+    name = name or algorithm.__name__
+    start_time = time.perf_counter()
+    try:
+        if asyncio.iscoroutinefunction(algorithm):
+            value = await algorithm(context, n)
+        else:
+            value = await asyncio.to_thread(algorithm, n)
+    except asyncio.CancelledError as e:
+        if is_caller_cancelled():
+            raise
+        raise CalculationError(name, n, ErrorCategory.CANCELED, "calcul annulé") from e
+    except Exception as e:
+        raise CalculationError(name, n, categorize(e), str(e) or type(e).__name__) from e
+    duration = time.perf_counter() - start_time
+    return FibonacciResult(value, n, name, duration)
@@ -0,0 +1,107 @@
+"""
+Module de l'instantané de progression, déclenché par le signal SIGUSR1.
+
+Un long calcul lancé sans barre de progression (dans un script, sur un
+serveur) ne dit rien de son avancement. Plutôt que de l'interrompre pour le
+relancer avec '-d', on peut l'interroger : `kill -USR1 <pid>` écrit sur la
+sortie d'erreur l'avancement de chaque calcul en cours, leur avancement
+moyen et la durée écoulée, puis le calcul continue.
+
+L'instantané lit le dernier avancement connu de chaque calcul (voir
+`CalculationProgress`), sans attendre la boucle d'événements. Le
+gestionnaire de signal s'exécute dans le thread principal, entre deux
+instructions Python : pendant une multiplication native, la réponse attend
+la fin du produit en cours. Sur les plates-formes sans SIGUSR1 (Windows),
+`install` ne fait rien.
+"""
+
+import contextlib
+import signal
+import sys
+import time
+from typing import TYPE_CHECKING, Any, Dict, Iterator, Optional, TextIO, Tuple
+
+if TYPE_CHECKING:  # `context` importe ce module.
+    from .context import CalculationProgress
+
+
+class ProgressSnapshot:
+    """Registre des calculs en cours, dont l'avancement est rapporté à la demande.
+
+    Les calculs concurrents (mode 'all') s'inscrivent chacun par `track`
+    pour la durée de leur exécution ; l'avancement moyen porte sur ceux qui
+    rapportent leur progression.
+
+    Attributes:
+        stream (TextIO): Le flux de l'instantané.
+    """
+
+    def __init__(self, stream: Optional[TextIO] = None) -> None:
+        self.stream = stream or sys.stderr
+        self._start = time.monotonic()
+        self._calculations: Dict[int, Tuple[str, "CalculationProgress"]] = {}
+        self._previous_handler: Any = None
+        self._installed = False
+
+    @contextlib.contextmanager
+    def track(self, name: str, progress: "CalculationProgress") -> Iterator[None]:
+        """Inscrit un calcul pendant l'exécution du bloc `with`.
+
+        Args:
+            name (str): Le nom de l'algorithme.
+            progress (CalculationProgress): Son avancement, mis à jour par
+                l'algorithme (voir `track_progress`).
+        """
+        key = id(progress)
+        self._calculations[key] = (name, progress)
+        try:
+            yield
+        finally:
+            self._calculations.pop(key, None)
+
+    def describe(self) -> str:
+        """Décrit l'avancement des calculs en cours et la durée écoulée.
+
+        Returns:
+            str: L'instantané, sur une ligne.
+        """
+        elapsed = f"écoulé : {time.monotonic() - self._start:.1f} s"
+        calculations = list(self._calculations.values())
+        if not calculations:
+            return f"Progression : aucun calcul en cours, {elapsed}"
+        reported = [progress.fraction for _, progress in calculations if progress.total]
+        overall = f"{100 * sum(reported) / len(reported):.1f} %" if reported else "inconnue"
+        if len(calculations) == 1:
+            return f"Progression ({calculations[0][0]}) : {overall}, {elapsed}"
+        details = ", ".join(
+            f"{name} : {100 * progress.fraction:.1f} %" if progress.total else f"{name} : inconnue"
+            for name, progress in calculations
+        )
+        return f"Progression : {overall} ({details}), {elapsed}"
+
+    def report(self, *_: Any) -> None:
+        """Écrit l'instantané sur le flux ; sert de gestionnaire de signal."""
+        print(self.describe(), file=self.stream, flush=True)
+
+    def install(self) -> bool:
+        """Installe `report` comme gestionnaire de SIGUSR1.
+
+        Returns:
+            bool: `True` si le gestionnaire est installé, `False` sur une
+            plate-forme sans SIGUSR1 ou hors du thread principal.
+        """
+        if not hasattr(signal, "SIGUSR1"):
+            return False
+        try:
+            self._previous_handler = signal.signal(signal.SIGUSR1, self.report)
+        except ValueError:  # Hors du thread principal.
+            return False
+        self._installed = True
+        return True
+
+    def uninstall(self) -> None:
+        """Rétablit le gestionnaire de SIGUSR1 remplacé par `install`."""
+        if self._installed:
+            previous = self._previous_handler
+            signal.signal(signal.SIGUSR1, signal.SIG_DFL if previous is None else previous)
+            self._installed = False
@@ -0,0 +1,174 @@
+"""
+Module du chien de garde, qui diagnostique un calcul apparemment bloqué.
+
+Un interblocage dans la multiplication parallèle (un résultat délégué au pool
+de processus qui ne revient jamais, par exemple) fige le calcul sans aucun
+message : la barre de progression cesse simplement d'avancer. Le chien de
+garde surveille les signes de vie du calcul (les étapes terminées et les
+multiplications déléguées revenues) depuis un thread distinct ; en l'absence
+de tout signe de vie pendant l'intervalle choisi, il écrit sur la sortie
+d'erreur les piles d'exécution de tous les threads (`faulthandler`), puis
+celles des tâches `asyncio`, qui montrent quel `await` n'aboutit pas. Il peut
+ensuite mettre fin au processus.
+
+Le thread de surveillance ne dépend pas de la boucle d'événements : les piles
+des threads sont écrites même si celle-ci est bloquée dans un calcul natif.
+Seules les piles des tâches exigent que la boucle reprenne la main ; à défaut,
+le rapport le signale.
+"""
+
+import asyncio
+import contextlib
+import faulthandler
+import os
+import sys
+import threading
+import time
+from typing import Any, Iterator, Optional, TextIO
+
+# Délai, en secondes, accordé à la boucle d'événements pour écrire les piles
+# des tâches `asyncio`.
+_TASK_DUMP_TIMEOUT = 1.0
+
+
+def _print_await_chain(task: asyncio.Task, stream: TextIO) -> None:
+    """Écrit la chaîne des `await` d'une tâche, jusqu'à l'objet attendu.
+
+    Contrairement à `Task.print_stack`, qui s'arrête à la coroutine de la
+    tâche, la chaîne est suivie jusqu'au futur en attente (le résultat d'une
+    multiplication déléguée, par exemple).
+    """
+    print(f"Tâche {task.get_name()} :", file=stream)
+    awaitable: Any = task.get_coro()
+    while awaitable is not None:
+        frame = getattr(awaitable, "cr_frame", None) or getattr(awaitable, "gi_frame", None)
+        if frame is None:
+            print(f"  en attente de {awaitable!r}", file=stream)
+            break
+        code = frame.f_code
+        print(f'  File "{code.co_filename}", line {frame.f_lineno}, in {code.co_name}', file=stream)
+        awaitable = getattr(awaitable, "cr_await", None) or getattr(awaitable, "gi_yieldfrom", None)
+
+
+class Watchdog:
+    """Surveille les signes de vie des calculs en cours.
+
+    Le chien de garde n'est armé que pendant les calculs, délimités par
+    `watch` : la conversion décimale du résultat, qui ne donne aucun signe
+    de vie, n'est pas surveillée. Les calculs concurrents (mode 'all')
+    partagent le même chien de garde, qui se déclenche lorsqu'aucun d'eux
+    n'a progressé pendant `interval` secondes. Sans abandon, il se réarme
+    après chaque rapport.
+
+    Attributes:
+        interval (float): La durée maximale, en secondes, sans signe de vie.
+        exit_code (Optional[int]): Le code de sortie avec lequel le processus
+            est arrêté après le rapport, ou `None` pour le laisser continuer.
+        stream (TextIO): Le flux du rapport, qui doit posséder un descripteur
+            de fichier (`faulthandler` y écrit directement).
+    """
+
+    def __init__(
+        self, interval: float, exit_code: Optional[int] = None, stream: Optional[TextIO] = None
+    ) -> None:
+        self.interval = interval
+        self.exit_code = exit_code
+        self.stream = stream or sys.stderr
+        self._active = 0
+        self._last_sign = time.monotonic()
+        self._loop: Optional[asyncio.AbstractEventLoop] = None
+        self._lock = threading.Lock()
+        self._stopped = threading.Event()
+        self._thread: Optional[threading.Thread] = None
+
+    def feed(self) -> None:
+        """Enregistre un signe de vie du calcul."""
+        self._last_sign = time.monotonic()
+
+    @contextlib.contextmanager
+    def watch(self) -> Iterator[None]:
+        """Arme le chien de garde pendant l'exécution du bloc `with`.
+
+        Le thread de surveillance est démarré au premier appel, depuis la
+        boucle d'événements dont les tâches seront rapportées.
+        """
+        with self._lock:
+            if self._thread is None:
+                self._loop = asyncio.get_running_loop()
+                self._thread = threading.Thread(
+                    target=self._run, name="pyfibonacci-watchdog", daemon=True
+                )
+                self._thread.start()
+            if self._active == 0:
+                self.feed()
+            self._active += 1
+        try:
+            yield
+        finally:
+            with self._lock:
+                self._active -= 1
+
+    def close(self) -> None:
+        """Arrête le thread de surveillance."""
+        self._stopped.set()
+        if self._thread is not None:
+            self._thread.join()
+
+    def _run(self) -> None:
+        """Boucle du thread de surveillance."""
+        while not self._stopped.wait(self._next_check()):
+            with self._lock:
+                expired = self._active > 0 and self._remaining() == 0
+            if not expired:
+                continue
+            self.report()
+            if self.exit_code is not None:
+                # Le calcul est bloqué : une sortie ordinaire attendrait la
+                # fin des tâches qui ne se terminent pas.
+                os._exit(self.exit_code)
+            self.feed()
+
+    def _next_check(self) -> float:
+        """Retourne le délai, en secondes, avant le prochain examen."""
+        with self._lock:
+            return self._remaining() if self._active else self.interval
+
+    def _remaining(self) -> float:
+        """Retourne le délai, en secondes, avant l'expiration de l'intervalle."""
+        return max(self._last_sign + self.interval - time.monotonic(), 0.0)
+
+    def report(self) -> None:
+        """Écrit les piles d'exécution des threads et des tâches `asyncio`."""
+        idle = time.monotonic() - self._last_sign
+        print(
+            f"WATCHDOG: aucun signe de vie du calcul depuis {idle:.1f} s ; "
+            "piles d'exécution des threads :",
+            file=self.stream,
+            flush=True,
+        )
+        faulthandler.dump_traceback(file=self.stream, all_threads=True)
+        if self._loop is None or self._loop.is_closed():
+            return
+
+        dumped = threading.Event()
+
+        def dump_tasks() -> None:
+            try:
+                for task in asyncio.all_tasks(self._loop):
+                    _print_await_chain(task, self.stream)
+                self.stream.flush()
+            finally:
+                dumped.set()
+
+        print("WATCHDOG: piles des tâches asyncio :", file=self.stream, flush=True)
+        try:
+            self._loop.call_soon_threadsafe(dump_tasks)
+        except RuntimeError:
+            return
+        if not dumped.wait(_TASK_DUMP_TIMEOUT):
+            print(
+                "WATCHDOG: la boucle d'événements ne répond pas (calcul bloqué hors "
+                "d'un 'await').",
+                file=self.stream,
+                flush=True,
+            )
@@ -0,0 +1,156 @@
+"""
+Module de la recherche des points de croisement entre algorithmes.
+
+La calibration (voir `calibrate`) détermine un seuil unique, celui de la
+multiplication parallèle. `run_crossover` répond à une question voisine, à
+plus haut niveau : sur cette machine, quel algorithme calcule F(n) le plus
+vite, selon n ? Chaque algorithme de Fibonacci est chronométré sur une
+gamme d'indices, et le rapport indique les plages d'indices sur lesquelles
+le plus rapide ne change pas, par exemple :
+
+    n < 200 : iterative
+    n ≥ 200 : fast
+
+Un algorithme qui échoue (ou dépasse le délai accordé à chaque calcul) à un
+indice n'est pas mesuré aux indices suivants : il serait a fortiori plus
+lent encore.
+"""
+
+from dataclasses import dataclass
+from typing import Awaitable, Callable, Dict, List, Optional, Sequence, Tuple
+
+from .cli.output import CalculationResult, _format_duration, group_digits
+
+# Indices mesurés, par ordre croissant : trois par décade (1, 2, 5).
+CROSSOVER_SIZES = tuple(m * 10**e for e in range(2, 8) for m in (1, 2, 5))
+
+# Délai accordé par défaut à chaque calcul, en secondes.
+CROSSOVER_TIMEOUT = 10.0
+
+# Nombre d'exécutions de chaque calcul, dont la durée médiane est retenue.
+CROSSOVER_REPEAT = 3
+
+
+@dataclass(frozen=True)
+class CrossoverMeasure:
+    """Durées des algorithmes mesurés pour un indice.
+
+    Attributes:
+        n (int): L'indice calculé.
+        durations (Dict[str, Optional[float]]): La durée de chaque algorithme
+            mesuré, en secondes, ou `None` s'il a échoué.
+    """
+
+    n: int
+    durations: Dict[str, Optional[float]]
+
+    @property
+    def fastest(self) -> Optional[str]:
+        """L'algorithme le plus rapide, ou `None` si tous ont échoué."""
+        measured = {name: d for name, d in self.durations.items() if d is not None}
+        return min(measured, key=measured.__getitem__) if measured else None
+
+
+async def run_crossover(
+    execute: Callable[[int, str], Awaitable[CalculationResult]],
+    algorithms: Sequence[str],
+    sizes: Sequence[int] = CROSSOVER_SIZES,
+) -> List[CrossoverMeasure]:
+    """Chronomètre chaque algorithme sur chaque indice.
+
+    Args:
+        execute (Callable[[int, str], Awaitable[CalculationResult]]): La
+            fonction qui exécute et chronomètre un algorithme sur un indice.
+        algorithms (Sequence[str]): Les algorithmes comparés.
+        sizes (Sequence[int]): Les indices, par ordre croissant.
+
+    Returns:
+        List[CrossoverMeasure]: Une mesure par indice traité. La gamme
+        s'arrête dès que tous les algorithmes ont échoué.
+    """
+    remaining = list(algorithms)
+    measures = []
+    for n in sizes:
+        if not remaining:
+            break
+        durations: Dict[str, Optional[float]] = {}
+        for name in list(remaining):
+            result = await execute(n, name)
+            if result.success:
+                durations[name] = result.duration
+            else:
+                durations[name] = None
+                remaining.remove(name)
+        measures.append(CrossoverMeasure(n, durations))
+    return measures
+
+
+def crossover_ranges(measures: Sequence[CrossoverMeasure]) -> List[Tuple[int, str]]:
+    """Regroupe les indices consécutifs dont l'algorithme le plus rapide est le même.
+
+    Args:
+        measures (Sequence[CrossoverMeasure]): Les mesures, par indice croissant.
+
+    Returns:
+        List[Tuple[int, str]]: Pour chaque plage, son premier indice mesuré
+        et l'algorithme le plus rapide ; chaque changement d'algorithme est
+        un point de croisement. Les indices où tous ont échoué sont ignorés.
+    """
+    ranges: List[Tuple[int, str]] = []
+    for measure in measures:
+        fastest = measure.fastest
+        if fastest is not None and (not ranges or ranges[-1][1] != fastest):
+            ranges.append((measure.n, fastest))
+    return ranges
+
+
+def describe_crossover(
+    algorithms: Sequence[str], measures: Sequence[CrossoverMeasure], sep: str = " "
+) -> str:
+    """Met en forme les mesures et les points de croisement.
+
+    Args:
+        algorithms (Sequence[str]): Les algorithmes comparés, dans l'ordre
+            des colonnes.
+        measures (Sequence[CrossoverMeasure]): Les mesures, par indice croissant.
+        sep (str): Le séparateur des milliers.
+
+    Returns:
+        str: Le rapport, sur plusieurs lignes.
+    """
+    columns = " | ".join(f"{name:>11}" for name in algorithms)
+    rules = "|".join("-" * 13 for _ in algorithms)
+    lines = [
+        f"Points de croisement des algorithmes (durée médiane de {CROSSOVER_REPEAT} exécutions)",
+        f"| n              | {columns} | Le plus rapide |",
+        f"|----------------|{rules}|----------------|",
+    ]
+    for measure in measures:
+        cells = []
+        for name in algorithms:
+            if name not in measure.durations:
+                cells.append(f"{'-':>11}")
+            elif measure.durations[name] is None:
+                cells.append(f"{'ÉCHEC':>11}")
+            else:
+                cells.append(f"{_format_duration(measure.durations[name]):>11}")
+        fastest = measure.fastest or "-"
+        lines.append(f"| {group_digits(measure.n, sep):>14} | " + " | ".join(cells) + f" | {fastest:<14} |")
+
+    ranges = crossover_ranges(measures)
+    if not ranges:
+        lines.append("Aucun algorithme n'a abouti.")
+        return "\n".join(lines)
+    lines.append("Algorithme le plus rapide :")
+    for i, (start, name) in enumerate(ranges):
+        following = ranges[i + 1][0] if i + 1 < len(ranges) else None
+        if len(ranges) == 1:
+            span = "à tous les indices mesurés"
+        elif i == 0:
+            span = f"n < {group_digits(following, sep)}"
+        elif following is None:
+            span = f"n ≥ {group_digits(start, sep)}"
+        else:
+            span = f"{group_digits(start, sep)} ≤ n < {group_digits(following, sep)}"
+        lines.append(f"  {span} : {name}")
+    return "\n".join(lines)
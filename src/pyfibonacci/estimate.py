@@ -0,0 +1,158 @@
+"""
+Module d'estimation du coût d'un calcul, sans l'effectuer.
+
+Avant de lancer un calcul gigantesque, `estimate_calculation` prédit la taille
+du résultat (formule de Binet), la mémoire de pointe et la durée du calcul par
+"Fast Doubling". La durée est extrapolée à partir de deux carrés mesurés sur
+la machine : leur rapport donne l'exposant de la loi de puissance suivie par
+la multiplication (≈ 1,58 pour Karatsuba, proche de 1 avec GMP), ce qui rend
+l'estimation indépendante du moteur de multiplication.
+
+La mémoire de pointe prévue sert aussi de garde-fou : `check_memory` refuse
+un calcul dont le résultat ne tiendrait pas dans la mémoire disponible, avant
+toute allocation, plutôt que de laisser le système tuer le processus.
+"""
+
+import math
+import sys
+import time
+from dataclasses import dataclass
+from typing import Optional
+
+from .cli.output import _format_bytes, _format_duration, group_digits
+from .core.algorithms import predict_bit_length, predict_digits
+from .core.multiplication import _square
+from .core.resources import available_memory
+
+# Taille (en bits) du plus grand carré mesuré ; le second en fait le quart.
+SAMPLE_BITS = 1 << 18
+
+# Nombre d'entiers de la taille du résultat vivants simultanément lors de la
+# dernière étape du "Fast Doubling" : les trois produits, leur somme et le
+# couple retourné.
+_PEAK_RESULT_COPIES = 5
+
+
+@dataclass(frozen=True)
+class CalculationEstimate:
+    """Coût prévu du calcul de F(n).
+
+    Attributes:
+        n (int): L'indice considéré.
+        digits (int): Le nombre de chiffres décimaux prévu.
+        bit_length (int): La longueur en bits prévue.
+        peak_memory_bytes (int): La mémoire de pointe prévue, en octets,
+            représentation décimale du résultat comprise.
+        duration (float): La durée prévue du calcul, en secondes, hors
+            conversion décimale.
+    """
+
+    n: int
+    digits: int
+    bit_length: int
+    peak_memory_bytes: int
+    duration: float
+
+
+def _int_size(bits: int) -> int:
+    """Retourne la taille en octets d'un entier Python de `bits` bits."""
+    digits = -(-bits // sys.int_info.bits_per_digit)
+    return digits * sys.int_info.sizeof_digit
+
+
+def predict_peak_memory(n: int) -> int:
+    """Prévoit la mémoire de pointe du calcul de F(n), en octets.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite.
+
+    Returns:
+        int: La mémoire de pointe prévue, représentation décimale du résultat
+        comprise.
+    """
+    return _PEAK_RESULT_COPIES * _int_size(predict_bit_length(n)) + predict_digits(n)
+
+
+def check_memory(n: int) -> Optional[str]:
+    """Vérifie que le calcul de F(n) tient dans la mémoire disponible.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite.
+
+    Returns:
+        Optional[str]: Un message d'erreur si la mémoire de pointe prévue
+        dépasse la mémoire disponible, `None` sinon (ou si celle-ci est
+        inconnue).
+    """
+    available = available_memory()
+    required = predict_peak_memory(n)
+    if available is None or required <= available:
+        return None
+    return (
+        f"Le calcul de F({n}) nécessiterait environ {_format_bytes(required)} de mémoire, "
+        f"au-delà des {_format_bytes(available)} disponibles."
+    )
+
+
+def _measure_square(bits: int, repeat: int = 3) -> float:
+    """Mesure la meilleure durée, sur `repeat` essais, d'un carré de `bits` bits."""
+    x = (1 << bits) - 1
+    best = math.inf
+    for _ in range(repeat):
+        start = time.perf_counter()
+        _square(x)
+        best = min(best, time.perf_counter() - start)
+    return best
+
+
+def estimate_calculation(n: int) -> CalculationEstimate:
+    """Prévoit la taille, la mémoire de pointe et la durée du calcul de F(n).
+
+    Chaque étape du "Fast Doubling" effectue trois produits d'opérandes de
+    taille moitié de celle de l'étape suivante ; le coût total est donc une
+    série géométrique dominée par la dernière étape. La durée d'un carré de
+    la taille voulue est extrapolée des mesures, suivant une loi de
+    puissance dont l'exposant est mesuré (et borné entre 1 et 2).
+
+    Args:
+        n (int): L'indice (éventuellement négatif) de la suite.
+
+    Returns:
+        CalculationEstimate: Les prévisions pour F(n).
+    """
+    bits = predict_bit_length(n)
+    digits = predict_digits(n)
+    peak_memory = predict_peak_memory(n)
+
+    half = max(bits // 2, 1)
+    sample = min(half, SAMPLE_BITS)
+    large, small = _measure_square(sample), _measure_square(max(sample // 4, 1))
+    exponent = math.log(large / small, 4) if large > 0 and small > 0 else 2.0
+    exponent = min(max(exponent, 1.0), 2.0)
+    final_step = 3 * large * (half / sample) ** exponent
+    duration = final_step / (1 - 2**-exponent)
+
+    return CalculationEstimate(n, digits, bits, peak_memory, duration)
+
+
+def describe_estimate(estimate: CalculationEstimate, sep: str = " ") -> str:
+    """Met en forme une estimation pour l'affichage.
+
+    Args:
+        estimate (CalculationEstimate): L'estimation à décrire.
+        sep (str): Le séparateur des milliers.
+
+    Returns:
+        str: Le rapport, sur plusieurs lignes.
+    """
+    digits = group_digits(estimate.digits, sep)
+    bits = group_digits(estimate.bit_length, sep)
+    return "\n".join(
+        [
+            f"Estimation pour F({estimate.n}) par 'Fast Doubling' :",
+            f"  Chiffres décimaux : ~{digits}",
+            f"  Taille binaire    : ~{bits} bits",
+            f"  Mémoire de pointe : ~{_format_bytes(estimate.peak_memory_bytes)}",
+            f"  Durée du calcul   : ~{_format_duration(estimate.duration)} (hors conversion décimale)",
+        ]
+    )
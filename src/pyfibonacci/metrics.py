@@ -0,0 +1,114 @@
+"""
+Module d'instrumentation Prometheus des calculs.
+
+Les métriques sont enregistrées dans le registre par défaut de
+`prometheus_client` et alimentées par l'exécution de chaque algorithme, que
+le calcul soit lancé depuis la ligne de commande ou depuis le serveur HTTP
+(qui les publie sur `GET /metrics`) :
+
+- `pyfibonacci_calculation_duration_seconds` : histogramme des durées des
+  calculs réussis, par algorithme ;
+- `pyfibonacci_calculations_total` : nombre de calculs, par algorithme et
+  par issue (voir `OUTCOMES`) ;
+- `pyfibonacci_largest_n` : plus grande valeur de |n| demandée ;
+- `pyfibonacci_cache_lookups_total` et `pyfibonacci_cache_evictions_total` :
+  consultations (`hit` ou `miss`) et évictions du cache de résultats du
+  serveur (`--cache-size`).
+"""
+
+from prometheus_client import REGISTRY, Counter, Gauge, Histogram, generate_latest
+
+from .cli.output import CalculationResult
+
+# Issues possibles d'un calcul. `mismatch` est compté, en plus de l'issue
+# `success`, pour chaque algorithme d'un mode 'all' dont les résultats
+# divergent.
+OUTCOMES = ("success", "error", "timeout", "cancel", "memory", "mismatch")
+
+# Bornes des intervalles de l'histogramme, en secondes : du calcul quasi
+# instantané aux très grands indices.
+DURATION_BUCKETS = (0.001, 0.01, 0.1, 0.5, 1.0, 5.0, 10.0, 30.0, 60.0, 300.0)
+
+CALCULATION_DURATION = Histogram(
+    "pyfibonacci_calculation_duration_seconds",
+    "Durée des calculs réussis, en secondes.",
+    ["algorithm"],
+    buckets=DURATION_BUCKETS,
+)
+
+CALCULATIONS = Counter(
+    "pyfibonacci_calculations_total",
+    "Nombre de calculs, par algorithme et par issue.",
+    ["algorithm", "outcome"],
+)
+
+LARGEST_N = Gauge(
+    "pyfibonacci_largest_n",
+    "Plus grande valeur de |n| demandée depuis le démarrage.",
+)
+
+CACHE_LOOKUPS = Counter(
+    "pyfibonacci_cache_lookups_total",
+    "Nombre de consultations du cache de résultats, par résultat (hit ou miss).",
+    ["result"],
+)
+
+CACHE_EVICTIONS = Counter(
+    "pyfibonacci_cache_evictions_total",
+    "Nombre de résultats évincés du cache, faute de place.",
+)
+
+_largest_n = 0
+
+
+def record_request(n: int) -> None:
+    """Enregistre l'indice d'un calcul demandé.
+
+    Args:
+        n (int): L'indice (éventuellement négatif) demandé.
+    """
+    global _largest_n
+    if abs(n) > _largest_n:
+        _largest_n = abs(n)
+        LARGEST_N.set(_largest_n)
+
+
+def record_result(result: CalculationResult) -> None:
+    """Enregistre l'issue et, en cas de succès, la durée d'un calcul.
+
+    Args:
+        result (CalculationResult): Le résultat de l'algorithme.
+    """
+    if result.success:
+        CALCULATION_DURATION.labels(result.algorithm).observe(result.duration)
+    CALCULATIONS.labels(result.algorithm, result.outcome).inc()
+
+
+def record_outcome(algorithm: str, outcome: str) -> None:
+    """Enregistre une issue qui n'est pas portée par un `CalculationResult`.
+
+    Args:
+        algorithm (str): Le nom de l'algorithme.
+        outcome (str): L'issue, parmi `OUTCOMES` (typiquement `cancel` ou
+            `mismatch`).
+    """
+    CALCULATIONS.labels(algorithm, outcome).inc()
+
+
+def record_cache_lookup(hit: bool) -> None:
+    """Enregistre une consultation du cache de résultats.
+
+    Args:
+        hit (bool): Vrai si le résultat était présent dans le cache.
+    """
+    CACHE_LOOKUPS.labels("hit" if hit else "miss").inc()
+
+
+def record_cache_eviction() -> None:
+    """Enregistre l'éviction d'un résultat du cache."""
+    CACHE_EVICTIONS.inc()
+
+
+def exposition() -> bytes:
+    """Retourne les métriques au format texte d'exposition de Prometheus."""
+    return generate_latest(REGISTRY)
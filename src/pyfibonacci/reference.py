@@ -0,0 +1,134 @@
+"""
+Module du cache des résultats de référence, indexés par n.
+
+Pour valider un nouvel algorithme, il suffit de le confronter à un résultat
+déjà établi plutôt que de recalculer F(n) avec tous les autres : une
+exécution avec `--cache-reference` enregistre le résultat sur lequel les
+algorithmes s'accordent, et une exécution ultérieure avec `--compare-cached`
+compare le sien à celui du cache.
+
+Chaque référence occupe deux fichiers dans le répertoire du cache :
+`F<n>.bin`, la valeur au format d'export binaire (voir `write_binary`), et
+`F<n>.json`, ses métadonnées (algorithme d'origine, taille en bits et
+empreinte SHA-256 de l'export). L'empreinte est vérifiée à chaque lecture,
+ce qui écarte une référence corrompue au lieu de lui faire confiance.
+"""
+
+import json
+import os
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Optional, Tuple, Union
+
+from .cli.output import hash_value, read_binary, write_binary
+
+# Algorithme des empreintes enregistrées avec les références.
+REFERENCE_HASH_ALGORITHM = "sha256"
+
+
+@dataclass(frozen=True)
+class Reference:
+    """Un résultat de référence lu dans le cache.
+
+    Attributes:
+        n (int): L'indice du terme.
+        value (int): La valeur de F(n).
+        algorithm (str): L'algorithme qui a produit la référence.
+        hash (str): L'empreinte SHA-256 de l'export binaire de la valeur.
+    """
+
+    n: int
+    value: int
+    algorithm: str
+    hash: str
+
+
+def default_reference_dir() -> Path:
+    """Retourne le répertoire du cache des références par défaut.
+
+    Returns:
+        Path: `$XDG_CACHE_HOME/pyfibonacci/references`, ou à défaut
+        `~/.cache/pyfibonacci/references`.
+    """
+    cache_home = os.environ.get("XDG_CACHE_HOME")
+    base = Path(cache_home) if cache_home else Path.home() / ".cache"
+    return base / "pyfibonacci" / "references"
+
+
+def _reference_paths(n: int, directory: Union[str, Path, None]) -> Tuple[Path, Path]:
+    """Retourne les chemins de la valeur et des métadonnées de la référence de F(n)."""
+    root = Path(directory) if directory is not None else default_reference_dir()
+    return root / f"F{n}.bin", root / f"F{n}.json"
+
+
+def save_reference(
+    n: int, value: int, algorithm: str, directory: Union[str, Path, None] = None
+) -> Path:
+    """Enregistre F(n) comme référence, en remplaçant une éventuelle référence existante.
+
+    Chaque fichier est d'abord écrit à côté de sa destination puis renommé,
+    pour qu'une interruption ne laisse pas de référence tronquée.
+
+    Args:
+        n (int): L'indice du terme.
+        value (int): La valeur de F(n).
+        algorithm (str): L'algorithme qui a produit la valeur.
+        directory (Union[str, Path, None]): Le répertoire du cache ; par
+            défaut, `default_reference_dir()`.
+
+    Returns:
+        Path: Le chemin du fichier de la valeur.
+
+    Raises:
+        OSError: Si le répertoire ou les fichiers ne peuvent être écrits.
+    """
+    value_path, meta_path = _reference_paths(n, directory)
+    value_path.parent.mkdir(parents=True, exist_ok=True)
+    metadata = {
+        "n": n,
+        "algorithm": algorithm,
+        "bit_length": value.bit_length(),
+        "hash_algorithm": REFERENCE_HASH_ALGORITHM,
+        "hash": hash_value(value, REFERENCE_HASH_ALGORITHM),
+    }
+    partial = value_path.with_suffix(".bin.tmp")
+    with open(partial, "wb") as stream:
+        write_binary(stream, value)
+    os.replace(partial, value_path)
+    partial = meta_path.with_suffix(".json.tmp")
+    partial.write_text(json.dumps(metadata, indent=2) + "\n", encoding="utf-8")
+    os.replace(partial, meta_path)
+    return value_path
+
+
+def load_reference(n: int, directory: Union[str, Path, None] = None) -> Optional[Reference]:
+    """Lit la référence de F(n) dans le cache.
+
+    Args:
+        n (int): L'indice du terme.
+        directory (Union[str, Path, None]): Le répertoire du cache ; par
+            défaut, `default_reference_dir()`.
+
+    Returns:
+        Optional[Reference]: La référence, ou `None` si F(n) n'est pas en
+        cache.
+
+    Raises:
+        OSError: Si les fichiers de la référence ne peuvent être lus.
+        ValueError: Si la référence est malformée, ou si la valeur lue ne
+            correspond pas à l'empreinte enregistrée.
+    """
+    value_path, meta_path = _reference_paths(n, directory)
+    if not meta_path.exists():
+        return None
+    try:
+        metadata = json.loads(meta_path.read_text(encoding="utf-8"))
+        algorithm, expected_hash = str(metadata["algorithm"]), str(metadata["hash"])
+        hash_algorithm = metadata["hash_algorithm"]
+    except (json.JSONDecodeError, KeyError, TypeError) as e:
+        raise ValueError(f"Métadonnées de référence malformées dans '{meta_path}'.") from e
+    with open(value_path, "rb") as stream:
+        value = read_binary(stream)
+    if metadata.get("n") != n or hash_value(value, hash_algorithm) != expected_hash:
+        raise ValueError(f"La référence '{value_path}' ne correspond pas à son empreinte : elle est corrompue.")
+    return Reference(n, value, algorithm, expected_hash)
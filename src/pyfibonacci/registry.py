@@ -0,0 +1,184 @@
+"""
+Module du registre des algorithmes, ouvert aux extensions.
+
+Le registre associe les noms acceptés par `--algo` aux fonctions de calcul.
+Un code externe peut y ajouter ses propres algorithmes avec
+`register_algorithm`, sans modifier PyFibonacci : ils deviennent alors
+sélectionnables par `--algo`, participent au mode `all` et sont exposés par
+le serveur HTTP.
+
+Un paquet installé peut aussi déclarer ses algorithmes comme points d'entrée
+du groupe `pyfibonacci.algorithms`, chargés au démarrage de la CLI par
+`load_plugins` :
+
+    [project.entry-points."pyfibonacci.algorithms"]
+    mon_algo = "mon_paquet.module:ma_fonction"
+
+Un algorithme est soit une coroutine `async def f(context, n) -> int`, qui
+reçoit le `CalculationContext` et peut s'appuyer sur `multiply`, soit une
+fonction synchrone `def f(n) -> int`, exécutée dans le pool de threads par
+défaut pour ne pas bloquer la boucle d'événements ; elle n'a pas à être
+sérialisable, mais, le GIL étant partagé, elle ne s'exécute pas en
+parallèle des autres calculs en Python du processus. Dans les deux cas,
+`n` est positif ou nul : le signe des indices négatifs est appliqué ensuite
+selon l'identité du "negafibonacci".
+"""
+
+import asyncio
+import inspect
+from dataclasses import dataclass
+from importlib.metadata import entry_points
+from types import MappingProxyType
+from typing import Awaitable, Callable, Dict, List, Mapping, Optional, Tuple
+
+from .core.algorithms import (
+    fib_fast_doubling,
+    fib_iterative,
+    fib_matrix,
+    lucas_fast_doubling,
+)
+
+# Signature d'un algorithme : coroutine `(context, n)` ou fonction `(n)`.
+Algorithm = Callable[..., Awaitable[int] | int]
+
+# Groupe des points d'entrée déclarant des algorithmes externes.
+PLUGIN_ENTRY_POINT_GROUP = "pyfibonacci.algorithms"
+
+# Noms réservés par la CLI, qui ne peuvent désigner un algorithme.
+_RESERVED_NAMES = ("all", "best")
+
+# Le registre des algorithmes disponibles.
+# Il mappe les noms de la CLI aux fonctions (asynchrones ou synchrones).
+ALGORITHM_REGISTRY: Dict[str, Algorithm] = {
+    "iterative": fib_iterative,
+    "matrix": fib_matrix,
+    "fast": fib_fast_doubling,
+    "lucas": lucas_fast_doubling,
+}
+
+
+# Nom complet et complexité des algorithmes intégrés (voir `describe_algorithms`).
+_BUILTIN_DETAILS: Dict[str, Tuple[str, str]] = {
+    "iterative": ("Itération", "O(n) additions de grands entiers"),
+    "matrix": ("Exponentiation matricielle", "O(log n) produits de matrices 2x2"),
+    "fast": ("Fast Doubling", "O(log n) étapes de trois multiplications"),
+    "lucas": ("Fast Doubling (Lucas)", "O(log n) étapes de trois multiplications"),
+}
+
+
+@dataclass(frozen=True)
+class AlgorithmInfo:
+    """Description d'un algorithme enregistré.
+
+    Attributes:
+        name (str): Le nom accepté par `--algo`.
+        title (str): Le nom complet de la méthode.
+        summary (str): Sa description en une ligne (la première ligne de la
+            documentation de la fonction).
+        complexity (Optional[str]): Sa complexité, si elle est connue.
+        parallel (bool): Vrai si l'algorithme est une coroutine, dont les
+            grandes multiplications peuvent être déléguées au pool de
+            processus (voir `--threshold`) ; faux s'il s'exécute d'un bloc
+            dans un thread.
+    """
+
+    name: str
+    title: str
+    summary: str
+    complexity: Optional[str]
+    parallel: bool
+
+
+def register_algorithm(name: str, func: Algorithm) -> None:
+    """Ajoute un algorithme au registre.
+
+    Args:
+        name (str): Le nom sous lequel l'algorithme est sélectionné par
+            `--algo` et par le paramètre `algo` du serveur.
+        func (Algorithm): La fonction de calcul (voir la documentation du
+            module pour les signatures acceptées).
+
+    Raises:
+        ValueError: Si le nom est vide, réservé (`all`, `best`), contient une virgule
+            ou un blanc, ou est déjà enregistré.
+        TypeError: Si `func` n'est pas appelable.
+    """
+    if not name or name in _RESERVED_NAMES or any(c == "," or c.isspace() for c in name):
+        raise ValueError(f"Nom d'algorithme invalide : '{name}'.")
+    if name in ALGORITHM_REGISTRY:
+        raise ValueError(f"L'algorithme '{name}' est déjà enregistré.")
+    if not callable(func):
+        raise TypeError(f"L'algorithme '{name}' doit être une fonction, pas {type(func).__name__}.")
+    ALGORITHM_REGISTRY[name] = func
+
+
+def select_best_algorithm() -> str:
+    """Retourne l'algorithme intégré désigné par `--algo best`.
+
+    `best` est pour l'instant un alias de `fast` : dans l'application, le
+    "Fast Doubling" est le plus rapide à toutes les tailles. L'itération
+    s'exécute dans le pool de threads par défaut, dont le seul aller-retour
+    coûte davantage qu'un petit calcul par "doubling", et l'exponentiation
+    matricielle effectue plus de produits par bit de `n`.
+
+    Returns:
+        str: Le nom de l'algorithme retenu.
+    """
+    return "fast"
+
+
+def describe_algorithms() -> List[AlgorithmInfo]:
+    """Décrit les algorithmes enregistrés, extensions comprises.
+
+    Le nom complet et la complexité des algorithmes intégrés sont connus ;
+    ceux d'une extension sont déduits de sa fonction (nom et documentation).
+
+    Returns:
+        List[AlgorithmInfo]: Une description par algorithme, dans l'ordre
+        d'enregistrement.
+    """
+    infos = []
+    for name, func in ALGORITHM_REGISTRY.items():
+        title, complexity = _BUILTIN_DETAILS.get(name, (getattr(func, "__name__", name), None))
+        doc = inspect.getdoc(func) or ""
+        infos.append(
+            AlgorithmInfo(
+                name=name,
+                title=title,
+                summary=doc.splitlines()[0] if doc else "",
+                complexity=complexity,
+                parallel=asyncio.iscoroutinefunction(func),
+            )
+        )
+    return infos
+
+
+def get_algorithms() -> Mapping[str, Algorithm]:
+    """Retourne une vue en lecture seule du registre.
+
+    Returns:
+        Mapping[str, Algorithm]: Les algorithmes enregistrés, par nom, dans
+        l'ordre d'enregistrement.
+    """
+    return MappingProxyType(ALGORITHM_REGISTRY)
+
+
+def load_plugins() -> List[str]:
+    """Enregistre les algorithmes déclarés par les paquets installés.
+
+    Chaque point d'entrée du groupe `PLUGIN_ENTRY_POINT_GROUP` est chargé et
+    enregistré sous son nom.
+
+    Returns:
+        List[str]: Les noms des algorithmes enregistrés.
+
+    Raises:
+        ValueError: Si un point d'entrée porte un nom invalide ou déjà pris.
+        TypeError: Si un point d'entrée ne désigne pas une fonction.
+        ImportError: Si le module d'un point d'entrée ne peut être importé.
+    """
+    loaded = []
+    for entry_point in entry_points(group=PLUGIN_ENTRY_POINT_GROUP):
+        register_algorithm(entry_point.name, entry_point.load())
+        loaded.append(entry_point.name)
+    return loaded
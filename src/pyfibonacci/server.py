@@ -0,0 +1,473 @@
+"""
+Module du mode serveur HTTP de PyFibonacci.
+
+Ce module expose les algorithmes de calcul via un petit serveur HTTP/1.1
+bâti sur `asyncio.start_server`, sans framework web. Le point d'accès
+`GET /fib?n=1000&algo=fast` retourne un document JSON ; `GET /metrics`
+publie les métriques Prometheus (voir `metrics`). Chaque requête est
+traitée dans sa propre tâche : si le client se déconnecte avant la réponse,
+le calcul en cours est annulé. Un cache LRU optionnel (`CachingExecutor`)
+sert instantanément les indices fréquemment demandés ; il peut être vidé
+pendant les périodes creuses, pour rendre sa mémoire au système.
+
+Pour un orchestrateur (Kubernetes...), `GET /healthz` indique que le
+serveur répond (sonde de vivacité) et `GET /readyz` qu'il accepte de
+nouveaux calculs (sonde de disponibilité). À la réception de SIGTERM, le
+serveur cesse d'être disponible, refuse les nouveaux calculs (statut 503)
+et laisse à ceux en cours un délai de grâce pour aboutir, avant d'annuler
+les retardataires et de s'arrêter (voir `ServerState.drain`).
+"""
+
+import asyncio
+import contextlib
+import json
+import signal
+import time
+from collections import OrderedDict
+from http import HTTPStatus
+from typing import Any, Awaitable, Callable, Collection, Dict, List, Optional, Set, Tuple
+from urllib.parse import parse_qs, urlsplit
+
+from prometheus_client import CONTENT_TYPE_LATEST
+
+from . import metrics
+from .cli.output import CalculationResult
+
+# Fonction exécutant un calcul `(n, algo_name)` sous le timeout configuré.
+CalculationExecutor = Callable[[int, str], Awaitable[CalculationResult]]
+
+# Valeur par défaut de la borne supérieure de |n| acceptée par le serveur,
+# pour éviter qu'une seule requête ne monopolise la machine.
+DEFAULT_MAX_N = 10_000_000
+
+# Taille maximale acceptée pour la ligne de requête et les en-têtes.
+_MAX_REQUEST_LINE = 8192
+
+# Délai de grâce par défaut, en secondes, laissé aux calculs en cours à
+# l'arrêt du serveur : sous les 30 secondes qu'un orchestrateur accorde
+# habituellement avant de tuer le processus.
+DEFAULT_SHUTDOWN_GRACE = 25.0
+
+# Délai minimal, en secondes, laissé aux connexions pour écrire leur réponse
+# et se fermer une fois les calculs terminés ou annulés, même si le délai de
+# grâce est épuisé.
+_CLOSE_TIMEOUT = 1.0
+
+# Routes servies, avec les sondes de vivacité et de disponibilité.
+_ROUTES = ("/fib", "/metrics", "/healthz", "/readyz")
+
+
+class ServerState:
+    """État du serveur partagé par les connexions : disponibilité, connexions et calculs en cours.
+
+    Attributes:
+        ready (bool): Vrai tant que le serveur accepte de nouveaux calculs ;
+            faux dès le début de l'arrêt.
+    """
+
+    def __init__(self) -> None:
+        self.ready = True
+        self._calculations: Set[asyncio.Task] = set()
+        self._connections: Set[asyncio.Task] = set()
+
+    @property
+    def in_flight(self) -> int:
+        """Le nombre de calculs en cours."""
+        return len(self._calculations)
+
+    def track(self, calculation: asyncio.Task) -> None:
+        """Inscrit un calcul jusqu'à sa fin."""
+        self._calculations.add(calculation)
+        calculation.add_done_callback(self._calculations.discard)
+
+    def track_connection(self, connection: asyncio.Task) -> None:
+        """Inscrit le traitement d'une connexion jusqu'à sa fin."""
+        self._connections.add(connection)
+        connection.add_done_callback(self._connections.discard)
+
+    async def drain(self, grace: float) -> int:
+        """Refuse les nouveaux calculs et attend la fin de ceux en cours.
+
+        Les connexions sont ensuite attendues dans le reste du délai (au moins
+        `_CLOSE_TIMEOUT`), pour qu'elles écrivent leur réponse et se ferment
+        avant l'arrêt de la boucle d'événements, qui les annulerait.
+
+        Args:
+            grace (float): Le délai, en secondes, laissé aux calculs en cours.
+
+        Returns:
+            int: Le nombre de calculs annulés faute d'avoir abouti dans le délai.
+        """
+        self.ready = False
+        loop = asyncio.get_running_loop()
+        deadline = loop.time() + grace
+        pending: Set[asyncio.Task] = set()
+        if self._calculations:
+            _, pending = await asyncio.wait(set(self._calculations), timeout=grace)
+        for calculation in pending:
+            calculation.cancel()
+        if pending:
+            await asyncio.wait(pending)
+        if self._connections:
+            remaining = max(deadline - loop.time(), _CLOSE_TIMEOUT)
+            await asyncio.wait(set(self._connections), timeout=remaining)
+        return len(pending)
+
+
+
+class CachingExecutor:
+    """Enrobe une fonction de calcul d'un cache LRU des résultats réussis.
+
+    Les résultats sont indexés par `(n, algo_name)` ; au-delà de
+    `max_entries`, le résultat consulté le moins récemment est évincé. Seuls
+    les succès sont conservés : un timeout ou une erreur n'empêche pas une
+    requête ultérieure de retenter le calcul. Les entiers Python étant
+    immuables, les valeurs sont partagées sans copie. Le cache n'est
+    manipulé que depuis la boucle d'événements, entre deux points
+    d'attente ; deux requêtes simultanées pour un même indice absent du
+    cache le calculent toutes deux.
+
+    La mémoire retenue est celle des valeurs conservées : jusqu'à
+    `max_entries` fois la taille du plus grand F(n) accepté. `clear` la
+    libère, par exemple pendant une période creuse (voir
+    `drain_when_idle`) ; en contrepartie, les requêtes suivantes
+    recalculent, et réallouent, les valeurs qui auraient été servies depuis
+    le cache.
+
+    Attributes:
+        max_entries (int): Le nombre maximal de résultats conservés.
+    """
+
+    def __init__(self, execute: CalculationExecutor, max_entries: int) -> None:
+        if max_entries < 1:
+            raise ValueError("La taille du cache doit être au moins 1.")
+        self._execute = execute
+        self._entries: OrderedDict[Tuple[int, str], CalculationResult] = OrderedDict()
+        self._last_lookup = time.monotonic()
+        self.max_entries = max_entries
+
+    def __len__(self) -> int:
+        return len(self._entries)
+
+    def clear(self) -> int:
+        """Vide le cache.
+
+        Les valeurs ne sont plus référencées par le cache : leur mémoire est
+        libérée dès qu'aucune réponse en cours ne les utilise.
+
+        Returns:
+            int: Le nombre de résultats retirés.
+        """
+        released = len(self._entries)
+        self._entries.clear()
+        return released
+
+    async def drain_when_idle(self, idle: float) -> None:
+        """Vide le cache chaque fois qu'il n'a pas été consulté depuis `idle` secondes.
+
+        La coroutine ne se termine pas : elle est annulée avec le serveur.
+
+        Args:
+            idle (float): La durée d'inactivité, en secondes, au terme de
+                laquelle le cache est vidé.
+        """
+        while True:
+            await asyncio.sleep(max(self._last_lookup + idle - time.monotonic(), 0.0))
+            if time.monotonic() - self._last_lookup < idle:
+                continue
+            self.clear()
+            # Attend la prochaine consultation, plutôt que de vider à nouveau
+            # un cache resté vide.
+            self._last_lookup = time.monotonic()
+
+    async def __call__(self, n: int, algo_name: str) -> CalculationResult:
+        """Retourne le résultat en cache, ou le calcule et le conserve."""
+        self._last_lookup = time.monotonic()
+        key = (n, algo_name)
+        cached = self._entries.get(key)
+        metrics.record_cache_lookup(cached is not None)
+        if cached is not None:
+            self._entries.move_to_end(key)
+            return cached
+
+        result = await self._execute(n, algo_name)
+        if result.success:
+            self._entries[key] = result
+            if len(self._entries) > self.max_entries:
+                self._entries.popitem(last=False)
+                metrics.record_cache_eviction()
+        return result
+
+
+def parse_listen_address(address: str) -> Tuple[Optional[str], int]:
+    """Analyse une adresse d'écoute de la forme `[hôte]:port`.
+
+    Args:
+        address (str): L'adresse, par exemple `:8080` ou `127.0.0.1:8080`.
+
+    Returns:
+        Tuple[Optional[str], int]: L'hôte (`None` pour toutes les interfaces)
+        et le port.
+
+    Raises:
+        ValueError: Si l'adresse est malformée.
+    """
+    host, sep, port_text = address.rpartition(":")
+    if not sep:
+        raise ValueError(f"Adresse d'écoute invalide '{address}' (attendu : [hôte]:port).")
+    try:
+        port = int(port_text)
+    except ValueError:
+        raise ValueError(f"Port invalide dans l'adresse d'écoute '{address}'.") from None
+    if not 0 <= port <= 65535:
+        raise ValueError(f"Port hors limites dans l'adresse d'écoute '{address}'.")
+    return (host.strip("[]") or None, port)
+
+
+async def handle_fib_request(
+    query: Dict[str, List[str]],
+    execute: CalculationExecutor,
+    algorithms: Collection[str],
+    max_n: int,
+) -> Tuple[int, Dict[str, Any]]:
+    """Traite les paramètres d'une requête `/fib` et calcule le résultat.
+
+    Args:
+        query (Dict[str, List[str]]): Les paramètres de la requête, tels que
+            retournés par `urllib.parse.parse_qs`.
+        execute (CalculationExecutor): La fonction de calcul.
+        algorithms (Collection[str]): Les noms d'algorithmes acceptés.
+        max_n (int): La valeur maximale de |n| acceptée.
+
+    Returns:
+        Tuple[int, Dict[str, Any]]: Le code de statut HTTP et le document JSON
+        de la réponse. En cas de succès, le document contient `n`,
+        `algorithm`, `value` (chaîne décimale), `digits` et `duration_ms`.
+    """
+    if "n" not in query:
+        return HTTPStatus.BAD_REQUEST, {"error": "Paramètre 'n' manquant."}
+    try:
+        n = int(query["n"][0])
+    except ValueError:
+        return HTTPStatus.BAD_REQUEST, {"error": "Le paramètre 'n' doit être un entier."}
+    if abs(n) > max_n:
+        return HTTPStatus.BAD_REQUEST, {
+            "error": f"|n| dépasse la limite autorisée par le serveur ({max_n})."
+        }
+
+    algo_name = query.get("algo", ["fast"])[0]
+    if algo_name not in algorithms:
+        return HTTPStatus.BAD_REQUEST, {
+            "error": f"Algorithme inconnu '{algo_name}'.",
+            "algorithms": sorted(algorithms),
+        }
+
+    result = await execute(n, algo_name)
+    if result.timed_out:
+        return HTTPStatus.GATEWAY_TIMEOUT, {"error": result.error}
+    if not result.success:
+        return HTTPStatus.INTERNAL_SERVER_ERROR, {"error": result.error}
+    return HTTPStatus.OK, {
+        "n": n,
+        "algorithm": algo_name,
+        "value": str(result.value),
+        "digits": len(str(abs(result.value))),
+        "duration_ms": result.duration * 1000,
+    }
+
+
+async def _write_response(
+    writer: asyncio.StreamWriter,
+    status: int,
+    payload: bytes,
+    content_type: str = "application/json",
+) -> None:
+    """Écrit une réponse HTTP/1.1 puis laisse l'appelant fermer la connexion."""
+    head = (
+        f"HTTP/1.1 {status} {HTTPStatus(status).phrase}\r\n"
+        f"Content-Type: {content_type}\r\n"
+        f"Content-Length: {len(payload)}\r\n"
+        "Connection: close\r\n\r\n"
+    )
+    writer.write(head.encode("latin-1") + payload)
+    await writer.drain()
+
+
+async def _write_json(
+    writer: asyncio.StreamWriter, status: int, body: Dict[str, Any]
+) -> None:
+    """Écrit une réponse HTTP/1.1 dont le corps est un document JSON."""
+    await _write_response(writer, status, json.dumps(body).encode("utf-8"))
+
+
+async def _handle_connection(
+    reader: asyncio.StreamReader,
+    writer: asyncio.StreamWriter,
+    execute: CalculationExecutor,
+    algorithms: Collection[str],
+    max_n: int,
+    state: ServerState,
+) -> None:
+    """Traite une connexion : lit une requête, calcule et répond.
+
+    Pendant le calcul, la connexion est surveillée : une fin de flux côté
+    client (déconnexion) annule la tâche de calcul. Les algorithmes
+    asynchrones s'arrêtent alors à leur prochain point d'attente ; une
+    multiplication déjà confiée au pool de processus va cependant à son
+    terme en arrière-plan. Un calcul annulé par l'arrêt du serveur reçoit
+    le statut 503.
+    """
+    try:
+        request_line = await reader.readline()
+        if not request_line or len(request_line) > _MAX_REQUEST_LINE:
+            return
+        # Les en-têtes sont lus puis ignorés : seule la ligne de requête compte.
+        while await reader.readline() not in (b"\r\n", b"\n", b""):
+            pass
+
+        try:
+            method, target, _ = request_line.decode("latin-1").split(" ", 2)
+        except ValueError:
+            await _write_json(writer, HTTPStatus.BAD_REQUEST, {"error": "Requête invalide."})
+            return
+
+        url = urlsplit(target)
+        if url.path not in _ROUTES:
+            await _write_json(writer, HTTPStatus.NOT_FOUND, {"error": "Ressource inconnue."})
+            return
+        if method != "GET":
+            await _write_json(
+                writer, HTTPStatus.METHOD_NOT_ALLOWED, {"error": "Seule la méthode GET est acceptée."}
+            )
+            return
+        if url.path == "/metrics":
+            await _write_response(
+                writer, HTTPStatus.OK, metrics.exposition(), CONTENT_TYPE_LATEST
+            )
+            return
+        if url.path == "/healthz":
+            await _write_json(writer, HTTPStatus.OK, {"status": "ok"})
+            return
+        if url.path == "/readyz":
+            if state.ready:
+                await _write_json(writer, HTTPStatus.OK, {"status": "ready", "in_flight": state.in_flight})
+            else:
+                await _write_json(
+                    writer, HTTPStatus.SERVICE_UNAVAILABLE, {"status": "draining", "in_flight": state.in_flight}
+                )
+            return
+        if not state.ready:
+            await _write_json(
+                writer, HTTPStatus.SERVICE_UNAVAILABLE, {"error": "Serveur en cours d'arrêt."}
+            )
+            return
+
+        calculation = asyncio.create_task(
+            handle_fib_request(parse_qs(url.query), execute, algorithms, max_n)
+        )
+        state.track(calculation)
+        disconnect = asyncio.create_task(reader.read())
+        done, _ = await asyncio.wait(
+            {calculation, disconnect}, return_when=asyncio.FIRST_COMPLETED
+        )
+        if calculation not in done:
+            # Le client s'est déconnecté : inutile de poursuivre le calcul.
+            calculation.cancel()
+            with contextlib.suppress(asyncio.CancelledError):
+                await calculation
+            return
+        disconnect.cancel()
+
+        if calculation.cancelled():
+            await _write_json(
+                writer, HTTPStatus.SERVICE_UNAVAILABLE, {"error": "Calcul interrompu par l'arrêt du serveur."}
+            )
+            return
+        status, body = calculation.result()
+        await _write_json(writer, status, body)
+    except (ConnectionError, asyncio.IncompleteReadError):
+        pass
+    finally:
+        writer.close()
+        with contextlib.suppress(ConnectionError):
+            await writer.wait_closed()
+
+
+async def start_server(
+    address: str,
+    execute: CalculationExecutor,
+    algorithms: Collection[str],
+    max_n: int = DEFAULT_MAX_N,
+    state: Optional[ServerState] = None,
+) -> asyncio.Server:
+    """Démarre le serveur HTTP sans bloquer.
+
+    Args:
+        address (str): L'adresse d'écoute, de la forme `[hôte]:port`.
+        execute (CalculationExecutor): La fonction de calcul.
+        algorithms (Collection[str]): Les noms d'algorithmes acceptés.
+        max_n (int): La valeur maximale de |n| acceptée.
+        state (Optional[ServerState]): L'état partagé par les connexions,
+            par lequel l'appelant peut arrêter le serveur en douceur ; par
+            défaut, un état propre au serveur.
+
+    Returns:
+        asyncio.Server: Le serveur démarré.
+    """
+    host, port = parse_listen_address(address)
+    state = state or ServerState()
+
+    async def _on_connection(reader: asyncio.StreamReader, writer: asyncio.StreamWriter) -> None:
+        state.track_connection(asyncio.current_task())
+        await _handle_connection(reader, writer, execute, algorithms, max_n, state)
+
+    return await asyncio.start_server(_on_connection, host, port)
+
+
+async def run_server(
+    address: str,
+    execute: CalculationExecutor,
+    algorithms: Collection[str],
+    max_n: int = DEFAULT_MAX_N,
+    grace: float = DEFAULT_SHUTDOWN_GRACE,
+) -> None:
+    """Démarre le serveur HTTP et traite les requêtes jusqu'à SIGTERM ou l'interruption.
+
+    À la réception de SIGTERM, le serveur s'arrête en douceur : il cesse
+    d'être disponible, laisse aux calculs en cours jusqu'à `grace` secondes
+    pour aboutir, annule les autres puis retourne. Sur les plates-formes où
+    la boucle d'événements ne peut pas intercepter de signal (Windows),
+    seule l'interruption l'arrête.
+
+    Args:
+        address (str): L'adresse d'écoute, de la forme `[hôte]:port`.
+        execute (CalculationExecutor): La fonction de calcul.
+        algorithms (Collection[str]): Les noms d'algorithmes acceptés.
+        max_n (int): La valeur maximale de |n| acceptée.
+        grace (float): Le délai de grâce des calculs en cours à l'arrêt, en
+            secondes.
+    """
+    state = ServerState()
+    server = await start_server(address, execute, algorithms, max_n, state)
+    for sock in server.sockets:
+        host, port = sock.getsockname()[:2]
+        print(f"Serveur à l'écoute sur http://{host}:{port}/fib")
+
+    loop = asyncio.get_running_loop()
+    stop = asyncio.Event()
+    try:
+        loop.add_signal_handler(signal.SIGTERM, stop.set)
+        handled = True
+    except (NotImplementedError, AttributeError, RuntimeError):
+        handled = False
+    try:
+        async with server:
+            await stop.wait()
+            in_flight = state.in_flight
+            print(f"Arrêt du serveur : {in_flight} calcul(s) en cours, délai de grâce de {grace}s.")
+            cancelled = await state.drain(grace)
+            if cancelled:
+                print(f"Arrêt du serveur : {cancelled} calcul(s) annulé(s) au terme du délai de grâce.")
+    finally:
+        if handled:
+            loop.remove_signal_handler(signal.SIGTERM)
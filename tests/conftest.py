@@ -0,0 +1,43 @@
+"""
+Configuration partagée de la suite de tests.
+
+Les tests de propriétés (Hypothesis) tirent leurs exemples d'une graine
+unique par exécution, affichée dans l'en-tête de pytest. Pour reproduire un
+échec, il suffit de relancer la suite avec la même graine :
+
+    PYFIBONACCI_TEST_SEED=1234 pytest
+"""
+
+import os
+import random
+
+import hypothesis
+import pytest
+
+# Graine des tests de propriétés : celle de l'environnement, ou une graine
+# tirée au hasard, pour que chaque exécution reste reproductible.
+TEST_SEED = int(os.environ.get("PYFIBONACCI_TEST_SEED") or random.getrandbits(32))
+
+
+def pytest_report_header(config):
+    """Affiche la graine des tests de propriétés en tête du rapport."""
+    return f"Tests de propriétés : PYFIBONACCI_TEST_SEED={TEST_SEED}"
+
+
+def pytest_collection_modifyitems(config, items):
+    """Applique la graine de l'exécution à chaque test de propriétés."""
+    for item in items:
+        test = getattr(item, "obj", None)
+        if getattr(test, "is_hypothesis_test", False):
+            hypothesis.seed(TEST_SEED)(test)
+
+
+@pytest.fixture(autouse=True)
+def isolated_config(tmp_path, monkeypatch):
+    """
+    Redirige le fichier de configuration vers un répertoire temporaire afin
+    que la configuration réelle de l'utilisateur n'influence pas les tests.
+    """
+    path = tmp_path / "pyfibonacci.json"
+    monkeypatch.setenv("PYFIBONACCI_CONFIG", str(path))
+    return path
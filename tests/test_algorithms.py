@@ -3,8 +3,30 @@ Suite de tests pour les algorithmes de calcul de Fibonacci.
 """
 import asyncio
 import pytest
-from pyfibonacci.core.algorithms import fib_iterative, fib_matrix, fib_fast_doubling
-from pyfibonacci.core.context import CalculationContext
+from concurrent.futures import ProcessPoolExecutor
+from pyfibonacci.core.algorithms import (
+    fib_iterative,
+    fib_matrix,
+    fib_fast_doubling,
+    fib_fast_doubling_pair,
+    fib_fast_doubling_mod,
+    fib_sequence,
+    fib_sum_iterative,
+    fib_last_digits,
+    apply_negafibonacci_sign,
+    apply_negalucas_sign,
+    divisibility_facts,
+    lucas_fast_doubling,
+    LUCAS_LOOKUP_TABLE,
+    _gather_products,
+    predict_bit_length,
+    predict_digits,
+    verify_cassini,
+)
+from pyfibonacci.core import algorithms
+from pyfibonacci.core.context import CalculationContext, CalculationProgress, track_progress
+from pyfibonacci.core.errors import MemoryRiskError
+from pyfibonacci.registry import ALGORITHM_REGISTRY
 
 # Les premiers termes de la suite de Fibonacci pour les tests.
 FIBONACCI_TERMS = [0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144]
@@ -45,11 +67,133 @@ async def test_fib_fast_doubling(context, n, expected):
     result = await fib_fast_doubling(context, n)
     assert result == expected
 
+@pytest.mark.parametrize("n", range(len(FIBONACCI_TERMS) - 1))
+@pytest.mark.asyncio
+async def test_fib_fast_doubling_pair(context, n):
+    """Teste que la variante 'fast doubling' retourne (F(n), F(n+1))."""
+    assert await fib_fast_doubling_pair(context, n) == (FIBONACCI_TERMS[n], FIBONACCI_TERMS[n + 1])
+
+@pytest.mark.asyncio
+async def test_fib_fast_doubling_pair_large_index(context):
+    """Vérifie le couple sur un grand indice, contre l'algorithme itératif."""
+    assert await fib_fast_doubling_pair(context, 1000) == (fib_iterative(1000), fib_iterative(1001))
+
+@pytest.mark.asyncio
+async def test_fib_fast_doubling_pair_negative_input(context):
+    """Teste la gestion des entrées négatives pour la variante retournant le couple."""
+    with pytest.raises(ValueError):
+        await fib_fast_doubling_pair(context, -1)
+
+@pytest.mark.parametrize("start", [0, 5, -7, 1000])
+@pytest.mark.asyncio
+async def test_fib_sequence(context, start):
+    """Vérifie que la suite produite à partir de `start` est exacte, indices négatifs compris."""
+    terms = fib_sequence(context, start)
+    produced = [await anext(terms) for _ in range(12)]
+    await terms.aclose()
+    assert produced == [
+        apply_negafibonacci_sign(n, fib_iterative(abs(n))) for n in range(start, start + 12)
+    ]
+
+@pytest.mark.asyncio
+async def test_fib_sequence_stops_on_cancellation(context):
+    """Vérifie qu'un consommateur sans fin est interrompu par l'annulation de sa tâche."""
+    consumed = 0
+
+    async def consume():
+        nonlocal consumed
+        async for _ in fib_sequence(context, 0):
+            consumed += 1
+
+    task = asyncio.create_task(consume())
+    await asyncio.sleep(0.01)
+    task.cancel()
+    with pytest.raises(asyncio.CancelledError):
+        await task
+    assert consumed > 0
+
+@pytest.mark.parametrize("n", [0, 1, 2, 7, -6, -7, 1000])
+@pytest.mark.asyncio
+async def test_verify_cassini_accepts_exact_values(context, n):
+    """Vérifie que l'identité de Cassini est satisfaite par les valeurs exactes, négatives comprises."""
+    await verify_cassini(context, n, apply_negafibonacci_sign(n, fib_iterative(abs(n))))
+
+@pytest.mark.parametrize("n, value", [(0, 1), (10, 56), (-10, 55), (1000, fib_iterative(1000) ^ (1 << 300))])
+@pytest.mark.asyncio
+async def test_verify_cassini_rejects_corrupted_values(context, n, value):
+    """Vérifie qu'une erreur d'un seul bit, ou de signe, met l'identité en défaut."""
+    with pytest.raises(ValueError):
+        await verify_cassini(context, n, value)
+
+@pytest.mark.asyncio
+async def test_fib_fast_doubling_tracks_progress(context):
+    """Vérifie que le 'fast doubling' compte une étape par bit de n."""
+    with track_progress() as progress:
+        await fib_fast_doubling(context, 1000)
+    assert progress.completed == progress.total == (1000).bit_length()
+
+@pytest.mark.parametrize("n", [2, 1000, 1024, 1025])
+@pytest.mark.asyncio
+async def test_fib_matrix_tracks_progress(n):
+    """Vérifie que l'algorithme matriciel compte une étape par bit de l'exposant n-1."""
+    queue = asyncio.Queue()
+    context = CalculationContext(threshold=10000, progress_queue=queue)
+    with track_progress() as progress:
+        assert await fib_matrix(context, n) == fib_iterative(n)
+    assert progress.completed == progress.total == (n - 1).bit_length()
+    assert queue.qsize() == progress.total
+
+@pytest.mark.parametrize("algorithm, steps", [(fib_fast_doubling, (1000).bit_length()), (fib_matrix, (999).bit_length())])
+@pytest.mark.asyncio
+async def test_reporter_receives_each_step(algorithm, steps):
+    """Vérifie que le rapporteur reçoit l'avancement après chaque étape, sans `track_progress`."""
+    reports = []
+    context = CalculationContext(threshold=10000, reporter=lambda p: reports.append((p.completed, p.total)))
+    assert await algorithm(context, 1000) == fib_iterative(1000)
+    assert reports == [(step, steps) for step in range(1, steps + 1)]
+
+@pytest.mark.asyncio
+async def test_reporter_shares_tracked_progress():
+    """Vérifie que le rapporteur reçoit l'avancement suivi par `track_progress`, s'il y en a un."""
+    reported = []
+    context = CalculationContext(threshold=10000, reporter=reported.append)
+    with track_progress() as progress:
+        await fib_fast_doubling(context, 1000)
+    assert reported and all(p is progress for p in reported)
+
+@pytest.mark.asyncio
+async def test_fib_fast_doubling_traces_steps():
+    """Vérifie que le traceur reçoit une mesure par bit de n, du bit de poids fort au plus faible."""
+    events = []
+    context = CalculationContext(threshold=10000, tracer=events.append)
+    assert await fib_fast_doubling(context, 1000) == fib_iterative(1000)
+
+    assert [e.step for e in events] == list(range(1, (1000).bit_length() + 1))
+    assert "".join(str(e.bit) for e in events) == bin(1000)[2:]
+    assert all(e.path == "native" and e.duration >= 0 for e in events)
+    assert events[-1].operand_bits == (2 * fib_iterative(501) - fib_iterative(500)).bit_length()
+
+@pytest.mark.parametrize("completed, total, expected", [(0, 10, 0.0), (10, 10, 1.0), (9, 10, 1 / 3), (0, 0, 0.0)])
+def test_progress_work_fraction(completed, total, expected):
+    """Vérifie que le travail estimé pondère les étapes, la dernière valant les deux tiers."""
+    progress = CalculationProgress(completed=completed, total=total)
+    assert progress.work_fraction == pytest.approx(expected, rel=1e-3)
+
 def test_fib_iterative_negative_input():
     """Teste la gestion des entrées négatives pour l'algorithme itératif."""
     with pytest.raises(ValueError):
         fib_iterative(-1)
 
+@pytest.mark.parametrize("n", range(30))
+def test_fib_sum_iterative_matches_identity(n):
+    """Vérifie que la somme terme à terme vaut F(n+2) - 1."""
+    assert fib_sum_iterative(n) == fib_iterative(n + 2) - 1
+
+def test_fib_sum_iterative_negative_input():
+    """Teste le refus d'un nombre de termes négatif."""
+    with pytest.raises(ValueError):
+        fib_sum_iterative(-1)
+
 @pytest.mark.asyncio
 async def test_fib_matrix_negative_input(context):
     """Teste la gestion des entrées négatives pour l'algorithme matriciel."""
@@ -62,6 +206,117 @@ async def test_fib_fast_doubling_negative_input(context):
     with pytest.raises(ValueError):
         await fib_fast_doubling(context, -1)
 
+@pytest.mark.parametrize("n, expected", enumerate(FIBONACCI_TERMS))
+def test_fib_fast_doubling_mod_small_values(n, expected):
+    """Teste le 'fast doubling' modulaire sur des valeurs connues."""
+    assert fib_fast_doubling_mod(n, 10) == expected % 10
+
+def test_fib_fast_doubling_mod_matches_full_computation():
+    """Vérifie que le résidu correspond à la réduction du calcul complet."""
+    m = 1_000_000_007
+    assert fib_fast_doubling_mod(1000, m) == fib_iterative(1000) % m
+
+def test_fib_fast_doubling_mod_one():
+    """Teste que tout nombre est congru à 0 modulo 1."""
+    assert fib_fast_doubling_mod(12345, 1) == 0
+
+@pytest.mark.parametrize("m", [0, -7])
+def test_fib_fast_doubling_mod_invalid_modulus(m):
+    """Teste le rejet d'un module nul ou négatif."""
+    with pytest.raises(ValueError):
+        fib_fast_doubling_mod(10, m)
+
+def test_fib_fast_doubling_mod_negative_input():
+    """Teste la gestion des entrées négatives pour le 'fast doubling' modulaire."""
+    with pytest.raises(ValueError):
+        fib_fast_doubling_mod(-1, 10)
+
+def test_fib_fast_doubling_mod_huge_index():
+    """Vérifie qu'un indice gigantesque est traité et donne un résidu valide."""
+    # La période de Pisano modulo 10 est 60 : F(n) mod 10 == F(n mod 60) mod 10.
+    n = 250_000_000
+    assert fib_fast_doubling_mod(n, 10) == fib_iterative(n % 60) % 10
+
+@pytest.mark.parametrize("n, k, expected", [
+    (25, 3, "025"),   # F(25) = 75025 : les zéros de tête sont conservés.
+    (20, 4, "6765"),  # Autant de chiffres que demandé.
+    (10, 5, "55"),    # F(10) est plus court que k : aucun zéro ajouté.
+    (0, 3, "0"),
+])
+def test_fib_last_digits(n, k, expected):
+    """Vérifie la complétion par des zéros des derniers chiffres de F(n)."""
+    assert fib_last_digits(n, k) == expected
+
+def test_fib_last_digits_matches_full_computation():
+    """Compare les derniers chiffres au nombre complet, autour de chaque longueur."""
+    for n in range(0, 400, 7):
+        text = str(fib_iterative(n))
+        for k in range(1, len(text) + 3):
+            assert fib_last_digits(n, k) == (text if len(text) < k else text[-k:])
+
+@pytest.mark.parametrize("k", [0, -1])
+def test_fib_last_digits_invalid_count(k):
+    """Vérifie qu'un nombre de chiffres non strictement positif est rejeté."""
+    with pytest.raises(ValueError):
+        fib_last_digits(10, k)
+
+# F(-n) pour n = 0..8 : 0, 1, -1, 2, -3, 5, -8, 13, -21
+NEGAFIBONACCI_TERMS = [0, 1, -1, 2, -3, 5, -8, 13, -21]
+
+@pytest.mark.parametrize("k, expected", enumerate(NEGAFIBONACCI_TERMS))
+def test_apply_negafibonacci_sign(k, expected):
+    """Teste le signe appliqué aux indices négatifs."""
+    assert apply_negafibonacci_sign(-k, fib_iterative(k)) == expected
+
+@pytest.mark.parametrize("n", [0, 1, 2, 10, 11])
+def test_apply_negafibonacci_sign_positive_index_unchanged(n):
+    """Vérifie que les indices positifs ne sont pas modifiés."""
+    assert apply_negafibonacci_sign(n, fib_iterative(n)) == fib_iterative(n)
+
+# Les premiers termes de la suite de Lucas.
+LUCAS_TERMS = [2, 1, 3, 4, 7, 11, 18, 29, 47, 76, 123, 199, 322]
+
+@pytest.mark.parametrize("n, expected", enumerate(LUCAS_TERMS))
+@pytest.mark.asyncio
+async def test_lucas_fast_doubling(context, n, expected):
+    """Teste le calcul des nombres de Lucas sur des valeurs connues."""
+    assert await lucas_fast_doubling(context, n) == expected
+
+@pytest.mark.parametrize("n", [92, 93, 94, 500])
+@pytest.mark.asyncio
+async def test_lucas_fast_doubling_beyond_lookup_table(context, n):
+    """Vérifie L(n) = F(n-1) + F(n+1) à la frontière de la table et au-delà."""
+    assert await lucas_fast_doubling(context, n) == fib_iterative(n - 1) + fib_iterative(n + 1)
+
+@pytest.mark.parametrize("n", [0, 1, 2, 50, 92, 93])
+@pytest.mark.asyncio
+async def test_lucas_fast_doubling_without_lookup_table(n):
+    """Vérifie que le noyau, forcé pour les petits indices, concorde avec la table."""
+    context = CalculationContext(threshold=10000, use_lookup_table=False)
+    with track_progress() as progress:
+        value = await lucas_fast_doubling(context, n)
+    assert value == 2 * fib_iterative(n + 1) - fib_iterative(n)
+    if n < len(LUCAS_LOOKUP_TABLE):
+        assert value == LUCAS_LOOKUP_TABLE[n]
+    assert progress.completed == n.bit_length()
+
+def test_lucas_lookup_table():
+    """Vérifie la taille et la récurrence de la table de Lucas."""
+    assert len(LUCAS_LOOKUP_TABLE) == 93
+    assert LUCAS_LOOKUP_TABLE[:len(LUCAS_TERMS)] == tuple(LUCAS_TERMS)
+    assert LUCAS_LOOKUP_TABLE[92] == fib_iterative(91) + fib_iterative(93)
+
+@pytest.mark.asyncio
+async def test_lucas_fast_doubling_negative_input(context):
+    """Teste la gestion des entrées négatives pour le calcul de Lucas."""
+    with pytest.raises(ValueError):
+        await lucas_fast_doubling(context, -1)
+
+@pytest.mark.parametrize("k, expected", [(1, -1), (2, 3), (3, -4), (4, 7)])
+def test_apply_negalucas_sign(k, expected):
+    """Teste la règle L(-n) = (-1)^n * L(n)."""
+    assert apply_negalucas_sign(-k, LUCAS_TERMS[k]) == expected
+
 # --- Tests basés sur les propriétés avec Hypothesis ---
 
 from hypothesis import given, strategies as st, settings
@@ -91,3 +346,127 @@ async def test_cassini_identity_fast_doubling(n):
     result = fn_plus_1 * fn_minus_1 - fn * fn
 
     assert result == expected
+
+
+@pytest.mark.asyncio
+async def test_fib_fast_doubling_parallel_multiplications():
+    """
+    Vérifie que les trois multiplications d'une étape, lancées ensemble et
+    déléguées au pool de processus, produisent le bon résultat.
+    """
+    with ProcessPoolExecutor() as executor:
+        context = CalculationContext(threshold=10, executor=executor)
+        assert await fib_fast_doubling(context, 2000) == fib_iterative(2000)
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("name", [name for name, func in ALGORITHM_REGISTRY.items() if asyncio.iscoroutinefunction(func)])
+@pytest.mark.parametrize("mul_algo", ["auto", "parallel"])
+async def test_parallel_path_matches_sequential_path(name, mul_algo):
+    """
+    Vérifie que chaque algorithme donne exactement le même résultat lorsque
+    toutes ses multiplications sont déléguées au pool de processus et
+    lorsqu'elles sont toutes effectuées dans le processus courant : les
+    produits d'une étape, lancés ensemble, ne doivent pas se mélanger.
+    """
+    algorithm = ALGORITHM_REGISTRY[name]
+    sequential = CalculationContext(threshold=10000, mul_algo="native", use_lookup_table=False)
+    with ProcessPoolExecutor(max_workers=3) as executor:
+        parallel = CalculationContext(
+            threshold=1, executor=executor, mul_algo=mul_algo, use_lookup_table=False
+        )
+        for n in (1_000, 65_537, 200_003):
+            expected = await algorithm(sequential, n)
+            assert await algorithm(parallel, n) == expected, f"F({n}) diverge en parallèle"
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("mul_algo, expected_order", [("native", ["slow", "fast"]), ("parallel", ["fast", "slow"])])
+async def test_gather_products_runs_concurrently_only_when_parallelizable(mul_algo, expected_order):
+    """
+    Vérifie que les produits ne sont lancés ensemble que si l'un d'eux peut
+    être délégué à l'exécuteur ; sinon, ils sont attendus dans l'ordre.
+    """
+    finished = []
+
+    async def product(name, delay):
+        await asyncio.sleep(delay)
+        finished.append(name)
+        return len(name)
+
+    with ProcessPoolExecutor(max_workers=1) as executor:
+        context = CalculationContext(threshold=10, executor=executor, mul_algo=mul_algo)
+        results = await _gather_products(context, 1, product("slow", 0.02), product("fast", 0))
+
+    assert results == [4, 4]
+    assert finished == expected_order
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("algorithm", [fib_fast_doubling, fib_matrix])
+async def test_step_memory_guard_interrupts_before_allocating(algorithm, monkeypatch):
+    """
+    Vérifie que le calcul est interrompu avant une étape dont les produits
+    dépasseraient la fraction permise de la mémoire disponible.
+    """
+    monkeypatch.setattr(algorithms, "MEMORY_CHECK_BITS", 1)
+    monkeypatch.setattr(algorithms, "available_memory", lambda: 10_000)
+    context = CalculationContext(threshold=10000, memory_fraction=0.5)
+
+    with track_progress() as progress, pytest.raises(MemoryRiskError) as excinfo:
+        await algorithm(context, 100_000)
+
+    assert excinfo.value.allowed == 5_000
+    assert excinfo.value.required > 5_000
+    assert 0 < progress.completed < progress.total
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("memory_fraction, available", [(None, 10_000), (0.5, 10**9), (0.5, None)])
+async def test_step_memory_guard_allows_calculation(memory_fraction, available, monkeypatch):
+    """
+    Vérifie que le calcul aboutit sans contrôle, avec assez de mémoire, ou
+    lorsque la mémoire disponible est inconnue.
+    """
+    monkeypatch.setattr(algorithms, "MEMORY_CHECK_BITS", 1)
+    monkeypatch.setattr(algorithms, "available_memory", lambda: available)
+    context = CalculationContext(threshold=10000, memory_fraction=memory_fraction)
+
+    assert await fib_fast_doubling(context, 10_000) == fib_iterative(10_000)
+
+
+
+def test_predict_size_matches_actual_values():
+    """
+    Vérifie que la taille prédite de F(n) est exacte sur les 3000 premiers indices,
+    y compris pour les indices négatifs.
+    """
+    a, b = 0, 1
+    for n in range(3000):
+        assert predict_bit_length(n) == a.bit_length()
+        assert predict_bit_length(-n) == a.bit_length()
+        assert predict_digits(n) == len(str(a))
+        a, b = b, a + b
+
+
+def test_divisibility_facts_match_actual_values():
+    """
+    Vérifie que les diviseurs déduits de n sont exacts sur les 500 premiers
+    indices, y compris pour les indices négatifs.
+    """
+    a, b = 0, 1
+    for n in range(500):
+        actual = {d: a % d == 0 for d in divisibility_facts(n)}
+        assert divisibility_facts(n) == divisibility_facts(-n) == actual
+        a, b = b, a + b
+
+
+@pytest.mark.parametrize("n", [10**4 + 1, 10**5])
+def test_predict_size_large_indices(n):
+    """
+    Vérifie que la taille prédite est exacte ou à une unité près pour de grands indices.
+    """
+    value = fib_iterative(n)
+    digits = predict_digits(n)
+    assert abs(predict_bit_length(n) - value.bit_length()) <= 1
+    assert 10 ** (digits - 2) <= value < 10 ** (digits + 1)
@@ -2,18 +2,49 @@
 Tests pour le module principal de l'application.
 """
 import asyncio
+import io
+import json
 import sys
+import tracemalloc
 from unittest.mock import AsyncMock, MagicMock, patch
 
 import pytest
-from pyfibonacci.app import (_run_single_algorithm, _run_all_algorithms, main_async)
-from pyfibonacci.core.context import CalculationContext
+from pyfibonacci.app import (
+    _WARMUP_INDEX, _execute_algorithm, _report_properties, _run_batch, _run_gcd, _run_range, _run_single_algorithm, _run_all_algorithms,
+    _warm_up_algorithms, main_async,
+)
+from pyfibonacci.calibrate import CalibrationPoint
+from pyfibonacci.cli.args import EXPECT_MISMATCH_EXIT_CODE, MEMORY_EXIT_CODE, parse_args
+from pyfibonacci.cli.output import CalculationResult, DisplayOptions, hash_value
+from pyfibonacci.core import algorithms
+from pyfibonacci.core.algorithms import apply_negafibonacci_sign, fib_iterative
+from pyfibonacci.core.context import CalculationContext, current_progress
+from pyfibonacci.core.errors import ErrorCategory
+from pyfibonacci.core.snapshot import ProgressSnapshot
+from pyfibonacci.crossover import CROSSOVER_REPEAT, CROSSOVER_TIMEOUT, CrossoverMeasure
+from pyfibonacci.reference import load_reference, save_reference
+from pyfibonacci.registry import ALGORITHM_REGISTRY
+
+
+def make_args(**overrides):
+    """
+    Construit un Namespace d'arguments à partir des valeurs par défaut réelles
+    du parseur, en surchargeant les attributs fournis.
+
+    Contrairement à un `MagicMock`, les options non précisées valent leur
+    défaut (et non un mock « truthy » qui activerait tous les modes).
+    """
+    with patch.object(sys, "argv", ["pyfibonacci"]):
+        args = parse_args()
+    for key, value in overrides.items():
+        setattr(args, key, value)
+    return args
 
 
 @pytest.fixture
 def mock_context():
-    """Fixture pour un contexte de calcul mocké."""
-    return MagicMock(spec=CalculationContext)
+    """Fixture pour un contexte de calcul mocké, sans avertissement de timeout."""
+    return MagicMock(spec=CalculationContext, warn_at=None)
 
 
 @pytest.mark.asyncio
@@ -34,6 +65,120 @@ async def test_run_single_algorithm_sync_and_async(mock_context):
         mock_registry["test_async"].assert_called_once_with(mock_context, 10)
 
 
+@pytest.mark.asyncio
+async def test_warm_up_algorithms_discards_results():
+    """
+    Vérifie que le préchauffage exécute chaque algorithme une fois, sans
+    progression, sans métriques et sans propager ses échecs.
+    """
+    context = CalculationContext(threshold=1000, progress_queue=asyncio.Queue())
+    registry = {
+        "test_sync": MagicMock(return_value=1),
+        "test_async": AsyncMock(side_effect=RuntimeError("boom")),
+    }
+    with (
+        patch("pyfibonacci.app.ALGORITHM_REGISTRY", registry),
+        patch("pyfibonacci.app.metrics.record_result") as record_result,
+    ):
+        await _warm_up_algorithms(context, ["test_async", "test_sync"], timeout=1)
+
+    registry["test_sync"].assert_called_once_with(_WARMUP_INDEX)
+    warm_context, index = registry["test_async"].call_args.args
+    assert index == _WARMUP_INDEX and warm_context.progress_queue is None
+    assert context.progress_queue.empty()
+    record_result.assert_not_called()
+
+
+@pytest.mark.asyncio
+async def test_run_single_algorithm_negative_index(mock_context, capsys):
+    """
+    Vérifie qu'un indice négatif est calculé via F(|n|) puis correctement signé.
+    """
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"test_async": AsyncMock(return_value=55)}) as mock_registry:
+        await _run_single_algorithm(mock_context, -10, "test_async", timeout=1)
+        mock_registry["test_async"].assert_called_once_with(mock_context, 10)
+        assert "Résultat (test_async): -55" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+async def test_run_single_algorithm_lucas_negative_index(mock_context, capsys):
+    """
+    Vérifie que l'algorithme 'lucas' applique sa propre règle de signe.
+    """
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"lucas": AsyncMock(return_value=4)}):
+        await _run_single_algorithm(mock_context, -3, "lucas", timeout=1)
+        assert "Résultat (lucas): -4" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_excludes_lucas(mock_context):
+    """
+    Vérifie que le mode 'all' n'exécute pas l'algorithme de Lucas.
+    """
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "fast": AsyncMock(return_value=55),
+        "lucas": AsyncMock(return_value=123),
+    }) as mock_registry:
+        await _run_all_algorithms(mock_context, 10, timeout=1)
+        mock_registry["fast"].assert_called_once_with(mock_context, 10)
+        mock_registry["lucas"].assert_not_called()
+
+
+@pytest.mark.asyncio
+async def test_run_single_algorithm_base(mock_context, capsys):
+    """
+    Vérifie que le résultat est affiché dans la base demandée.
+    """
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"test_async": AsyncMock(return_value=255)}):
+        await _run_single_algorithm(mock_context, 10, "test_async", timeout=1, display=DisplayOptions(base=16))
+        assert "Résultat (test_async): ff" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.multi_progress_manager", new_callable=AsyncMock)
+async def test_run_all_algorithms_multi_progress(mock_multi_progress_manager):
+    """
+    Vérifie qu'en mode 'multi' chaque algorithme reçoit sa propre file de
+    progression, terminée par 'done'.
+    """
+    context = CalculationContext(threshold=10000)
+    seen_queues = {}
+
+    def recorder(name):
+        async def algo(ctx, n):
+            seen_queues[name] = ctx.progress_queue
+            return 55
+        return algo
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"a": recorder("a"), "b": recorder("b")}):
+        results = await _run_all_algorithms(context, 10, timeout=1, multi_progress=True)
+
+    assert [r.value for r in results] == [55, 55]
+    queues = mock_multi_progress_manager.call_args.args[0]
+    assert queues == seen_queues
+    assert seen_queues["a"] is not seen_queues["b"]
+    for queue in queues.values():
+        assert queue.get_nowait() == "done"
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.multi_progress_manager", new_callable=AsyncMock)
+async def test_run_all_algorithms_multi_progress_reports_failure(mock_multi_progress_manager):
+    """
+    Vérifie que la file d'un algorithme échoué se termine par son issue, et
+    non par 'done'.
+    """
+    context = CalculationContext(threshold=10000)
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "ok": AsyncMock(return_value=55),
+        "boom": AsyncMock(side_effect=RuntimeError("boom")),
+    }):
+        await _run_all_algorithms(context, 10, timeout=1, multi_progress=True)
+
+    queues = mock_multi_progress_manager.call_args.args[0]
+    assert {name: queue.get_nowait() for name, queue in queues.items()} == {"ok": "done", "boom": "error"}
+
+
 @pytest.mark.asyncio
 async def test_run_single_algorithm_timeout(mock_context, capsys):
     """
@@ -91,6 +236,267 @@ async def test_run_all_algorithms(mock_context, capsys):
         assert "Résultat (timeout): TIMEOUT" in captured.err
 
 
+@pytest.mark.asyncio
+@pytest.mark.parametrize("sequential, expected", [
+    (True, ["start a", "end a", "start b", "end b"]),
+    (False, ["start a", "start b", "end a", "end b"]),
+])
+async def test_run_all_algorithms_sequential(mock_context, capsys, sequential, expected):
+    """
+    Vérifie qu'en mode séquentiel, chaque algorithme ne démarre qu'une fois le
+    précédent terminé, et que les résultats restent dans l'ordre demandé.
+    """
+    events = []
+
+    def recorder(name):
+        async def algo(*args):
+            events.append(f"start {name}")
+            await asyncio.sleep(0.01)
+            events.append(f"end {name}")
+            return 55
+        return algo
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"a": recorder("a"), "b": recorder("b")}):
+        results = await _run_all_algorithms(mock_context, 10, timeout=1, sequential=sequential)
+
+    assert events == expected
+    assert [r.algorithm for r in results] == ["a", "b"]
+    assert ("l'un après l'autre" in capsys.readouterr().out) == sequential
+
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_failure_does_not_cancel_others(mock_context):
+    """
+    Vérifie que l'échec ou l'annulation interne d'un algorithme n'interrompt
+    pas les autres : chaque algorithme dispose de son propre timeout, et les
+    échecs sont capturés dans son résultat plutôt que propagés au groupe.
+    """
+    async def slow(*args):
+        """Simule un algorithme encore en cours lorsque les autres échouent."""
+        await asyncio.sleep(0.05)
+        return 55
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "error": AsyncMock(side_effect=RuntimeError("boom")),
+        "cancel": AsyncMock(side_effect=asyncio.CancelledError),
+        "slow": slow,
+    }):
+        results = await _run_all_algorithms(mock_context, 10, timeout=1)
+
+    assert [r.failure.category if r.failure else None for r in results] == [
+        ErrorCategory.INTERNAL, ErrorCategory.CANCELED, None
+    ]
+    assert results[2].value == 55
+
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_reports_divergence(mock_context, capsys):
+    """
+    Vérifie qu'un désaccord désigne l'algorithme minoritaire et la position
+    du premier chiffre qui diffère de la valeur majoritaire.
+    """
+    right, wrong = fib_iterative(300), fib_iterative(300) + 10**20
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "fast": AsyncMock(return_value=right),
+        "wrong": AsyncMock(return_value=wrong),
+        "matrix": AsyncMock(return_value=right),
+    }):
+        await _run_all_algorithms(mock_context, 300, timeout=1)
+
+    err = capsys.readouterr().err
+    position = next(i for i, (a, b) in enumerate(zip(str(right), str(wrong)), 1) if a != b)
+    assert "  - Valeur majoritaire : 'fast', 'matrix'\n" in err
+    assert f"  - Valeur divergente : 'wrong' (premier chiffre différent : position {position})" in err
+    assert "  - Contrôle indépendant de F(n) modulo 2^61 - 1 : en cause : 'wrong'\n" in err
+
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_reports_divergence_without_majority(mock_context, capsys):
+    """
+    Vérifie qu'à égalité, le premier algorithme sert de référence et que les
+    petites valeurs en désaccord sont affichées.
+    """
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "fast": AsyncMock(return_value=55),
+        "wrong": AsyncMock(return_value=65),
+    }):
+        await _run_all_algorithms(mock_context, 10, timeout=1)
+
+    err = capsys.readouterr().err
+    assert "  - Valeur de référence (aucune majorité) : 'fast' = 55\n" in err
+    assert "  - Valeur divergente : 'wrong' = 65 (premier chiffre différent : position 1)" in err
+    assert "en cause : 'wrong'\n" in err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("n", [300, -300, -301])
+async def test_run_all_algorithms_divergence_names_wrong_majority(mock_context, n, capsys):
+    """
+    Vérifie que le contrôle modulaire désigne les algorithmes en cause même
+    lorsqu'ils sont majoritaires, y compris pour un indice négatif.
+    """
+    right, wrong = fib_iterative(abs(n)), fib_iterative(abs(n)) + 10**20
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "fast": AsyncMock(return_value=wrong),
+        "matrix": AsyncMock(return_value=wrong),
+        "iterative": AsyncMock(return_value=right),
+    }):
+        await _run_all_algorithms(mock_context, n, timeout=1)
+
+    err = capsys.readouterr().err
+    assert "en cause : 'fast', 'matrix'\n" in err
+
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_divergence_without_agreeing_value(mock_context, capsys):
+    """Vérifie le verdict du contrôle modulaire lorsqu'aucune valeur n'est juste."""
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "fast": AsyncMock(return_value=56),
+        "wrong": AsyncMock(return_value=65),
+    }):
+        await _run_all_algorithms(mock_context, 10, timeout=1)
+
+    assert "modulo 2^61 - 1 : aucune valeur ne concorde\n" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+async def test_run_single_algorithm_repeat(capsys):
+    """
+    Vérifie qu'avec --repeat l'algorithme est exécuté N fois, que seule la
+    première exécution rapporte sa progression et que les durées sont résumées.
+    """
+    queue = asyncio.Queue()
+    context = CalculationContext(threshold=10000, progress_queue=queue)
+    algo = AsyncMock(return_value=55)
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"fast": algo}):
+        result = await _run_single_algorithm(context, 10, "fast", timeout=1, repeat=3)
+
+    assert algo.call_count == 3
+    assert [call.args[0].progress_queue for call in algo.call_args_list] == [queue, None, None]
+    assert result.value == 55
+    assert len(result.durations) == 3
+    assert min(result.durations) <= result.duration <= max(result.durations)
+    assert "Durée : min " in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_repeat_stops_at_first_failure(capsys):
+    """
+    Vérifie qu'un échec pendant une exécution répétée est rapporté tel quel.
+    """
+    context = CalculationContext(threshold=10000)
+    flaky = AsyncMock(side_effect=[55, ValueError("boom"), 55])
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"ok": AsyncMock(return_value=55), "flaky": flaky}):
+        results = await _run_all_algorithms(context, 10, 1, repeat=3)
+
+    ok, failed = results
+    assert len(ok.durations) == 3
+    assert not failed.success and flaky.call_count == 2
+    captured = capsys.readouterr()
+    assert "Résultat (ok): Calcul terminé (min " in captured.out
+    assert "Résultat (flaky): ERREUR (boom)" in captured.err
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app._run_single_algorithm", new_callable=AsyncMock)
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_estimate_does_not_calculate(
+    mock_process_pool_executor, mock_run_single_algorithm, mock_parse_args, capsys
+):
+    """
+    Vérifie que --estimate affiche les prévisions en JSON sans lancer le calcul.
+    """
+    mock_parse_args.return_value = make_args(n=100000, estimate=True, json=True)
+
+    await main_async()
+
+    mock_run_single_algorithm.assert_not_called()
+    document = json.loads(capsys.readouterr().out)
+    assert document["digits"] == 20899
+    assert document["duration_s"] > 0
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("force", [False, True])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app._run_single_algorithm", new_callable=AsyncMock)
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_refuses_result_exceeding_memory(
+    mock_process_pool_executor, mock_run_single_algorithm, mock_parse_args, force, capsys
+):
+    """
+    Vérifie qu'un résultat dépassant la mémoire disponible est refusé avant le
+    calcul, sauf avec --force.
+    """
+    mock_parse_args.return_value = make_args(n=10**12, force=force)
+    mock_run_single_algorithm.return_value = CalculationResult("fast", 1, 0.1)
+
+    with patch("pyfibonacci.estimate.available_memory", return_value=1024**3):
+        if force:
+            await main_async()
+        else:
+            with pytest.raises(SystemExit) as excinfo:
+                await main_async()
+            assert excinfo.value.code == 1
+
+    captured = capsys.readouterr()
+    if force:
+        mock_run_single_algorithm.assert_called_once()
+    else:
+        mock_run_single_algorithm.assert_not_called()
+        assert "ERREUR: Le calcul de F(1000000000000) nécessiterait environ" in captured.err
+        assert "Utiliser --force pour passer outre." in captured.err
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_color(mock_context, capsys):
+    """
+    Vérifie qu'avec --color always les statuts sont colorés, et qu'ils
+    restent en texte brut avec --color never.
+    """
+    registry = {"ok": MagicMock(return_value=1), "timeout": AsyncMock(side_effect=asyncio.TimeoutError)}
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", registry):
+        await _run_all_algorithms(mock_context, 10, 1, DisplayOptions(color="always"))
+        captured = capsys.readouterr()
+        assert "Résultat (ok): \033[32mCalcul terminé.\033[0m" in captured.out
+        assert "Résultat (timeout): \033[33mTIMEOUT" in captured.err
+
+        await _run_all_algorithms(mock_context, 10, 1, DisplayOptions(color="never"))
+        captured = capsys.readouterr()
+        assert "\033[" not in captured.out + captured.err
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("quiet", [False, True])
+async def test_run_all_algorithms_compare_only(mock_context, capsys, quiet):
+    """
+    Vérifie qu'en mode --compare-only la valeur n'est jamais écrite, et que le
+    verdict de concordance s'affiche hors du mode silencieux.
+    """
+    registry = {"a": MagicMock(return_value=832040), "b": AsyncMock(return_value=832040)}
+    display = DisplayOptions(quiet=quiet, color="never", show_value=False)
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", registry):
+        await _run_all_algorithms(mock_context, 30, 1, display)
+
+    out = capsys.readouterr().out
+    assert "832040" not in out
+    assert ("Statut global : les 2 algorithmes ayant abouti concordent." in out) is not quiet
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_compare_only_json(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'en mode --compare-only le document JSON omet la valeur et le
+    nombre de chiffres, mais conserve les statuts.
+    """
+    mock_parse_args.return_value = make_args(n=30, algo="fast,matrix", json=True, compare_only=True)
+
+    await main_async()
+
+    document = json.loads(capsys.readouterr().out)
+    assert "value" not in document and "digits" not in document
+    assert all(r["success"] for r in document["results"])
+
+
 @pytest.mark.asyncio
 @patch("pyfibonacci.app.parse_args")
 @patch("pyfibonacci.app.run_calibration", new_callable=AsyncMock)
@@ -100,13 +506,42 @@ async def test_main_async_calibrate(mock_process_pool_executor, mock_run_calibra
     Vérifie que `main_async` appelle `run_calibration` lorsque
     l'argument --calibrate est fourni.
     """
-    mock_args = MagicMock(calibrate=True, n=None)
+    mock_args = make_args(calibrate=True, n=None)
     mock_parse_args.return_value = mock_args
 
     await main_async()
     mock_run_calibration.assert_called_once()
 
 
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.run_calibration", new_callable=AsyncMock)
+@patch("pyfibonacci.app.run_crossover", new_callable=AsyncMock)
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_calibrate_crossover(
+    mock_process_pool_executor, mock_run_crossover, mock_run_calibration, mock_parse_args, capsys
+):
+    """
+    Vérifie que `--crossover` remplace la calibration du seuil, compare les
+    algorithmes du mode 'all' et applique le délai par défaut à chaque calcul.
+    """
+    mock_parse_args.return_value = make_args(calibrate=True, crossover=True, n=None)
+    mock_run_crossover.return_value = [CrossoverMeasure(100, {"iterative": 0.001, "matrix": 0.002, "fast": 0.003})]
+
+    with patch("pyfibonacci.app._execute_repeated", new_callable=AsyncMock) as mock_execute:
+        mock_execute.return_value = CalculationResult("fast", 55, 0.1)
+        await main_async()
+        execute, algorithms = mock_run_crossover.call_args.args
+        assert await execute(10, "fast") is mock_execute.return_value
+
+    mock_run_calibration.assert_not_called()
+    assert algorithms == ["iterative", "matrix", "fast"]
+    assert mock_execute.call_args.args[1:] == (10, "fast", CROSSOVER_TIMEOUT, CROSSOVER_REPEAT)
+    captured = capsys.readouterr()
+    assert f"timeout de {CROSSOVER_TIMEOUT}s par calcul" in captured.out
+    assert "à tous les indices mesurés : iterative" in captured.out
+
+
 @pytest.mark.asyncio
 @patch("pyfibonacci.app.parse_args")
 @patch("pyfibonacci.app.ProcessPoolExecutor")
@@ -115,7 +550,7 @@ async def test_main_async_missing_n_argument(mock_process_pool_executor, mock_pa
     Vérifie que `main_async` affiche une erreur si -n est manquant
     sans --calibrate.
     """
-    mock_args = MagicMock(calibrate=False, n=None)
+    mock_args = make_args(calibrate=False, n=None)
     mock_parse_args.return_value = mock_args
 
     with pytest.raises(SystemExit) as e:
@@ -139,7 +574,7 @@ async def test_main_async_progress_bar_deadlock(
     le message "done" n'était jamais envoyé à la queue, bloquant le
     gestionnaire de progression.
     """
-    mock_args = MagicMock(
+    mock_args = make_args(
         n=10,
         algo="fast",
         details=True,  # Active la barre de progression
@@ -148,6 +583,7 @@ async def test_main_async_progress_bar_deadlock(
         threshold=1000
     )
     mock_parse_args.return_value = mock_args
+    mock_run_single_algorithm.return_value = CalculationResult("fast", 55, 0.1)
 
     try:
         # On exécute avec un timeout court. Si le deadlock se produit,
@@ -171,3 +607,1376 @@ async def test_run_single_algorithm_actual_timeout(mock_context, capsys):
         await _run_single_algorithm(mock_context, 10, "long_running", timeout=0.01)
         captured = capsys.readouterr()
         assert "ERREUR: L'algorithme 'long_running' a dépassé le timeout de 0.01s." in captured.err
+
+
+@pytest.mark.asyncio
+async def test_run_single_algorithm_timeout_reports_progress(mock_context, capsys):
+    """
+    Vérifie qu'un timeout indique l'avancement atteint par l'algorithme.
+    """
+    async def partial_algo(*args, **kwargs):
+        """Simule un algorithme bloqué après 18 étapes sur 29."""
+        progress = current_progress()
+        progress.completed, progress.total = 18, 29
+        await asyncio.sleep(0.1)
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"partial": partial_algo}):
+        result = await _run_single_algorithm(mock_context, 10, "partial", timeout=0.01)
+
+    assert result.timed_out
+    assert "~62% (bit 18/29)" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("completed, warned", [(18, True), (29, False)])
+async def test_execute_algorithm_warns_before_timeout(mock_context, completed, warned, capsys):
+    """
+    Vérifie qu'à la fraction `warn_at` du timeout, un calcul dont l'avancement
+    laisse prévoir un dépassement est signalé sur stderr, et lui seul.
+    """
+    async def slow_algo(*args, **kwargs):
+        """Simule un algorithme qui s'attarde après `completed` étapes sur 29."""
+        progress = current_progress()
+        progress.completed, progress.total = completed, 29
+        await asyncio.sleep(0.2)
+        return 1
+
+    mock_context.warn_at = 0.25
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"slow": slow_algo}):
+        await _run_single_algorithm(mock_context, 10, "slow", timeout=0.4)
+
+    err = capsys.readouterr().err
+    assert ("AVERTISSEMENT (slow): à 25% du timeout, le calcul n'est effectué qu'à ~0%" in err) == warned
+
+
+@pytest.mark.asyncio
+async def test_execute_algorithm_registers_snapshot(mock_context):
+    """
+    Vérifie que le calcul s'inscrit à l'instantané de progression du contexte
+    pendant son exécution, et seulement pendant celle-ci.
+    """
+    snapshots = []
+
+    async def reporting_algo(*args, **kwargs):
+        progress = current_progress()
+        progress.completed, progress.total = 1, 2
+        snapshots.append(mock_context.snapshot.describe())
+        return 1
+
+    mock_context.snapshot = ProgressSnapshot()
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"reporting": reporting_algo}):
+        await _execute_algorithm(mock_context, 10, "reporting", timeout=1)
+
+    assert snapshots[0].startswith("Progression (reporting) : 50.0 %")
+    assert "aucun calcul en cours" in mock_context.snapshot.describe()
+
+
+
+@pytest.mark.asyncio
+async def test_run_single_algorithm_structured_failure(mock_context, capsys):
+    """
+    Vérifie que le résultat porte une erreur structurée et que le message
+    affiché dépend de sa catégorie.
+    """
+    registry = {
+        "timeout": AsyncMock(side_effect=asyncio.TimeoutError),
+        "canceled": AsyncMock(side_effect=asyncio.CancelledError),
+        "broken": AsyncMock(side_effect=ValueError("boom")),
+    }
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", registry):
+        results = {name: await _run_single_algorithm(mock_context, 10, name, timeout=1) for name in registry}
+
+    assert results["timeout"].failure.category is ErrorCategory.TIMEOUT
+    assert results["timeout"].timed_out
+    assert results["canceled"].failure.category is ErrorCategory.CANCELED
+    assert results["broken"].failure.category is ErrorCategory.INTERNAL
+    assert isinstance(results["broken"].failure.__cause__, ValueError)
+    assert results["broken"].failure.n == 10
+    err = capsys.readouterr().err
+    assert "a dépassé le timeout" in err
+    assert "'canceled' a été annulé" in err
+    assert "ERREUR inattendue avec l'algorithme 'broken': boom" in err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("force, memory_fraction, code", [(False, 0.9, MEMORY_EXIT_CODE), (True, 0.9, None), (False, 0, None)])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_memory_guard_exit_code(mock_process_pool_executor, mock_parse_args, force, memory_fraction, code, monkeypatch, capsys):
+    """
+    Vérifie qu'un calcul interrompu par le contrôle des allocations se
+    termine avec un code de sortie distinct, sauf avec --force ou si le
+    contrôle est désactivé.
+    """
+    monkeypatch.setattr(algorithms, "MEMORY_CHECK_BITS", 1)
+    monkeypatch.setattr(algorithms, "available_memory", lambda: 10_000)
+    mock_parse_args.return_value = make_args(
+        n=100_000, algo="fast", force=force, memory_fraction=memory_fraction, mul_algo="native"
+    )
+
+    with patch("pyfibonacci.estimate.available_memory", return_value=None):
+        if code is None:
+            await main_async()
+        else:
+            with pytest.raises(SystemExit) as e:
+                await main_async()
+            assert e.value.code == code
+
+    err = capsys.readouterr().err
+    assert ("ERREUR: Mémoire insuffisante pour l'algorithme 'fast'" in err) == (code is not None)
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("runner, overrides", [
+    ("_run_range", {"range": (1, 3)}),
+    ("_run_ratio", {"n": 100, "ratio": 10}),
+    ("_run_sum", {"n": 100, "sum": True}),
+    ("_run_digit_range", {"n": 100, "digit_range": (1, 5)}),
+])
+@pytest.mark.parametrize("force, expected", [(False, 0.9), (True, None)])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_memory_guard_reaches_modes(mock_process_pool_executor, mock_parse_args, runner, overrides, force, expected):
+    """
+    Vérifie que le contrôle des allocations s'applique aussi aux contextes
+    des autres modes, sauf avec --force.
+    """
+    mock_parse_args.return_value = make_args(algo="fast", force=force, memory_fraction=0.9, **overrides)
+
+    with patch(f"pyfibonacci.app.{runner}", AsyncMock(return_value=True)) as mode:
+        await main_async()
+
+    assert mode.await_args.args[0].memory_fraction == expected
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_modular(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que l'option --mod affiche le résidu de F(n) modulo m.
+    """
+    mock_parse_args.return_value = make_args(n=100, mod=[1_000_000_007])
+
+    await main_async()
+
+    captured = capsys.readouterr()
+    assert f"F(100) mod 1000000007 = {354224848179261915075 % 1_000_000_007}" in captured.out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_modular_zero_modulus(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'un module nul est rejeté avec un code de sortie non nul.
+    """
+    mock_parse_args.return_value = make_args(n=100, mod=[7, 0])
+
+    with pytest.raises(SystemExit) as e:
+        await main_async()
+
+    assert e.value.code == 1
+    assert "module" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_modular_negative_index(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que le résidu d'un indice négatif est le plus petit résidu non-négatif.
+    """
+    # F(-10) = -55 et -55 mod 7 = 1.
+    mock_parse_args.return_value = make_args(n=-10, mod=[7])
+
+    await main_async()
+
+    assert "F(-10) mod 7 = 1" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("json_mode", [False, True])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_modular_several_moduli(mock_process_pool_executor, mock_parse_args, json_mode, capsys):
+    """
+    Vérifie qu'une liste de modules donne un résidu par module, en tableau
+    ou, en mode JSON, en objet associant chaque module à son résidu.
+    """
+    moduli = [7, 1_000_000_007, 2**61 - 1]
+    mock_parse_args.return_value = make_args(n=100, mod=moduli, json=json_mode)
+
+    await main_async()
+
+    out = capsys.readouterr().out
+    value = fib_iterative(100)
+    if json_mode:
+        document = json.loads(out)
+        assert document["residues"] == {str(m): str(value % m) for m in moduli}
+    else:
+        assert out.splitlines() == [
+            f"F(100) mod {m:<19} = {value % m}" for m in moduli
+        ]
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_tail(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --tail affiche les derniers chiffres, signe compris.
+    """
+    # F(-26) = -121393.
+    mock_parse_args.return_value = make_args(n=-26, tail=3)
+
+    await main_async()
+
+    assert "Derniers chiffres de F(-26) (3) : -393" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("n, k, expected", [
+    (100, 5, "Extrémités de F(100) (21 chiffres) : 35422...15075"),
+    (-26, 3, "Extrémités de F(-26) (6 chiffres) : -121393"),
+])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_edges(mock_process_pool_executor, mock_parse_args, n, k, expected, capsys):
+    """
+    Vérifie que --edges affiche les extrémités de F(n), ou F(n) entier s'il
+    est court.
+    """
+    # F(100) = 354224848179261915075.
+    mock_parse_args.return_value = make_args(n=n, edges=k)
+
+    await main_async()
+
+    assert expected in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_edges_json(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie le document JSON de --edges.
+    """
+    mock_parse_args.return_value = make_args(n=-100, edges=4, json=True)
+
+    await main_async()
+
+    assert json.loads(capsys.readouterr().out) == {
+        "n": -100, "edges": 4, "digits": 21, "head": "-3542", "tail": "5075",
+    }
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("n, json_output, expected", [
+    (100, False, "Chiffres 3 à 8 de F(100) (21 chiffres) : 422484\n"),
+    (-100, False, "Chiffres 3 à 8 de F(-100) (21 chiffres) : 422484\n"),
+    (100, True, '{"n": 100, "start": 3, "end": 8, "digits": 21, "value": "422484"}\n'),
+])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_digit_range(
+    mock_process_pool_executor, mock_parse_args, n, json_output, expected, capsys
+):
+    """
+    Vérifie que --digit-range affiche les chiffres demandés de F(n), signe exclu.
+    """
+    # F(100) = 354224848179261915075.
+    mock_parse_args.return_value = make_args(n=n, digit_range=(3, 8), json=json_output)
+
+    await main_async()
+
+    assert capsys.readouterr().out == expected
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("n, digit_range, computed, message", [
+    (100, (20, 23), False, "F(100) compte environ 21 chiffres"),
+    (100, (22, 22), True, "qui compte 21 chiffres"),
+])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_digit_range_out_of_bounds(
+    mock_process_pool_executor, mock_parse_args, n, digit_range, computed, message, capsys
+):
+    """
+    Vérifie qu'un intervalle hors de F(n) est refusé avec le code de sortie
+    1, avant le calcul s'il dépasse la taille prévue de plus d'un chiffre.
+    """
+    mock_parse_args.return_value = make_args(n=n, digit_range=digit_range)
+
+    with (
+        patch("pyfibonacci.app._execute_algorithm", wraps=_execute_algorithm) as spy,
+        pytest.raises(SystemExit) as exc_info,
+    ):
+        await main_async()
+
+    assert exc_info.value.code == 1
+    assert spy.called is computed
+    assert message in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("algo", ["all", "fast,matrix", "lucas"])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_digit_range_requires_single_fibonacci_algorithm(
+    mock_process_pool_executor, mock_parse_args, algo, capsys
+):
+    """
+    Vérifie que --digit-range refuse une comparaison et l'algorithme 'lucas'.
+    """
+    mock_parse_args.return_value = make_args(n=100, digit_range=(1, 3), algo=algo)
+
+    with pytest.raises(SystemExit) as exc_info:
+        await main_async()
+
+    assert exc_info.value.code == 1
+    assert "algorithme de Fibonacci unique" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("n, k, expected", [
+    (100, 4, "F(100) ≈ 3.542 × 10^20 (4 chiffres significatifs)"),
+    (10, 5, "F(10) ≈ 5.5000 × 10^1 (5 chiffres significatifs)"),
+    (-100, 3, "F(-100) ≈ -3.54 × 10^20 (3 chiffres significatifs)"),
+    (7, 1, "F(7) ≈ 1 × 10^1 (1 chiffre significatif)"),
+])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_scaled(mock_process_pool_executor, mock_parse_args, n, k, expected, capsys):
+    """
+    Vérifie que --scaled affiche la mantisse tronquée à K chiffres, complétée
+    par des zéros si F(n) est plus court, et l'exposant.
+    """
+    # F(100) = 354224848179261915075.
+    mock_parse_args.return_value = make_args(n=n, scaled=k)
+
+    await main_async()
+
+    assert expected in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_scaled_json_huge_index(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie le document JSON de --scaled pour un indice dont F(n) ne pourrait
+    pas être construit, et la forme compacte de '-q'.
+    """
+    mock_parse_args.return_value = make_args(n=10**9, scaled=12, json=True)
+    await main_async()
+    assert json.loads(capsys.readouterr().out) == {
+        "n": 10**9, "scaled": 12, "digits": 208987640, "mantissa": "7.95231787455", "exponent": 208987639,
+    }
+
+    mock_parse_args.return_value = make_args(n=100, scaled=6, quiet=True)
+    await main_async()
+    assert capsys.readouterr().out == "3.54224e+20\n"
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_ratio(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --ratio affiche F(n)/F(n-1) à côté de φ, avec l'écart.
+    """
+    mock_parse_args.return_value = make_args(n=11, ratio=5)
+
+    await main_async()
+
+    assert capsys.readouterr().out.splitlines() == [
+        "F(11)/F(10) = 1.6182",
+        "φ           = 1.6180",
+        "Écart : 1.48E-4 (3 décimales exactes)",
+    ]
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_ratio_json_beyond_precision(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'un écart inférieur à la précision demandée n'est pas confondu
+    avec le bruit des arrondis.
+    """
+    mock_parse_args.return_value = make_args(n=1000, ratio=30, json=True)
+
+    await main_async()
+
+    document = json.loads(capsys.readouterr().out)
+    assert document["ratio"].startswith("1.61803398874989484820458683")
+    assert document["phi"] == "1.61803398874989484820458683436"
+    assert document["agreeing_decimals"] == 29
+    assert abs(float(document["difference"])) < 1e-29
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("n", [1, 0, -5])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_ratio_undefined(mock_process_pool_executor, mock_parse_args, n, capsys):
+    """
+    Vérifie que --ratio refuse les indices inférieurs à 2.
+    """
+    mock_parse_args.return_value = make_args(n=n, ratio=10)
+
+    with pytest.raises(SystemExit) as e:
+        await main_async()
+
+    assert e.value.code == 1
+    assert "n'est pas défini" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_json_output(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'en mode JSON la sortie standard ne contient qu'un document JSON.
+    """
+    mock_parse_args.return_value = make_args(n=50, algo="all", json=True)
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "iterative": MagicMock(return_value=12586269025),
+        "fast": AsyncMock(return_value=12586269025),
+    }):
+        await main_async()
+
+    document = json.loads(capsys.readouterr().out)
+    assert document["n"] == 50
+    assert document["value"] == "12586269025"
+    assert [r["algorithm"] for r in document["results"]] == ["iterative", "fast"]
+    assert all(r["success"] for r in document["results"])
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("reference, exit_code", [
+    ("354224848179261915075\n", None),
+    ("354224848179261915076\n", 3),
+])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_expect(
+    mock_process_pool_executor, mock_parse_args, reference, exit_code, tmp_path, capsys
+):
+    """
+    Vérifie que --expect compare le résultat au fichier de référence et sort
+    avec un code dédié, en indiquant la position du premier chiffre divergent.
+    """
+    path = tmp_path / "f100.txt"
+    path.write_text(reference, encoding="utf-8")
+    mock_parse_args.return_value = make_args(n=100, algo="fast", expect=str(path))
+
+    if exit_code is None:
+        await main_async()
+        assert "le résultat concorde" in capsys.readouterr().out
+    else:
+        with pytest.raises(SystemExit) as excinfo:
+            await main_async()
+        assert excinfo.value.code == exit_code
+        assert "à la position 21" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_expect_missing_file(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie qu'un fichier de référence illisible est signalé avec le code 1.
+    """
+    mock_parse_args.return_value = make_args(n=10, algo="fast", expect=str(tmp_path / "absent.txt"))
+
+    with pytest.raises(SystemExit) as excinfo:
+        await main_async()
+
+    assert excinfo.value.code == 1
+    assert "ERREUR" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_truncated_result(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --trunc-limit et --trunc-edges tronquent le résultat affiché,
+    mais pas la valeur écrite par '-q'.
+    """
+    digits = str(fib_iterative(1000))
+    mock_parse_args.return_value = make_args(n=1000, algo="fast", trunc_limit=100, trunc_edges=10)
+    await main_async()
+    assert f"Résultat (fast): {digits[:10]}...{digits[-10:]} (209 chiffres)" in capsys.readouterr().out
+
+    mock_parse_args.return_value = make_args(n=1000, algo="fast", trunc_limit=100, quiet=True)
+    await main_async()
+    assert capsys.readouterr().out == digits + "\n"
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_truncation_edges_too_large(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que des extrémités qui couvriraient toute la limite sont refusées.
+    """
+    mock_parse_args.return_value = make_args(n=1000, trunc_limit=50, trunc_edges=25)
+
+    with pytest.raises(SystemExit) as excinfo:
+        await main_async()
+
+    assert excinfo.value.code == 1
+    assert "'--trunc-edges' (25) doit rester inférieur" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_compare_cached(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie qu'une référence enregistrée par --cache-reference valide ensuite
+    un autre algorithme seul, avec --compare-cached.
+    """
+    mock_parse_args.return_value = make_args(
+        n=1000, algo="fast,iterative", cache_reference=True, reference_dir=str(tmp_path)
+    )
+    await main_async()
+    assert "Référence de F(1 000) enregistrée" in capsys.readouterr().out
+
+    mock_parse_args.return_value = make_args(n=1000, algo="matrix", compare_cached=True, reference_dir=str(tmp_path))
+    await main_async()
+    assert "Référence en cache (établie par fast, iterative) : 'matrix' concorde." in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_compare_cached_mismatch(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie qu'un algorithme en désaccord avec la référence en cache est
+    désigné, avec la position du premier chiffre divergent et un code dédié.
+    """
+    save_reference(100, fib_iterative(100) + 10**3, "faux", tmp_path)
+    mock_parse_args.return_value = make_args(n=100, algo="fast", compare_cached=True, reference_dir=str(tmp_path))
+
+    with pytest.raises(SystemExit) as excinfo:
+        await main_async()
+
+    assert excinfo.value.code == EXPECT_MISMATCH_EXIT_CODE
+    assert "'fast' diffère de la référence en cache (établie par faux) à la position 18" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_compare_cached_missing(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie qu'une référence absente du cache est une erreur (code 1).
+    """
+    mock_parse_args.return_value = make_args(n=100, algo="fast", compare_cached=True, reference_dir=str(tmp_path))
+
+    with pytest.raises(SystemExit) as excinfo:
+        await main_async()
+
+    assert excinfo.value.code == 1
+    assert "Aucune référence de F(100)" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_cache_reference_refuses_divergence(
+    mock_process_pool_executor, mock_parse_args, tmp_path, capsys
+):
+    """
+    Vérifie qu'un résultat sur lequel les algorithmes ne s'accordent pas
+    n'est pas enregistré comme référence.
+    """
+    mock_parse_args.return_value = make_args(
+        n=100, algo="fast,iterative", cache_reference=True, reference_dir=str(tmp_path)
+    )
+    corrupted = CalculationResult("iterative", fib_iterative(100) + 1, 0.0)
+
+    with patch("pyfibonacci.app._execute_algorithm", new=AsyncMock(side_effect=[
+        CalculationResult("fast", fib_iterative(100), 0.0), corrupted,
+    ])):
+        with pytest.raises(SystemExit) as excinfo:
+            await main_async()
+
+    assert excinfo.value.code == 1
+    assert "les algorithmes ne concordent pas" in capsys.readouterr().err
+    assert load_reference(100, tmp_path) is None
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_divergence_exit_code(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'un désaccord entre algorithmes termine le programme avec le
+    code dédié, en désignant l'algorithme en cause.
+    """
+    mock_parse_args.return_value = make_args(n=100, algo="fast,iterative")
+
+    with patch("pyfibonacci.app._execute_algorithm", new=AsyncMock(side_effect=[
+        CalculationResult("fast", fib_iterative(100), 0.0),
+        CalculationResult("iterative", fib_iterative(100) + 1, 0.0),
+    ])):
+        with pytest.raises(SystemExit) as excinfo:
+            await main_async()
+
+    assert excinfo.value.code == EXPECT_MISMATCH_EXIT_CODE
+    assert "en cause : 'iterative'" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("algo, scope", [("fast", ""), ("fast,matrix", " (tous algorithmes confondus)")])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_details_reports_memory(
+    mock_process_pool_executor, mock_parse_args, algo, scope, capsys
+):
+    """
+    Vérifie que -d rapporte la consommation mémoire du calcul, agrégée en
+    mode comparaison.
+    """
+    mock_parse_args.return_value = make_args(n=1000, algo=algo, details=True, progress="none")
+
+    await main_async()
+
+    lines = capsys.readouterr().out.splitlines()
+    memory_line = next(line for line in lines if line.startswith("Mémoire : pic de "))
+    assert memory_line.endswith(scope)
+
+
+def test_report_properties(capsys):
+    """
+    Vérifie que la parité et les diviseurs de F(n) sont déduits de n, puis
+    confrontés à chaque résultat valide.
+    """
+    results = [CalculationResult("fast", 832040, 0.1), CalculationResult("slow", None, 1.0, error="timeout")]
+    assert _report_properties(30, results)
+    out = capsys.readouterr().out
+    assert "  - Parité : pair (F(n) est pair ssi 3 divise n)." in out
+    assert "  - Diviseurs parmi 2, 3, 4, 5, 7, 8, 11, 13 : 2, 4, 5, 8, 11." in out
+    assert "Propriétés confirmées par le résultat." in out
+
+    assert not _report_properties(30, [CalculationResult("faulty", 832041, 0.1)])
+    captured = capsys.readouterr()
+    assert "ERREUR: Le résultat de 'faulty' contredit les propriétés de F(30) : il devrait être divisible par 2." in captured.err
+    assert "Propriétés confirmées" not in captured.out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_trace(mock_process_pool_executor, mock_parse_args, tmp_path):
+    """
+    Vérifie que --trace journalise une ligne JSON par étape du 'Fast Doubling'
+    dans le fichier donné.
+    """
+    path = tmp_path / "trace.jsonl"
+    mock_parse_args.return_value = make_args(n=1000, trace=str(path), mul_algo="native")
+
+    await main_async()
+
+    events = [json.loads(line) for line in path.read_text(encoding="utf-8").splitlines()]
+    assert [e["step"] for e in events] == list(range(1, 11))
+    assert set(events[0]) == {"step", "bit", "operand_bits", "path", "duration"}
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("output_format", ["dec", "binary"])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_output_and_decode(
+    mock_process_pool_executor, mock_parse_args, output_format, tmp_path, capsys
+):
+    """
+    Vérifie que --output écrit le résultat au format choisi, et que --decode
+    relit un export binaire sans exiger '-n'.
+    """
+    path = tmp_path / "f100"
+    mock_parse_args.return_value = make_args(n=-100, output=str(path), format=output_format)
+
+    await main_async()
+
+    assert f"Résultat enregistré dans {path}" in capsys.readouterr().out
+    if output_format == "dec":
+        assert path.read_text(encoding="utf-8") == "-354224848179261915075\n"
+        return
+
+    mock_parse_args.return_value = make_args(decode=str(path), json=True)
+    await main_async()
+    document = json.loads(capsys.readouterr().out)
+    assert document == {
+        "file": str(path),
+        "size_bytes": 23,
+        "negative": True,
+        "bit_length": 69,
+        "digits": 21,
+    }
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_decode_invalid_file(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie qu'un fichier qui n'est pas un export binaire est signalé avec le code 1.
+    """
+    path = tmp_path / "f100.txt"
+    path.write_text("354224848179261915075\n", encoding="utf-8")
+    mock_parse_args.return_value = make_args(decode=str(path))
+
+    with pytest.raises(SystemExit) as excinfo:
+        await main_async()
+
+    assert excinfo.value.code == 1
+    assert "pas un export binaire" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_bench_json(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --bench mesure chaque indice avec l'algorithme choisi, sans
+    exiger '-n', et émet son rapport en JSON, moteur de multiplication compris.
+    """
+    mock_parse_args.return_value = make_args(bench=True, json=True, mul_backend="native")
+
+    with patch("pyfibonacci.bench.BENCHMARK_SIZES", (100, 1000)):
+        await main_async()
+
+    document = json.loads(capsys.readouterr().out)
+    assert document["algorithm"] == "fast"
+    assert document["machine"].endswith("multiplication : native")
+    assert [r["n"] for r in document["results"]] == [100, 1000]
+    assert document["results"][0]["bit_length"] == fib_iterative(100).bit_length()
+    assert document["score"] > 0
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_bench_scaling_json(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --scaling calcule F(N) avec un pool par taille, jusqu'à
+    '--workers', et émet l'accélération de chacune en JSON.
+    """
+    mock_parse_args.return_value = make_args(bench=True, scaling=1000, workers=4, json=True)
+
+    with patch("pyfibonacci.app._warm_up_executor", new=AsyncMock()):
+        await main_async()
+
+    document = json.loads(capsys.readouterr().out)
+    assert (document["n"], document["algorithm"]) == (1000, "fast")
+    assert [r["workers"] for r in document["results"]] == [1, 2, 4]
+    assert document["results"][0]["speedup"] == 1.0
+    assert "plateau" in document
+    pools = [c.kwargs.get("max_workers") for c in mock_process_pool_executor.call_args_list]
+    assert pools[-3:] == [1, 2, 4]
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("algo, warmed", [("fast", ["fast"]), ("matrix,fast", ["matrix", "fast"])])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_warmup(mock_process_pool_executor, mock_parse_args, algo, warmed, capsys):
+    """
+    Vérifie que --warmup préchauffe les algorithmes mesurés avant le calcul.
+    """
+    mock_parse_args.return_value = make_args(n=100, algo=algo, warmup=True)
+
+    with (
+        patch("pyfibonacci.app._warm_up_executor", new=AsyncMock()) as warm_up_executor,
+        patch("pyfibonacci.app._warm_up_algorithms", new=AsyncMock()) as warm_up_algorithms,
+    ):
+        await main_async()
+
+    warm_up_executor.assert_awaited_once()
+    context, names, timeout = warm_up_algorithms.await_args.args
+    assert names == warmed and timeout == 10.0
+    out = capsys.readouterr().out
+    assert out.index("Préchauffage des algorithmes...") < out.index("Résultat")
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_digitsum(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --digitsum affiche la somme des chiffres et la racine numérique.
+    """
+    mock_parse_args.return_value = make_args(n=100, algo="fast", digitsum=True)
+
+    await main_async()
+
+    out = capsys.readouterr().out
+    assert "Somme des chiffres : 93 (racine numérique : 3)" in out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_sci_digits(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --sci-digits affiche le résultat en notation scientifique.
+    """
+    mock_parse_args.return_value = make_args(n=100, algo="all", sci_digits=10)
+
+    await main_async()
+
+    assert "Notation scientifique : 3.542248482e+20" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_best_algorithm(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --algo best est résolu en un algorithme unique avant le calcul.
+    """
+    mock_parse_args.return_value = make_args(n=50, algo="best", json=True)
+
+    with patch("pyfibonacci.app.select_best_algorithm", return_value="fast") as spy:
+        await main_async()
+
+    spy.assert_called_once_with()
+    document = json.loads(capsys.readouterr().out)
+    assert [r["algorithm"] for r in document["results"]] == ["fast"]
+    assert document["value"] == "12586269025"
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_algorithm_list(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'une liste d'algorithmes n'exécute et ne compare que ceux indiqués.
+    """
+    mock_parse_args.return_value = make_args(n=50, algo="matrix,iterative", json=True)
+    fast = AsyncMock(return_value=12586269025)
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "iterative": MagicMock(return_value=12586269025),
+        "matrix": AsyncMock(return_value=12586269025),
+        "fast": fast,
+    }):
+        await main_async()
+
+    document = json.loads(capsys.readouterr().out)
+    assert [r["algorithm"] for r in document["results"]] == ["matrix", "iterative"]
+    fast.assert_not_called()
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.run_calibration", new_callable=AsyncMock, return_value=3010)
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_calibrate_save(mock_process_pool_executor, mock_run_calibration, mock_parse_args, isolated_config):
+    """
+    Vérifie que --calibrate-save enregistre le seuil trouvé.
+    """
+    mock_parse_args.return_value = make_args(calibrate=True, calibrate_save=True)
+
+    await main_async()
+
+    assert json.loads(isolated_config.read_text()) == {"threshold": 3010}
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_calibrate_json(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie que --calibrate-json écrit toutes les mesures de la calibration,
+    avec le moteur de multiplication effectivement mesuré.
+    """
+    path = tmp_path / "calibration.json"
+    mock_parse_args.return_value = make_args(calibrate=True, calibrate_json=str(path), mul_backend="native")
+
+    async def calibrate(executor, size_timeout, points, mul_backend):
+        assert mul_backend == "native"
+        points.append(CalibrationPoint(10000, 3010, 0.1, 0.05))
+        return 3010
+
+    with patch("pyfibonacci.app.run_calibration", side_effect=calibrate):
+        await main_async()
+
+    document = json.loads(path.read_text(encoding="utf-8"))
+    assert (document["threshold"], document["machine"]["mul_backend"]) == (3010, "native")
+    assert [p["size_bits"] for p in document["points"]] == [10000]
+    assert f"1 points de mesure enregistrés dans {path}." in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.run_server", new_callable=AsyncMock)
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_serve(mock_process_pool_executor, mock_run_server, mock_parse_args):
+    """
+    Vérifie que --serve démarre le serveur sans exiger -n, et que la fonction
+    de calcul transmise réutilise le registre et le timeout.
+    """
+    mock_parse_args.return_value = make_args(serve=":8080", max_n=500, timeout=2.0, shutdown_grace=5.0)
+
+    await main_async()
+
+    address, execute, algorithms, max_n, grace = mock_run_server.call_args.args
+    assert (address, max_n, grace) == (":8080", 500, 5.0)
+    assert "fast" in algorithms
+    result = await execute(-10, "fast")
+    assert result.value == -55
+
+
+@pytest.mark.asyncio
+async def test_run_batch_outputs_one_line_per_index(mock_context, capsys):
+    """
+    Vérifie le format 'n<TAB>valeur', l'ordre de lecture, et que les indices
+    répétés ne sont calculés qu'une fois.
+    """
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"test_async": AsyncMock(side_effect=lambda ctx, n: n * 11)}) as mock_registry:
+        succeeded = await _run_batch(mock_context, ["10\n", "\n", "# commentaire\n", "3\n", "10\n"], "test_async", timeout=1)
+
+    assert succeeded
+    assert capsys.readouterr().out == "10\t110\n3\t33\n10\t110\n"
+    assert mock_registry["test_async"].call_count == 2
+
+
+@pytest.mark.asyncio
+async def test_run_batch_json_and_invalid_line(mock_context, capsys):
+    """
+    Vérifie l'émission d'un objet JSON par ligne et le signalement des lignes invalides.
+    """
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"test_async": AsyncMock(return_value=55)}):
+        succeeded = await _run_batch(mock_context, ["10", "abc"], "test_async", timeout=1, display=DisplayOptions(json=True))
+
+    assert not succeeded
+    captured = capsys.readouterr()
+    document = json.loads(captured.out)
+    assert (document["n"], document["value"]) == (10, "55")
+    assert "ligne 2" in captured.err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_batch_file(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie que --batch-file lit les indices depuis un fichier, sans exiger -n.
+    """
+    batch_file = tmp_path / "indices.txt"
+    batch_file.write_text("1\n2\n50\n")
+    mock_parse_args.return_value = make_args(batch_file=str(batch_file))
+
+    await main_async()
+
+    assert capsys.readouterr().out == "1\t1\n2\t1\n50\t12586269025\n"
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_verify_detects_wrong_result(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --verify signale un résultat erroné et termine avec un code non nul.
+    """
+    mock_parse_args.return_value = make_args(n=100, algo="fast", verify=True)
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"fast": AsyncMock(return_value=354224848179261915075 + 10**10)}):
+        with pytest.raises(SystemExit) as e:
+            await main_async()
+
+    assert e.value.code == 1
+    assert "vérification de Binet a échoué pour 'fast'" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize(
+    "m, expected",
+    [
+        (144, "La valeur (3 chiffres) est un nombre de Fibonacci : F(12)."),
+        (1, "La valeur (1 chiffre) est un nombre de Fibonacci : F(1) = F(2)."),
+        (145, "La valeur (3 chiffres) n'est pas un nombre de Fibonacci."),
+    ],
+)
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_is_fib(mock_process_pool_executor, mock_parse_args, m, expected, capsys):
+    """
+    Vérifie que la sous-commande `isfib` indique l'indice du nombre donné, ou son absence.
+    """
+    mock_parse_args.return_value = make_args(is_fib=m)
+
+    await main_async()
+
+    assert capsys.readouterr().out == expected + "\n"
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_is_fib_json(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que la réponse de `isfib` est un objet JSON avec --json.
+    """
+    mock_parse_args.return_value = make_args(is_fib=-8, json=True)
+
+    await main_async()
+
+    assert json.loads(capsys.readouterr().out) == {"fibonacci": True, "indices": [-6]}
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_stdin_json(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que la sous-commande `request` calcule F(n) pour la requête lue
+    sur l'entrée standard et émet le résultat en JSON.
+    """
+    mock_parse_args.return_value = make_args(stdin_json=True)
+
+    with patch("sys.stdin", io.StringIO('{"n": 100, "algo": "matrix"}')):
+        await main_async()
+
+    document = json.loads(capsys.readouterr().out)
+    assert document["n"] == 100 and document["value"] == "354224848179261915075"
+    assert [r["algorithm"] for r in document["results"]] == ["matrix"]
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_stdin_json_malformed(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'une requête JSON malformée est signalée avec le code de sortie 1.
+    """
+    mock_parse_args.return_value = make_args(stdin_json=True)
+
+    with patch("sys.stdin", io.StringIO('{"n": ')), pytest.raises(SystemExit) as exc_info:
+        await main_async()
+
+    assert exc_info.value.code == 1
+    captured = capsys.readouterr()
+    assert "ERREUR: Requête JSON malformée" in captured.err and captured.out == ""
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("json_output", [False, True])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_algo_info(mock_process_pool_executor, mock_parse_args, json_output, capsys):
+    """
+    Vérifie que la sous-commande `algos` décrit chaque algorithme enregistré,
+    en texte ou en JSON, sans lancer de calcul.
+    """
+    mock_parse_args.return_value = make_args(algo_info=True, json=json_output)
+
+    await main_async()
+
+    out = capsys.readouterr().out
+    if json_output:
+        algorithms = json.loads(out)["algorithms"]
+        assert [info["name"] for info in algorithms] == list(ALGORITHM_REGISTRY)
+        assert algorithms[0]["complexity"] == "O(n) additions de grands entiers"
+    else:
+        assert "fast (Fast Doubling)" in out
+        assert "Complexité : O(log n) produits de matrices 2x2" in out
+        assert "Multiplications parallèles : non, calcul d'un bloc dans un thread" in out
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("m, n, g", [(12, 18, 6), (-12, 18, 6), (100, 75, 25), (0, 7, 7), (0, 0, 0)])
+async def test_run_gcd_verifies_identity(m, n, g, capsys):
+    """
+    Vérifie que pgcd(F(m), F(n)) concorde avec F(pgcd(m, n)), indices
+    négatifs et nuls compris.
+    """
+    context = CalculationContext(threshold=10000)
+    assert await _run_gcd(context, m, n, "fast", timeout=1) is True
+
+    out = capsys.readouterr().out
+    assert f"F(pgcd({m}, {n})) = F({g}) = {fib_iterative(g)}" in out
+    assert "l'identité est vérifiée" in out
+
+
+@pytest.mark.asyncio
+async def test_run_gcd_detects_wrong_result(capsys):
+    """
+    Vérifie qu'un algorithme erroné à l'un des indices met l'identité en défaut.
+    """
+    def wrong(n):
+        return fib_iterative(n) + (1 if n == 18 else 0)
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"wrong": wrong}):
+        verified = await _run_gcd(CalculationContext(threshold=10000), 12, 18, "wrong", timeout=1)
+
+    assert verified is False
+    assert "pgcd(F(12), F(18)) diffère de F(6)" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_gcd_json(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que la sous-commande `gcd` émet un objet JSON avec --json.
+    """
+    mock_parse_args.return_value = make_args(gcd=(12, 18), json=True)
+
+    await main_async()
+
+    assert json.loads(capsys.readouterr().out) == {
+        "m": 12, "n": 18, "gcd_index": 6, "base": 10, "direct": "8", "identity": "8", "verified": True,
+    }
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("workers", [None, 4])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_workers_bounds_process_pool(mock_process_pool_executor, mock_parse_args, workers, capsys):
+    """
+    Vérifie que --workers fixe la taille du pool de processus (un par cœur par défaut).
+    """
+    mock_parse_args.return_value = make_args(n=10, algo="fast", workers=workers)
+
+    await main_async()
+
+    mock_process_pool_executor.assert_called_once_with(max_workers=workers)
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("use_lookup_table", [True, False])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_no_lut_reaches_algorithm(mock_process_pool_executor, mock_parse_args, use_lookup_table, capsys):
+    """
+    Vérifie que --no-lut est transmis à l'algorithme par le contexte de calcul.
+    """
+    mock_parse_args.return_value = make_args(n=10, algo="lucas", use_lookup_table=use_lookup_table)
+    algorithm = AsyncMock(return_value=123)
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"lucas": algorithm}):
+        await main_async()
+
+    assert algorithm.await_args.args[0].use_lookup_table is use_lookup_table
+    assert "123" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("runner, overrides", [
+    ("_run_range", {"range": (1, 3)}),
+    ("_run_batch", {"batch": True}),
+    ("_run_gcd", {"gcd": (4, 6)}),
+    ("_run_digit_range", {"n": 100, "digit_range": (1, 5)}),
+])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_no_lut_reaches_modes(mock_process_pool_executor, mock_parse_args, runner, overrides):
+    """
+    Vérifie que --no-lut atteint aussi les contextes des modes plage, batch,
+    PGCD et extraction de chiffres.
+    """
+    mock_parse_args.return_value = make_args(algo="fast", use_lookup_table=False, **overrides)
+
+    with patch(f"pyfibonacci.app.{runner}", AsyncMock(return_value=True)) as mode:
+        await main_async()
+
+    assert mode.await_args.args[0].use_lookup_table is False
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_selfcheck_detects_corrupted_result(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --selfcheck signale un résultat corrompu et termine avec le code de désaccord.
+    """
+    mock_parse_args.return_value = make_args(n=100, algo="fast", selfcheck=True)
+
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"fast": AsyncMock(return_value=354224848179261915075 ^ 1 << 40)}):
+        with pytest.raises(SystemExit) as e:
+            await main_async()
+
+    assert e.value.code == EXPECT_MISMATCH_EXIT_CODE
+    assert "auto-vérification a échoué pour 'fast'" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_selfcheck_accepts_exact_result(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --selfcheck confirme un résultat exact.
+    """
+    mock_parse_args.return_value = make_args(n=-100, algo="fast", selfcheck=True)
+
+    await main_async()
+
+    assert "l'identité de Cassini est vérifiée" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_profile_mem_with_details(mock_process_pool_executor, mock_parse_args, tmp_path, capsys):
+    """
+    Vérifie que --profile écrit le profil du calcul, y compris lorsque -d
+    mesure la mémoire avec le même `tracemalloc`.
+    """
+    path = tmp_path / "calcul.tracemalloc"
+    mock_parse_args.return_value = make_args(
+        n=1000, details=True, profile="mem", profile_out=str(path)
+    )
+
+    await main_async()
+
+    captured = capsys.readouterr()
+    assert "Mémoire : pic de" in captured.out
+    assert f"Profil 'mem' écrit dans {path}." in captured.err
+    assert tracemalloc.Snapshot.load(str(path)).statistics("filename")
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_hash(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --hash affiche l'empreinte du résultat, même sans la valeur.
+    """
+    mock_parse_args.return_value = make_args(n=1000, hash="sha256", compare_only=True)
+
+    await main_async()
+
+    assert f"Empreinte sha256 : {hash_value(fib_iterative(1000))}" in capsys.readouterr().out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_sum(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --sum affiche F(1) + ... + F(n), déduit de F(n+2), et le
+    confirme terme à terme avec --selfcheck.
+    """
+    # F(1) + ... + F(10) = F(12) - 1 = 143.
+    mock_parse_args.return_value = make_args(n=10, sum=True, selfcheck=True)
+
+    await main_async()
+
+    out = capsys.readouterr().out
+    assert "F(1) + ... + F(10) = F(12) - 1" in out
+    assert "Résultat (fast): 143" in out
+    assert "la somme terme à terme concorde" in out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_sum_selfcheck_detects_mismatch(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie qu'une somme terme à terme différente termine avec le code de désaccord.
+    """
+    mock_parse_args.return_value = make_args(n=10, sum=True, selfcheck=True)
+
+    with patch("pyfibonacci.app.fib_sum_iterative", return_value=144):
+        with pytest.raises(SystemExit) as e:
+            await main_async()
+
+    assert e.value.code == EXPECT_MISMATCH_EXIT_CODE
+    assert "diffère de F(12) - 1" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_sum_rejects_negative_n(mock_process_pool_executor, mock_parse_args, capsys):
+    """
+    Vérifie que --sum refuse un nombre de termes négatif.
+    """
+    mock_parse_args.return_value = make_args(n=-5, sum=True)
+
+    with pytest.raises(SystemExit) as e:
+        await main_async()
+
+    assert e.value.code == 1
+    assert "positif ou nul" in capsys.readouterr().err
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("start, stop", [(0, 12), (-8, 3), (-6, -2), (100, 102)])
+async def test_run_range_matches_individual_terms(start, stop, capsys):
+    """
+    Vérifie que chaque terme de l'intervalle, obtenu par additions, est
+    identique au terme calculé individuellement (négatifs compris).
+    """
+    context = CalculationContext(threshold=10000)
+    await _run_range(context, start, stop)
+
+    lines = capsys.readouterr().out.splitlines()
+    expected = [
+        f"{n}\t{apply_negafibonacci_sign(n, fib_iterative(abs(n)))}"
+        for n in range(start, stop + 1)
+    ]
+    assert lines == expected
+
+
+@pytest.mark.asyncio
+async def test_run_range_json_base(capsys):
+    """
+    Vérifie l'émission d'un objet JSON par terme dans la base demandée.
+    """
+    await _run_range(CalculationContext(threshold=10000), 10, 11, DisplayOptions(json=True, base=16))
+
+    documents = [json.loads(line) for line in capsys.readouterr().out.splitlines()]
+    assert documents == [{"n": 10, "base": 16, "value": "37"}, {"n": 11, "base": 16, "value": "59"}]
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("algo", ["fast", "all"])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_quiet_prints_only_value(mock_process_pool_executor, mock_parse_args, algo, capsys):
+    """
+    Vérifie qu'en mode silencieux la sortie standard ne contient que la valeur,
+    y compris avec '-d' (pas de barre de progression).
+    """
+    mock_parse_args.return_value = make_args(n=100, algo=algo, quiet=True, details=True)
+
+    await main_async()
+
+    captured = capsys.readouterr()
+    assert captured.out == "354224848179261915075\n"
+    assert captured.err == ""
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("algo", ["fast", "fast,matrix"])
+@patch("pyfibonacci.app.parse_args")
+@patch("pyfibonacci.app.ProcessPoolExecutor")
+async def test_main_async_quiet_failure_exit_code(mock_process_pool_executor, mock_parse_args, algo, capsys):
+    """
+    Vérifie qu'en mode silencieux un calcul dont aucun algorithme n'a abouti
+    n'écrit rien sur la sortie standard et sort avec un code non nul.
+    """
+    mock_parse_args.return_value = make_args(n=100, algo=algo, quiet=True, timeout=0.05)
+
+    async def slow(context, n):
+        await asyncio.sleep(10)
+
+    registry = {name: slow for name in algo.split(",")}
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", registry):
+        with pytest.raises(SystemExit) as e:
+            await main_async()
+
+    assert e.value.code == 1
+    captured = capsys.readouterr()
+    assert captured.out == ""
+    assert "timeout" in captured.err.lower()
@@ -0,0 +1,123 @@
+"""
+Tests pour le module de benchmark de débit.
+"""
+
+import pytest
+from pyfibonacci.bench import (
+    BenchmarkResult,
+    ScalingResult,
+    benchmark_score,
+    describe_benchmark,
+    describe_scaling,
+    run_benchmark,
+    run_scaling,
+    scaling_plateau,
+    scaling_speedups,
+    scaling_worker_counts,
+)
+from pyfibonacci.cli.output import CalculationResult
+from pyfibonacci.core.algorithms import fib_iterative
+
+
+@pytest.mark.asyncio
+async def test_run_benchmark_stops_after_failure():
+    """
+    Vérifie que chaque indice est mesuré dans l'ordre et que les indices
+    suivant un échec ne sont pas lancés.
+    """
+    calls = []
+
+    async def execute(n, algo_name):
+        calls.append((n, algo_name))
+        if n > 100:
+            return CalculationResult(algo_name, None, 1.0, error="timeout (1.0s)")
+        return CalculationResult(algo_name, fib_iterative(n), 0.5)
+
+    results = await run_benchmark(execute, "fast", sizes=(10, 100, 1000, 10000))
+
+    assert calls == [(10, "fast"), (100, "fast"), (1000, "fast")]
+    assert [r.bit_length for r in results] == [6, 69, 0]
+    assert results[2].error == "timeout (1.0s)"
+
+
+def test_benchmark_result_rates():
+    """Vérifie les débits, déduits de la taille du résultat et de la durée."""
+    result = BenchmarkResult(100, 0.5, fib_iterative(100).bit_length())
+
+    assert result.digits == len(str(fib_iterative(100)))
+    assert result.digits_per_second == 42
+    assert result.bits_per_second == 138
+
+
+def test_benchmark_score():
+    """Vérifie que le score est la moyenne géométrique des débits réussis."""
+    results = [
+        BenchmarkResult(1, 1.0, 1),
+        BenchmarkResult(2, 1e-6, 1),
+        BenchmarkResult(3, 1.0, error="timeout"),
+    ]
+    assert benchmark_score(results) == pytest.approx(1e-3)
+    assert benchmark_score(results[2:]) is None
+
+
+def test_describe_benchmark():
+    """Vérifie que le rapport contient le tableau, l'échec et le score."""
+    report = describe_benchmark(
+        "fast",
+        [BenchmarkResult(1_000_000, 0.25, 694_241), BenchmarkResult(10_000_000, 10.0, error="timeout (10.0s)")],
+    )
+
+    assert "algorithme 'fast'" in report
+    assert "|   1 000 000 |     208 988 |   250.00 ms |  8.360e+05 |  2.777e+06 |" in report
+    assert "|  10 000 000 | ÉCHEC : timeout (10.0s)" in report
+    assert "Score : 0.84 (millions de chiffres par seconde, sur 1 indices)" in report
+
+
+@pytest.mark.parametrize("max_workers, expected", [(1, [1]), (4, [1, 2, 4]), (6, [1, 2, 4, 6])])
+def test_scaling_worker_counts(max_workers, expected):
+    """Vérifie que les tailles de pool sont les puissances de 2, puis le maximum."""
+    assert scaling_worker_counts(max_workers) == expected
+
+
+@pytest.mark.asyncio
+async def test_run_scaling_stops_after_failure():
+    """Vérifie que les tailles suivant un échec ne sont pas mesurées."""
+    calls = []
+
+    async def execute(workers):
+        calls.append(workers)
+        if workers > 2:
+            return CalculationResult("fast", None, 3.0, error="timeout (3.0s)")
+        return CalculationResult("fast", 1, 1.0 / workers)
+
+    results = await run_scaling(execute, [1, 2, 4, 8])
+
+    assert calls == [1, 2, 4]
+    assert results == [ScalingResult(1, 1.0), ScalingResult(2, 0.5), ScalingResult(4, 3.0, "timeout (3.0s)")]
+    assert scaling_speedups(results) == [1.0, 2.0]
+
+
+@pytest.mark.parametrize("durations, expected", [
+    ((8.0, 4.0, 2.0, 1.0), None),
+    ((8.0, 4.0, 3.8, 3.9), 2),
+    ((8.0, 8.5), 1),
+])
+def test_scaling_plateau(durations, expected):
+    """Vérifie que le plafond est la dernière taille dont l'accélération progresse."""
+    results = [ScalingResult(2**k, duration) for k, duration in enumerate(durations)]
+    assert scaling_plateau(results) == expected
+
+
+def test_describe_scaling():
+    """Vérifie que le rapport contient l'accélération, l'efficacité et le plafond."""
+    report = describe_scaling(
+        10_000_000,
+        "fast",
+        [ScalingResult(1, 4.0), ScalingResult(2, 2.5), ScalingResult(4, 2.4)],
+        mul_backend="native",
+    )
+
+    assert "Passage à l'échelle de F(10 000 000), algorithme 'fast'" in report
+    assert "multiplication : native" in report
+    assert "|         2 |     2.500 s |        1.60x |       80%  |" in report
+    assert "Plafond : au-delà de 2 processus" in report
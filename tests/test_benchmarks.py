@@ -6,6 +6,7 @@ import pytest
 import asyncio
 from pyfibonacci.core.algorithms import fib_iterative, fib_matrix, fib_fast_doubling
 from pyfibonacci.core.context import CalculationContext
+from pyfibonacci.core.multiplication import multiply, square
 
 # Valeur de N pour les benchmarks. Assez grande pour être significative,
 # mais assez petite pour ne pas prendre trop de temps.
@@ -32,3 +33,47 @@ def test_benchmark_fast_doubling(benchmark):
         return asyncio.run(fib_fast_doubling(context, BENCHMARK_N))
 
     benchmark(f)
+
+# Tailles (en bits) des opérandes pour la comparaison entre `square` et
+# `multiply`. Le gain du carré dédié croît avec la taille des opérandes.
+SQUARING_BITS = [10_000, 100_000, 1_000_000]
+
+@pytest.mark.parametrize("bits", SQUARING_BITS)
+def test_benchmark_square(benchmark, bits):
+    """Benchmark de `square`, qui exploite la symétrie du carré."""
+    context = CalculationContext(threshold=10**9, mul_algo="native")
+    x = (1 << bits) - 1
+
+    def f():
+        return asyncio.run(square(context, x))
+
+    benchmark(f)
+
+@pytest.mark.parametrize("bits", SQUARING_BITS)
+def test_benchmark_multiply_same_value(benchmark, bits):
+    """Benchmark de `multiply` sur deux copies distinctes d'une même valeur."""
+    context = CalculationContext(threshold=10**9, mul_algo="native")
+    x = (1 << bits) - 1
+    y = x ^ 0  # Même valeur, objet distinct : la voie dédiée aux carrés est évitée.
+
+    def f():
+        return asyncio.run(multiply(context, x, y))
+
+    benchmark(f)
+
+# Tailles (en bits) pour situer le point de bascule entre l'arithmétique
+# native et GMP (voir `GMP_THRESHOLD_BITS`).
+GMP_BITS = [1_000, 10_000, 100_000, 1_000_000]
+
+@pytest.mark.parametrize("bits", GMP_BITS)
+def test_benchmark_gmp_native(benchmark, bits):
+    """Benchmark du produit natif de CPython, référence pour GMP."""
+    x, y = (1 << bits) - 1, (1 << bits) - 3
+    benchmark(lambda: x * y)
+
+@pytest.mark.parametrize("bits", GMP_BITS)
+def test_benchmark_gmp_mpz(benchmark, bits):
+    """Benchmark du produit GMP, conversions vers et depuis `mpz` comprises."""
+    gmpy2 = pytest.importorskip("gmpy2")
+    x, y = (1 << bits) - 1, (1 << bits) - 3
+    benchmark(lambda: int(gmpy2.mpz(x) * gmpy2.mpz(y)))
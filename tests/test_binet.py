@@ -0,0 +1,84 @@
+"""
+Tests pour la vérification des résultats par la formule de Binet.
+"""
+import pytest
+from decimal import Decimal
+from pyfibonacci.core.algorithms import apply_negafibonacci_sign, fib_iterative
+from pyfibonacci.core.binet import fibonacci_ratio, golden_ratio, leading_digits, verify_leading_digits
+
+
+@pytest.mark.parametrize("n", list(range(-40, 120)) + [1000, 5000])
+def test_verify_leading_digits_accepts_exact_values(n):
+    """Vérifie que les valeurs exactes, signe compris, sont acceptées."""
+    verify_leading_digits(apply_negafibonacci_sign(n, fib_iterative(abs(n))), n)
+
+
+def test_verify_leading_digits_rejects_small_error():
+    """Vérifie qu'une erreur est détectée sur un petit nombre, comparé exactement."""
+    with pytest.raises(ValueError):
+        verify_leading_digits(56, 10)
+
+
+def test_verify_leading_digits_rejects_leading_digit_error():
+    """Vérifie qu'une erreur dans les premiers chiffres est détectée."""
+    value = fib_iterative(5000)
+    corrupted = value + 10 ** (len(str(value)) - 10)
+    with pytest.raises(ValueError, match="premiers chiffres"):
+        verify_leading_digits(corrupted, 5000)
+
+
+@pytest.mark.parametrize("factor", [10, -1])
+def test_verify_leading_digits_rejects_wrong_length_or_sign(factor):
+    """Vérifie qu'un nombre de chiffres ou un signe erroné est détecté."""
+    with pytest.raises(ValueError):
+        verify_leading_digits(fib_iterative(5000) * factor, 5000)
+
+
+def test_verify_leading_digits_zero():
+    """Vérifie le cas F(0) = 0."""
+    verify_leading_digits(0, 0)
+    with pytest.raises(ValueError):
+        verify_leading_digits(1, 0)
+
+
+@pytest.mark.parametrize("n", [0, 1, 7, -8, 100, 1000, 5000])
+@pytest.mark.parametrize("count", [1, 5, 30])
+def test_leading_digits_matches_exact_values(n, count):
+    """Vérifie que les premiers chiffres et la taille prédits sont exacts."""
+    text = str(fib_iterative(abs(n)))
+    assert leading_digits(n, count) == (text[:count], len(text))
+
+
+def test_leading_digits_huge_index():
+    """Vérifie que la tête de F(10^9) s'obtient sans calculer le nombre complet."""
+    assert leading_digits(10**9, 20) == ("79523178745546834678", 208987640)
+
+
+def test_leading_digits_rejects_non_positive_count():
+    """Vérifie qu'un nombre de chiffres nul est refusé."""
+    with pytest.raises(ValueError):
+        leading_digits(10, 0)
+
+
+def test_golden_ratio():
+    """Vérifie les premiers chiffres de φ et la précision demandée."""
+    assert str(golden_ratio(20)) == "1.6180339887498948482"
+    assert len(str(golden_ratio(500))) == 501
+
+
+@pytest.mark.parametrize("n, digits, expected", [(2, 5, "1"), (3, 5, "2"), (6, 5, "1.6"), (11, 5, "1.6182")])
+def test_fibonacci_ratio_small_terms(n, digits, expected):
+    """Vérifie le rapport de petits termes consécutifs."""
+    assert fibonacci_ratio(fib_iterative(n), fib_iterative(n - 1), digits) == Decimal(expected)
+
+
+def test_fibonacci_ratio_large_terms_converges_to_golden_ratio():
+    """Vérifie que le rapport de grands termes, tronqués en tête, rejoint φ."""
+    ratio = fibonacci_ratio(fib_iterative(5000), fib_iterative(4999), 200)
+    assert abs(ratio - golden_ratio(200)) <= Decimal("1E-198")
+
+
+def test_fibonacci_ratio_rejects_zero_denominator():
+    """Vérifie que F(1)/F(0) est refusé."""
+    with pytest.raises(ZeroDivisionError):
+        fibonacci_ratio(1, 0, 10)
@@ -1,9 +1,14 @@
 """
 Tests pour le module de calibration.
 """
+import asyncio
+import json
 from unittest.mock import AsyncMock, MagicMock, patch
 import pytest
-from pyfibonacci.calibrate import (_measure_standard_multiply, _measure_parallel_multiply, run_calibration)
+from pyfibonacci.calibrate import (
+    CALIBRATION_SIZES, CalibrationPoint, _measure_standard_multiply, _measure_parallel_multiply,
+    run_calibration, write_calibration_points,
+)
 
 @pytest.mark.asyncio
 @patch("time.perf_counter", side_effect=[1.0, 2.5])
@@ -15,6 +20,17 @@ async def test_measure_standard_multiply(mock_perf_counter):
     assert duration == 1.5
 
 
+@pytest.mark.asyncio
+async def test_measure_standard_multiply_uses_product():
+    """
+    Vérifie que la multiplication standard passe par le même aiguillage
+    (natif ou GMP) que la multiplication parallélisée.
+    """
+    with patch("pyfibonacci.calibrate._product", return_value=0) as product:
+        await _measure_standard_multiply(size_in_bits=100, backend="native")
+    product.assert_called_once_with((1 << 100) - 1, (1 << 100) - 1, "native")
+
+
 @pytest.mark.asyncio
 @patch("asyncio.get_running_loop")
 @patch("time.perf_counter", side_effect=[1.0, 3.0])
@@ -33,6 +49,21 @@ async def test_measure_parallel_multiply(mock_perf_counter, mock_get_loop):
     mock_get_loop.return_value.run_in_executor.assert_called_once()
 
 
+@pytest.mark.asyncio
+@patch("pyfibonacci.calibrate._measure_standard_multiply", new_callable=AsyncMock, return_value=0.5)
+@patch("pyfibonacci.calibrate._measure_parallel_multiply", new_callable=AsyncMock, return_value=0.4)
+async def test_run_calibration_mul_backend(mock_measure_parallel, mock_measure_standard, capsys):
+    """
+    Vérifie que les deux multiplications mesurées utilisent le moteur demandé.
+    """
+    mock_executor = MagicMock()
+
+    await run_calibration(mock_executor, mul_backend="native")
+
+    mock_measure_standard.assert_called_with(CALIBRATION_SIZES[0], "native")
+    mock_measure_parallel.assert_called_with(mock_executor, CALIBRATION_SIZES[0], "native")
+
+
 @pytest.mark.asyncio
 @patch("pyfibonacci.calibrate._measure_standard_multiply", new_callable=AsyncMock)
 @patch("pyfibonacci.calibrate._measure_parallel_multiply", new_callable=AsyncMock)
@@ -49,8 +80,10 @@ async def test_run_calibration_finds_crossover(mock_measure_parallel, mock_measu
     mock_measure_standard.side_effect = [0.1, 0.1, 0.1, 0.5, 0.5, 0.5]  # 3 passes par taille
     mock_measure_parallel.side_effect = [0.8, 0.8, 0.8, 0.4, 0.4, 0.4]
 
-    await run_calibration(mock_executor)
+    threshold = await run_calibration(mock_executor)
 
+    # Le seuil est retourné en chiffres décimaux.
+    assert threshold == int(20000 / 3.3219)
     captured = capsys.readouterr()
     # On vérifie que la sortie indique le bon seuil.
     assert "Seuil optimal approximatif trouvé autour de 20000 bits" in captured.out
@@ -67,7 +100,101 @@ async def test_run_calibration_no_crossover(capsys):
     n'est trouvé dans la plage testée.
     """
     mock_executor = MagicMock()
-    await run_calibration(mock_executor)
+    assert await run_calibration(mock_executor) is None
 
     captured = capsys.readouterr()
     assert "Aucun seuil optimal trouvé" in captured.out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.calibrate._measure_standard_multiply", AsyncMock(return_value=0.5))
+async def test_run_calibration_skips_slow_size(capsys):
+    """
+    Vérifie qu'une taille dont les mesures dépassent le délai est ignorée,
+    sans bloquer la calibration des tailles suivantes.
+    """
+    async def measure_parallel(executor, size_in_bits, backend):
+        if size_in_bits == 10000:
+            await asyncio.sleep(10)
+        return 0.1
+
+    with patch("pyfibonacci.calibrate._measure_parallel_multiply", measure_parallel):
+        threshold = await run_calibration(MagicMock(), size_timeout=0.05)
+
+    assert threshold == int(20000 / 3.3219)
+    captured = capsys.readouterr()
+    assert "| 10000         | DÉLAI DÉPASSÉ, taille ignorée" in captured.out
+    assert "Tailles ignorées (délai de 0.05 s dépassé) : 10000 bits." in captured.out
+    assert "Échec" not in captured.out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.calibrate._measure_standard_multiply", AsyncMock(return_value=0.1))
+async def test_run_calibration_reports_failed_size_apart_from_skipped(capsys):
+    """
+    Vérifie qu'une taille dont les mesures échouent est signalée comme un
+    échec, distinct d'une taille ignorée pour délai dépassé.
+    """
+    async def measure_parallel(executor, size_in_bits, backend):
+        if size_in_bits == 50000:
+            raise OSError("pool indisponible")
+        if size_in_bits == 100000:
+            await asyncio.sleep(10)
+        return 0.5
+
+    with patch("pyfibonacci.calibrate._measure_parallel_multiply", measure_parallel):
+        assert await run_calibration(MagicMock(), size_timeout=0.05) is None
+
+    out = capsys.readouterr().out
+    assert "| 50000         | ÉCHEC" in out
+    assert "Échec des mesures pour 50000 bits : OSError: pool indisponible" in out
+    assert "Tailles ignorées (délai de 0.05 s dépassé) : 100000 bits." in out
+    assert "Aucun seuil optimal trouvé" in out
+
+
+@pytest.mark.asyncio
+@patch("pyfibonacci.calibrate._measure_standard_multiply", AsyncMock(return_value=0.002))
+async def test_run_calibration_records_every_point(capsys):
+    """
+    Vérifie qu'avec une liste de points, toutes les tailles sont mesurées
+    au-delà du point de croisement, et que les tailles non mesurées y
+    figurent avec leur raison.
+    """
+    async def measure_parallel(executor, size_in_bits, backend):
+        if size_in_bits == 10000:
+            await asyncio.sleep(10)
+        if size_in_bits == 50000:
+            raise OSError("pool indisponible")
+        return 0.001 if size_in_bits >= 100000 else 0.004
+
+    points = []
+    with patch("pyfibonacci.calibrate._measure_parallel_multiply", measure_parallel):
+        threshold = await run_calibration(MagicMock(), size_timeout=0.05, points=points)
+
+    assert threshold == int(100000 / 3.3219)
+    assert [p.size_bits for p in points] == CALIBRATION_SIZES
+    assert points[0] == CalibrationPoint(10000, 3010, error="délai de 0.05 s dépassé")
+    assert points[1] == CalibrationPoint(20000, 6020, pytest.approx(2.0), pytest.approx(4.0))
+    assert points[2].error == "OSError: pool indisponible" and points[2].standard_ms is None
+    assert all(p.parallel_ms == pytest.approx(1.0) for p in points[3:])
+    assert "| 500000 " in capsys.readouterr().out
+
+
+def test_write_calibration_points(tmp_path):
+    """
+    Vérifie le document JSON des mesures : description de la machine, seuil
+    et points, y compris ceux qui n'ont pas été mesurés.
+    """
+    path = tmp_path / "calibration.json"
+    points = [CalibrationPoint(10000, 3010, 0.1, 0.8), CalibrationPoint(20000, 6020, error="ÉCHEC")]
+
+    write_calibration_points(str(path), points, None, "native")
+
+    document = json.loads(path.read_text(encoding="utf-8"))
+    assert document["machine"]["cpu_count"] >= 1 and document["machine"]["python"]
+    assert document["machine"]["mul_backend"] == "native"
+    assert (document["passes"], document["threshold"]) == (3, None)
+    assert document["points"] == [
+        {"size_bits": 10000, "threshold_digits": 3010, "standard_ms": 0.1, "parallel_ms": 0.8, "error": None},
+        {"size_bits": 20000, "threshold_digits": 6020, "standard_ms": None, "parallel_ms": None, "error": "ÉCHEC"},
+    ]
@@ -6,7 +6,9 @@ import sys
 from unittest.mock import patch
 
 import pytest
-from pyfibonacci.cli.args import parse_args
+from pyfibonacci.bench import SCALING_N
+from pyfibonacci.cli.args import DEFAULT_RATIO_DIGITS, MAX_RANGE_LENGTH, apply_json_request, parse_args
+from pyfibonacci.server import DEFAULT_MAX_N
 
 @pytest.fixture
 def setup_sys_argv():
@@ -31,6 +33,30 @@ def test_parse_args_defaults(setup_sys_argv):
         assert args.threshold == 10000
         assert not args.details
         assert not args.calibrate
+        assert args.mod is None
+        assert args.tail is None
+        assert args.ratio is None
+        assert not args.json
+        assert args.mul_algo == "auto"
+        assert args.workers is None
+        assert args.use_lookup_table
+        assert args.memory_fraction == 0.9
+        assert args.watchdog is None
+        assert args.base == 10
+        assert args.progress is None
+        assert not args.verify
+        assert not args.quiet
+        assert args.color == "auto"
+        assert args.thousands_sep == " "
+        assert args.repeat == 1
+        assert not args.estimate
+        assert args.serve is None
+        assert args.max_n == DEFAULT_MAX_N
+        assert args.cache_size == 0
+        assert args.cache_idle is None
+        assert not args.batch
+        assert args.batch_file is None
+        assert args.range is None
 
 def test_parse_args_all_options(setup_sys_argv):
     """
@@ -59,6 +85,44 @@ def test_parse_args_calibrate(setup_sys_argv):
         args = parse_args()
         assert args.calibrate
         assert args.n is None  # n n'est pas requis avec --calibrate
+        assert args.calibrate_timeout is None
+        assert not args.crossover
+
+@pytest.mark.parametrize("argv", [
+    ['pyfibonacci', '--calibrate', '--crossover'],
+    ['pyfibonacci', 'calibrate', '--crossover'],
+])
+def test_parse_args_calibrate_crossover(setup_sys_argv, argv):
+    """
+    Vérifie que la recherche des points de croisement est acceptée par les
+    deux invocations.
+    """
+    with patch.object(sys, 'argv', argv):
+        args = parse_args()
+        assert args.calibrate
+        assert args.crossover
+
+@pytest.mark.parametrize("argv", [
+    ['pyfibonacci', '--calibrate', '--calibrate-timeout', '2.5'],
+    ['pyfibonacci', 'calibrate', '--timeout', '2.5'],
+])
+def test_parse_args_calibrate_timeout(setup_sys_argv, argv):
+    """
+    Vérifie que le délai par taille de la calibration est accepté par les
+    deux invocations.
+    """
+    with patch.object(sys, 'argv', argv):
+        args = parse_args()
+        assert args.calibrate
+        assert args.calibrate_timeout == 2.5
+
+def test_parse_args_calibrate_timeout_rejects_non_positive(setup_sys_argv):
+    """
+    Vérifie qu'un délai de calibration nul est refusé.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', 'calibrate', '--timeout', '0']):
+        with pytest.raises(SystemExit):
+            parse_args()
 
 def test_parse_args_invalid_choice(setup_sys_argv):
     """
@@ -67,3 +131,475 @@ def test_parse_args_invalid_choice(setup_sys_argv):
     with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--algo', 'invalid']):
         with pytest.raises(SystemExit):
             parse_args()
+
+@pytest.mark.parametrize("value, expected", [
+    ("all", "all"),
+    ("best", "best"),
+    ("fast,matrix", "fast,matrix"),
+    ("fast, iterative,fast", "fast,iterative"),
+])
+def test_parse_args_algo_list(setup_sys_argv, value, expected):
+    """
+    Vérifie que `--algo` accepte une liste d'algorithmes, normalisée sans doublons.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--algo', value]):
+        assert parse_args().algo == expected
+
+@pytest.mark.parametrize("value", ["fast,invalid", "fast,lucas", "fast,all", "fast,best", ""])
+def test_parse_args_algo_list_invalid(setup_sys_argv, value):
+    """
+    Vérifie qu'une liste contenant un nom inconnu, 'all' ou 'lucas' est refusée.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--algo', value]):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+@pytest.mark.parametrize("value, expected", [(",", ","), (".", "."), ("space", " "), ("none", "")])
+def test_parse_args_thousands_sep(setup_sys_argv, value, expected):
+    """
+    Vérifie que `--thousands-sep` accepte un caractère de ponctuation ou un nom.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--thousands-sep', value]):
+        assert parse_args().thousands_sep == expected
+
+@pytest.mark.parametrize("value", ["x", "1", ",,"])
+def test_parse_args_thousands_sep_invalid(setup_sys_argv, value):
+    """
+    Vérifie qu'un séparateur alphanumérique ou de plusieurs caractères est refusé.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--thousands-sep', value]):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+@pytest.mark.parametrize("value", ["0", "1001", "six"])
+def test_parse_args_sci_digits_invalid(setup_sys_argv, value):
+    """
+    Vérifie qu'un nombre de chiffres significatifs absurde est refusé.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--sci-digits', value]):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+def test_parse_args_mod(setup_sys_argv):
+    """
+    Vérifie que l'option `--mod` accepte de très grands entiers.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--mod', str(2**127 - 1)]):
+        args = parse_args()
+        assert args.mod == [2**127 - 1]
+
+def test_parse_args_mod_list(setup_sys_argv):
+    """
+    Vérifie que l'option `--mod` accepte une liste de modules, sans doublons.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--mod', '7, 11,7,13']):
+        args = parse_args()
+        assert args.mod == [7, 11, 13]
+
+def test_parse_args_mod_list_rejects_invalid_item(setup_sys_argv, capsys):
+    """
+    Vérifie qu'un élément non entier de la liste est refusé et nommé.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--mod', '7,,13']):
+        with pytest.raises(SystemExit):
+            parse_args()
+    assert "module invalide : ''" in capsys.readouterr().err
+
+def test_parse_args_negative_index(setup_sys_argv):
+    """
+    Vérifie qu'un indice négatif est accepté par `-n`.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '-10']):
+        args = parse_args()
+        assert args.n == -10
+
+def test_parse_args_base(setup_sys_argv):
+    """
+    Vérifie que l'option `--base` accepte une base valide.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--base', '16']):
+        assert parse_args().base == 16
+
+@pytest.mark.parametrize("base", ["1", "37", "hex"])
+def test_parse_args_invalid_base(setup_sys_argv, base):
+    """
+    Vérifie qu'une base hors de l'intervalle [2, 36] lève une erreur.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--base', base]):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+@pytest.mark.parametrize("repeat", ["0", "-3", "deux"])
+def test_parse_args_invalid_repeat(setup_sys_argv, repeat):
+    """
+    Vérifie que le nombre d'exécutions doit être un entier strictement positif.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '10', '--repeat', repeat]):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+@pytest.mark.parametrize("workers", ["0", "-2", "quatre"])
+def test_parse_args_invalid_workers(setup_sys_argv, workers):
+    """
+    Vérifie que le nombre de processus doit être un entier strictement positif.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', 'calc', '-n', '10', '--workers', workers]):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+def test_parse_args_mul_backend(setup_sys_argv):
+    """
+    Vérifie que `--mul-backend` accepte les moteurs disponibles, 'auto' par défaut.
+    """
+    assert parse_args(['calc', '-n', '10']).mul_backend == "auto"
+    assert parse_args(['calc', '-n', '10', '--mul-backend', 'native']).mul_backend == "native"
+    with patch("pyfibonacci.cli.args.MUL_BACKEND", "gmp"):
+        assert parse_args(['bench', '--mul-backend', 'gmp']).mul_backend == "gmp"
+
+@pytest.mark.parametrize("backend, message", [
+    ("gmp", "requiert la dépendance optionnelle 'gmpy2'"),
+    ("fft", "moteur inconnu : 'fft'"),
+])
+def test_parse_args_mul_backend_unavailable(setup_sys_argv, capsys, backend, message):
+    """
+    Vérifie qu'un moteur inconnu, ou GMP sans `gmpy2`, est refusé explicitement.
+    """
+    with patch("pyfibonacci.cli.args.MUL_BACKEND", "native"):
+        with pytest.raises(SystemExit):
+            parse_args(['calc', '-n', '10', '--mul-backend', backend])
+    assert message in capsys.readouterr().err
+
+@pytest.mark.parametrize("interval", ["0", "-1", "court"])
+def test_parse_args_invalid_watchdog(setup_sys_argv, interval):
+    """
+    Vérifie que l'intervalle du chien de garde doit être une durée strictement positive.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', 'calc', '-n', '10', '--watchdog', interval]):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+def test_parse_args_range(setup_sys_argv):
+    """
+    Vérifie que l'option `--range` accepte un intervalle, y compris négatif.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', '--range=-5:10']):
+        assert parse_args().range == (-5, 10)
+
+@pytest.mark.parametrize("interval", ["10", "a:b", "10:5", f"0:{MAX_RANGE_LENGTH}"])
+def test_parse_args_invalid_range(setup_sys_argv, interval):
+    """
+    Vérifie qu'un intervalle malformé, inversé ou trop long lève une erreur.
+    """
+    with patch.object(sys, 'argv', ['pyfibonacci', f'--range={interval}']):
+        with pytest.raises(SystemExit):
+            parse_args()
+
+def test_parse_args_environment_defaults(setup_sys_argv, monkeypatch):
+    """
+    Vérifie que les variables d'environnement fournissent les valeurs par
+    défaut des options, y compris booléennes.
+    """
+    monkeypatch.setenv("PYFIBONACCI_N", "1000")
+    monkeypatch.setenv("PYFIBONACCI_ALGO", "fast,matrix")
+    monkeypatch.setenv("PYFIBONACCI_TIMEOUT", "2.5")
+    monkeypatch.setenv("PYFIBONACCI_THRESHOLD", "5000")
+    monkeypatch.setenv("PYFIBONACCI_MAX_N", "42")
+    monkeypatch.setenv("PYFIBONACCI_QUIET", "yes")
+    monkeypatch.setenv("PYFIBONACCI_MUL_ALGO", "native")
+    with patch.object(sys, 'argv', ['pyfibonacci']):
+        args = parse_args()
+    assert (args.n, args.algo, args.timeout, args.threshold) == (1000, "fast,matrix", 2.5, 5000)
+    assert (args.max_n, args.quiet, args.mul_algo) == (42, True, "native")
+
+def test_parse_args_environment_overridden_by_flags(setup_sys_argv, isolated_config, monkeypatch):
+    """
+    Vérifie que les options explicites l'emportent sur l'environnement, qui
+    l'emporte lui-même sur le fichier de configuration.
+    """
+    isolated_config.write_text('{"threshold": 1234}', encoding="utf-8")
+    monkeypatch.setenv("PYFIBONACCI_THRESHOLD", "5000")
+    monkeypatch.setenv("PYFIBONACCI_N", "1000")
+    monkeypatch.setenv("PYFIBONACCI_VERIFY", "1")
+    with patch.object(sys, 'argv', ['pyfibonacci']):
+        assert parse_args().threshold == 5000
+    with patch.object(sys, 'argv', ['pyfibonacci', '-n', '7', '--threshold', '10']):
+        args = parse_args()
+    assert (args.n, args.threshold, args.verify) == (7, 10, True)
+
+def test_parse_args_environment_mode_ignored_by_subcommand(setup_sys_argv, monkeypatch):
+    """
+    Vérifie qu'une variable d'environnement choisissant un mode ne l'emporte
+    pas sur la sous-commande explicite, les autres options restant héritées.
+    """
+    monkeypatch.setenv("PYFIBONACCI_RANGE", "1:3")
+    monkeypatch.setenv("PYFIBONACCI_DECODE", "f")
+    monkeypatch.setenv("PYFIBONACCI_BENCH", "1")
+    monkeypatch.setenv("PYFIBONACCI_THRESHOLD", "5000")
+    args = parse_args(["calc", "-n", "5"])
+    assert (args.range, args.decode, args.bench) == (None, None, False)
+    assert (args.n, args.threshold) == (5, 5000)
+
+def test_parse_args_environment_mode_ignored_without_subcommand(setup_sys_argv, monkeypatch):
+    """
+    Vérifie que l'invocation historique ignore aussi les variables
+    d'environnement qui choisiraient un mode.
+    """
+    monkeypatch.setenv("PYFIBONACCI_SERVE", ":0")
+    monkeypatch.setenv("PYFIBONACCI_BENCH", "1")
+    monkeypatch.setenv("PYFIBONACCI_CALIBRATE", "1")
+    args = parse_args(["-n", "10", "-q"])
+    assert (args.serve, args.bench, args.calibrate) == (None, False, False)
+    assert args.n == 10
+
+@pytest.mark.parametrize("name, value, message", [
+    ("PYFIBONACCI_N", "mille", "PYFIBONACCI_N : valeur invalide : 'mille'"),
+    ("PYFIBONACCI_BASE", "40", "PYFIBONACCI_BASE : la base doit être comprise entre 2 et 36"),
+    ("PYFIBONACCI_MUL_ALGO", "fft", "PYFIBONACCI_MUL_ALGO : 'fft' n'est pas un choix valide"),
+    ("PYFIBONACCI_JSON", "peut-être", "PYFIBONACCI_JSON : booléen attendu"),
+])
+def test_parse_args_environment_invalid(setup_sys_argv, monkeypatch, capsys, name, value, message):
+    """
+    Vérifie qu'une variable d'environnement malformée est signalée clairement,
+    avec la même validation que l'option correspondante.
+    """
+    monkeypatch.setenv(name, value)
+    with patch.object(sys, 'argv', ['pyfibonacci']):
+        with pytest.raises(SystemExit):
+            parse_args()
+    assert message in capsys.readouterr().err
+
+def test_parse_args_environment_invalid_other_subcommand(setup_sys_argv, monkeypatch, capsys):
+    """
+    Vérifie qu'une variable invalide n'empêche pas une sous-commande qui ne
+    déclare pas l'option, et qu'elle est signalée par celle qui la déclare.
+    """
+    monkeypatch.setenv("PYFIBONACCI_MAX_N", "abc")
+    args = parse_args(["calc", "-n", "10"])
+    assert (args.n, args.max_n) == (10, DEFAULT_MAX_N)
+
+    with pytest.raises(SystemExit):
+        parse_args(["serve", ":0"])
+    err = capsys.readouterr().err
+    assert "PYFIBONACCI_MAX_N : valeur invalide : 'abc'" in err
+    assert "serve" in err.splitlines()[0]
+
+@pytest.mark.parametrize("argv", [["calc", "-n", "5"], ["-n", "5"]])
+def test_parse_args_environment_invalid_overridden_by_flag(setup_sys_argv, monkeypatch, argv):
+    """
+    Vérifie qu'une variable d'environnement malformée est ignorée lorsque
+    l'option correspondante est donnée explicitement.
+    """
+    monkeypatch.setenv("PYFIBONACCI_N", "abc")
+    monkeypatch.setenv("PYFIBONACCI_JSON", "peut-être")
+    assert parse_args(argv + ["--json"]).n == 5
+
+@pytest.mark.parametrize("argv, expected", [
+    (["calc", "-n", "100", "--algo", "matrix"], {"command": "calc", "n": 100, "algo": "matrix"}),
+    (["serve", ":8080", "--cache-size", "5"], {"command": "serve", "serve": ":8080", "cache_size": 5}),
+    (["bench", "--timeout", "600"], {"command": "bench", "bench": True, "timeout": 600.0}),
+    (["calibrate", "--save"], {"command": "calibrate", "calibrate": True, "calibrate_save": True}),
+    (["calibrate", "--points", "c.json"], {"command": "calibrate", "calibrate_json": "c.json"}),
+    (["batch", "indices.txt"], {"command": "batch", "batch": True, "batch_file": "indices.txt"}),
+    (["batch"], {"command": "batch", "batch": True, "batch_file": None}),
+    (["range", "--", "-5:3"], {"command": "range", "range": (-5, 3)}),
+    (["decode", "f.bin", "--json"], {"command": "decode", "decode": "f.bin", "json": True}),
+    (["isfib", "--", "-8"], {"command": "isfib", "is_fib": -8}),
+    (["gcd", "12:18", "--algo", "matrix"], {"command": "gcd", "gcd": (12, 18), "algo": "matrix"}),
+    (["algos", "--json"], {"command": "algos", "algo_info": True, "json": True}),
+    (["request", "--timeout", "5"], {"command": "request", "stdin_json": True, "timeout": 5.0}),
+])
+def test_parse_args_subcommands(capsys, argv, expected):
+    """
+    Vérifie que chaque sous-commande renseigne ses options et son mode, les
+    autres options valant leur défaut, sans avertissement de dépréciation.
+    """
+    args = parse_args(argv)
+    assert {key: getattr(args, key) for key in expected} == expected
+    assert not args.details and args.mod is None
+    assert capsys.readouterr().err == ""
+
+@pytest.mark.parametrize("argv", [
+    ["bench", "-n", "10"],
+    ["serve", ":8080", "--json"],
+    ["calc", "-n", "10", "--serve", ":8080"],
+    ["calibrate", "--threshold", "10"],
+])
+def test_parse_args_subcommand_rejects_foreign_options(argv):
+    """
+    Vérifie qu'une sous-commande refuse les options qui ne la concernent pas.
+    """
+    with pytest.raises(SystemExit):
+        parse_args(argv)
+
+def test_parse_args_subcommand_environment_defaults(isolated_config, monkeypatch):
+    """
+    Vérifie que la configuration et l'environnement s'appliquent aussi aux
+    options des sous-commandes.
+    """
+    isolated_config.write_text('{"threshold": 1234}', encoding="utf-8")
+    monkeypatch.setenv("PYFIBONACCI_TIMEOUT", "2.5")
+    args = parse_args(["bench"])
+    assert (args.threshold, args.timeout) == (1234, 2.5)
+
+def test_parse_args_legacy_invocation_is_deprecated(capsys):
+    """
+    Vérifie que l'invocation sans sous-commande reste acceptée, avec un
+    avertissement sur la sortie d'erreur.
+    """
+    args = parse_args(["-n", "10", "--bench"])
+    assert (args.command, args.n, args.bench) == (None, 10, True)
+
+    assert "AVERTISSEMENT: L'invocation sans sous-commande est dépréciée" in capsys.readouterr().err
+
+def test_parse_args_truncation():
+    """Vérifie les valeurs par défaut et la validation des options de troncature."""
+    args = parse_args(['calc', '-n', '10'])
+    assert (args.trunc_limit, args.trunc_edges) == (None, 25)
+    args = parse_args(['calc', '-n', '10', '--trunc-limit', '120', '--trunc-edges', '50'])
+    assert (args.trunc_limit, args.trunc_edges) == (120, 50)
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', '10', '--trunc-edges', '0'])
+
+
+def test_parse_args_reference_cache():
+    """Vérifie les options du cache des références."""
+    args = parse_args(['calc', '-n', '10'])
+    assert (args.cache_reference, args.compare_cached, args.reference_dir) == (False, False, None)
+    args = parse_args(['calc', '-n', '10', '--compare-cached', '--reference-dir', 'refs'])
+    assert (args.compare_cached, args.reference_dir) == (True, 'refs')
+
+def test_parse_args_bench_scaling():
+    """Vérifie l'indice par défaut de --scaling et son absence hors benchmark."""
+    assert parse_args(["bench"]).scaling is None
+    assert parse_args(["bench", "--scaling"]).scaling == SCALING_N
+    assert parse_args(["bench", "--scaling", "1000000"]).scaling == 1_000_000
+    assert parse_args(["--bench", "--scaling", "5000"]).scaling == 5000
+    assert parse_args(["-n", "10"]).scaling is None
+
+@pytest.mark.parametrize("value, expected", [("1000", 1000), ("-7", -7), ("2^30", 2**30), ("10^8+1", 10**8 + 1)])
+def test_parse_args_n_expression(value, expected):
+    """
+    Vérifie que -n accepte un nombre ou une expression arithmétique.
+    """
+    assert parse_args(['calc', '-n', value]).n == expected
+
+
+@pytest.mark.parametrize("value, message", [
+    ("2^64", "valeur invalide : '2^64' (dépassement : la valeur excède 2^64 - 1)"),
+    ("2^(3", "valeur invalide : '2^(3' (parenthèse fermante manquante en position 5)"),
+])
+def test_parse_args_n_expression_invalid(capsys, value, message):
+    """
+    Vérifie qu'une expression invalide ou hors de 64 bits est refusée avec un
+    message explicite.
+    """
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', value])
+    assert message in capsys.readouterr().err
+
+
+@pytest.mark.parametrize("argv, expected", [
+    (['calc', '-n', '100', '--ratio'], DEFAULT_RATIO_DIGITS),
+    (['calc', '-n', '100', '--ratio', '80'], 80),
+])
+def test_parse_args_ratio(argv, expected):
+    """
+    Vérifie que --ratio accepte une précision facultative.
+    """
+    assert parse_args(argv).ratio == expected
+
+def test_parse_args_sequential():
+    """
+    Vérifie que --sequential est désactivée par défaut.
+    """
+    assert not parse_args(['calc', '-n', '100']).sequential
+    assert parse_args(['calc', '-n', '100', '--algo', 'all', '--sequential']).sequential
+
+
+def test_parse_args_scaled():
+    """
+    Vérifie que --scaled exige un nombre de chiffres et exclut les autres modes.
+    """
+    assert parse_args(['calc', '-n', '100']).scaled is None
+    assert parse_args(['calc', '-n', '100', '--scaled', '8']).scaled == 8
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', '100', '--scaled', '0'])
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', '100', '--scaled', '8', '--tail', '3'])
+
+
+def test_parse_args_ratio_excludes_edges():
+    """
+    Vérifie que --ratio et --edges sont mutuellement exclusives.
+    """
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', '100', '--ratio', '--edges', '3'])
+
+def test_parse_args_digit_range():
+    """
+    Vérifie que --digit-range accepte un intervalle de rangs et exclut les
+    autres affichages partiels.
+    """
+    assert parse_args(['calc', '-n', '10^8', '--digit-range', '1000000:1000010']).digit_range == (
+        1_000_000, 1_000_010
+    )
+    assert parse_args(['calc', '-n', '10']).digit_range is None
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', '100', '--digit-range', '1:3', '--edges', '3'])
+
+@pytest.mark.parametrize("value, message", [
+    ("5", "intervalle de chiffres invalide"),
+    ("a:b", "intervalle de chiffres invalide"),
+    ("0:3", "commencent à 1"),
+    ("5:4", "inférieur ou égal"),
+])
+def test_parse_args_invalid_digit_range(capsys, value, message):
+    """
+    Vérifie qu'un intervalle de rangs malformé, vide ou commençant avant le
+    premier chiffre est refusé.
+    """
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', '100', '--digit-range', value])
+    assert message in capsys.readouterr().err
+
+@pytest.mark.parametrize("value", ["-0.1", "1.5", "beaucoup"])
+def test_parse_args_invalid_memory_fraction(value):
+    """
+    Vérifie qu'une fraction de la mémoire hors de [0, 1] est refusée.
+    """
+    with pytest.raises(SystemExit):
+        parse_args(['calc', '-n', '100', '--memory-fraction', value])
+
+
+@pytest.mark.parametrize("text, expected", [
+    ('{"n": 1000}', {"n": 1000, "algo": "fast", "mod": None}),
+    ('{"n": -8, "algo": "matrix"}', {"n": -8, "algo": "matrix"}),
+    ('{"n": "10^3", "algo": ["fast", "matrix"]}', {"n": 1000, "algo": "fast,matrix"}),
+    ('{"n": 100, "mod": "10,7"}', {"mod": [10, 7]}),
+    ('{"n": 100, "mod": [10, 10, 7]}', {"mod": [10, 7]}),
+    ('{"n": 100, "mod": 97}', {"mod": [97]}),
+])
+def test_apply_json_request(text, expected):
+    """Vérifie que les champs de la requête remplacent les options et imposent le JSON."""
+    args = parse_args(["request"])
+    apply_json_request(args, text)
+    assert {key: getattr(args, key) for key in expected} == expected
+    assert args.json
+
+
+@pytest.mark.parametrize("text, message", [
+    ('{"n": 100', "malformée"),
+    ("", "malformée"),
+    ("[100]", "doit être un objet"),
+    ('{"algo": "fast"}', "'n' manquant"),
+    ('{"n": 100, "modulus": 7}', "Champ inconnu"),
+    ('{"n": true}', "Champ 'n' invalide"),
+    ('{"n": 1.5}', "Champ 'n' invalide"),
+    ('{"n": "abc"}', "Champ 'n' invalide"),
+    ('{"n": 100, "algo": "nope"}', "Champ 'algo' invalide"),
+    ('{"n": 100, "mod": "x"}', "Champ 'mod' invalide"),
+])
+def test_apply_json_request_rejects_invalid(text, message):
+    """Vérifie qu'une requête invalide est refusée avec un message explicite."""
+    with pytest.raises(ValueError, match=message):
+        apply_json_request(parse_args(["request"]), text)
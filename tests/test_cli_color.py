@@ -0,0 +1,44 @@
+"""
+Tests pour la coloration des messages de la CLI.
+"""
+import io
+from unittest.mock import MagicMock
+
+import pytest
+from pyfibonacci.cli.color import color_enabled, paint
+
+
+def tty_stream(isatty):
+    """Construit un flux simulé qui se déclare (ou non) comme un terminal."""
+    stream = MagicMock()
+    stream.isatty.return_value = isatty
+    return stream
+
+
+@pytest.mark.parametrize(
+    "mode, isatty, expected",
+    [
+        ("always", False, True),
+        ("never", True, False),
+        ("auto", True, True),
+        ("auto", False, False),
+    ],
+)
+def test_color_enabled(mode, isatty, expected, monkeypatch):
+    """Vérifie la décision de coloration selon le mode et le flux."""
+    monkeypatch.delenv("NO_COLOR", raising=False)
+    assert color_enabled(mode, tty_stream(isatty)) is expected
+
+
+def test_color_enabled_honors_no_color(monkeypatch):
+    """Vérifie que NO_COLOR désactive la coloration automatique, mais pas 'always'."""
+    monkeypatch.setenv("NO_COLOR", "1")
+    assert not color_enabled("auto", tty_stream(True))
+    assert color_enabled("always", tty_stream(False))
+
+
+def test_paint():
+    """Vérifie que le texte n'est encadré de séquences ANSI que si la coloration est active."""
+    assert paint("OK", "green", "always", io.StringIO()) == "\033[32mOK\033[0m"
+    assert paint("OK", "green", "never", io.StringIO()) == "OK"
+    assert paint("OK", "green", "auto", io.StringIO()) == "OK"
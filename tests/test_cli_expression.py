@@ -0,0 +1,66 @@
+"""
+Tests pour l'évaluation des expressions d'indice de l'option `-n`.
+"""
+import re
+
+import pytest
+from pyfibonacci.cli.expression import MAX_EXPRESSION_VALUE, ExpressionError, evaluate_index
+
+
+@pytest.mark.parametrize("text, expected", [
+    ("1000", 1000),
+    ("-10", -10),
+    ("1_000_000", 1_000_000),
+    (str(2**70), 2**70),
+])
+def test_evaluate_index_plain_numbers_unchanged(text, expected):
+    """Vérifie qu'un nombre simple est converti comme par `int`, sans borne."""
+    assert evaluate_index(text) == expected
+
+
+@pytest.mark.parametrize("text, expected", [
+    ("2^30", 2**30),
+    ("10^8+1", 10**8 + 1),
+    ("2 * 3 + 4", 10),
+    ("2 + 3 * 4", 14),
+    ("(2 + 3) * 4", 20),
+    ("2^3^2", 512),
+    ("2*3^2", 18),
+    ("10 - 4 - 3", 3),
+    ("((7))", 7),
+    ("0^0", 1),
+    ("1^100000000000", 1),
+    ("2^63 + (2^63 - 1)", MAX_EXPRESSION_VALUE),
+])
+def test_evaluate_index_expressions(text, expected):
+    """Vérifie les opérateurs, leurs priorités et l'associativité de '^'."""
+    assert evaluate_index(text) == expected
+
+
+@pytest.mark.parametrize("text, message", [
+    ("2^64", "excède 2^64 - 1"),
+    ("2^63 + 2^63", "excède 2^64 - 1"),
+    ("2^2^100", "excède 2^64 - 1"),
+    ("2^32 * 2^32", "excède 2^64 - 1"),
+    ("1 - 2 + 5", "devient négative"),
+])
+def test_evaluate_index_rejects_overflow(text, message):
+    """Vérifie qu'une expression qui sort de [0, 2^64 - 1] est refusée, sans repli modulo 2^64."""
+    with pytest.raises(ExpressionError, match=re.escape(message)):
+        evaluate_index(text)
+
+
+@pytest.mark.parametrize("text, message", [
+    ("2^", "expression incomplète"),
+    ("(2+3", "parenthèse fermante manquante en position 5"),
+    ("2+3)", "')' inattendu en position 4"),
+    ("2 ** 3", "'*' inattendu en position 4"),
+    ("2 x 3", "caractère inattendu 'x' en position 3"),
+    ("2.5", "caractère inattendu '.' en position 2"),
+    ("-2^3", "'-' inattendu en position 1"),
+    ("   ", "expression vide"),
+])
+def test_evaluate_index_rejects_invalid_syntax(text, message):
+    """Vérifie que l'erreur de syntaxe désigne le lexème fautif et sa position."""
+    with pytest.raises(ExpressionError, match=re.escape(message)):
+        evaluate_index(text)
@@ -31,3 +31,17 @@ def test_main_keyboard_interrupt(mock_asyncio_run, mock_set_digits, capsys):
     mock_asyncio_run.assert_called_once()
     captured = capsys.readouterr()
     assert "\nProgramme interrompu par l'utilisateur." in captured.out
+
+@patch('pyfibonacci.cli.main.sys.set_int_max_str_digits')
+@patch('pyfibonacci.cli.main.load_plugins', side_effect=ValueError("L'algorithme 'fast' est déjà enregistré."))
+@patch('pyfibonacci.cli.main.asyncio.run')
+def test_main_invalid_plugin(mock_asyncio_run, mock_load_plugins, mock_set_digits, capsys):
+    """
+    Vérifie qu'une extension invalide interrompt le lancement avec un message
+    d'erreur, avant l'exécution de l'application.
+    """
+    with pytest.raises(SystemExit) as excinfo:
+        main()
+    assert excinfo.value.code == 1
+    mock_asyncio_run.assert_not_called()
+    assert "Extension invalide" in capsys.readouterr().err
@@ -0,0 +1,47 @@
+"""
+Tests pour le module de mesure de la consommation mémoire.
+"""
+import tracemalloc
+
+from pyfibonacci.cli import memory
+from pyfibonacci.cli.memory import (
+    MemorySnapshot,
+    describe_memory,
+    start_memory_tracking,
+    stop_memory_tracking,
+)
+
+
+def test_memory_tracking_measures_peak_allocation():
+    """Vérifie que le pic mesuré couvre une allocation libérée avant la fin du suivi."""
+    before = start_memory_tracking()
+    assert tracemalloc.is_tracing()
+    large = 1 << (8 * 4 * 1024 * 1024)  # Un entier de 4 Mio.
+    del large
+    after = stop_memory_tracking()
+
+    assert not tracemalloc.is_tracing()
+    assert after.peak - before.allocated >= 4 * 1024 * 1024
+    assert after.allocated - before.allocated < 4 * 1024 * 1024
+    assert after.collections >= before.collections
+
+
+def test_max_rss_without_resource(monkeypatch):
+    """Vérifie que la taille résidente est omise sur une plate-forme qui ne l'expose pas."""
+    monkeypatch.setattr(memory, "resource", None)
+    assert memory._max_rss() is None
+
+
+def test_describe_memory():
+    """Vérifie le résumé de la consommation mémoire d'un calcul."""
+    before = MemorySnapshot(allocated=1024, peak=1024, collections=3)
+    after = MemorySnapshot(allocated=2048, peak=3 * 1024**2, collections=5, max_rss=40 * 1024**2)
+    assert describe_memory(before, after) == (
+        "Mémoire : pic de 3.0 Mio, +1.0 Kio retenus, 2 passes du ramasse-miettes, "
+        "taille résidente max. 40.0 Mio"
+    )
+
+    released = MemorySnapshot(allocated=0, peak=1024, collections=3)
+    assert describe_memory(before, released) == (
+        "Mémoire : pic de 0 o, -1.0 Kio retenus, 0 passes du ramasse-miettes"
+    )
@@ -0,0 +1,526 @@
+"""
+Tests unitaires pour le module `pyfibonacci.cli.output`.
+"""
+
+import csv
+import hashlib
+import io
+import json
+from unittest.mock import patch
+
+import pytest
+from pyfibonacci.cli.output import (
+    CSV_COLUMNS,
+    CalculationResult,
+    DisplayOptions,
+    append_csv,
+    count_digits,
+    digit_range,
+    describe_durations,
+    describe_progress,
+    digit_sum,
+    encode_json_result,
+    first_mismatch,
+    format_scientific,
+    format_value,
+    group_digits,
+    hash_value,
+    iter_base_chunks,
+    iter_decimal_chunks,
+    read_binary,
+    truncate_digits,
+    write_binary,
+    write_value,
+)
+from pyfibonacci.core.context import CalculationProgress
+from pyfibonacci.core.errors import CalculationError, ErrorCategory
+
+
+def test_calculation_result_success():
+    """
+    Vérifie que le succès d'un résultat dépend de l'absence d'erreur.
+    """
+    assert CalculationResult("fast", 55, 0.1).success
+    assert not CalculationResult("fast", None, 0.1, error="boom").success
+
+
+def test_encode_json_result():
+    """
+    Vérifie le contenu du document JSON pour un calcul réussi.
+    """
+    results = [
+        CalculationResult("fast", 12586269025, 0.0015),
+        CalculationResult("matrix", None, 2.0, error="timeout (2.0s)", timed_out=True),
+    ]
+
+    document = json.loads(encode_json_result(50, results))
+
+    assert document["n"] == 50
+    assert document["digits"] == 11
+    assert document["bit_length"] == 34
+    assert document["value"] == "12586269025"
+    assert document["results"] == [
+        {"algorithm": "fast", "duration_ns": 1500000, "success": True, "error": None},
+        {"algorithm": "matrix", "duration_ns": 2000000000, "success": False, "error": "timeout (2.0s)"},
+    ]
+
+
+def test_encode_json_result_negative_value():
+    """
+    Vérifie que le nombre de chiffres ignore le signe d'un résultat négatif.
+    """
+    document = json.loads(encode_json_result(-10, [CalculationResult("fast", -55, 0.0)]))
+
+    assert document["digits"] == 2
+    assert document["value"] == "-55"
+
+
+def test_encode_json_result_all_failed():
+    """
+    Vérifie que les champs dérivés de la valeur sont nuls si tout a échoué.
+    """
+    document = json.loads(encode_json_result(10, [CalculationResult("fast", None, 1.0, error="boom")]))
+
+    assert document["digits"] is None
+    assert document["bit_length"] is None
+    assert document["value"] is None
+
+
+@pytest.mark.parametrize("value, base, expected", [
+    (255, 16, "ff"),
+    (5, 2, "101"),
+    (8, 8, "10"),
+    (35, 36, "z"),
+    (0, 7, "0"),
+    (-55, 16, "-37"),
+    (12586269025, 10, "12586269025"),
+])
+def test_format_value(value, base, expected):
+    """
+    Vérifie la représentation d'entiers dans différentes bases.
+    """
+    assert format_value(value, base) == expected
+
+
+@pytest.mark.parametrize("base", [3, 7, 36])
+def test_format_value_large_number_round_trip(base):
+    """
+    Vérifie la conversion "diviser pour régner" sur un grand nombre,
+    y compris les zéros internes qui doivent être préservés.
+    """
+    value = base**500 + base**130 + 1
+    assert int(format_value(value, base), base) == value
+
+
+@pytest.mark.parametrize("base", [1, 37])
+def test_format_value_invalid_base(base):
+    """
+    Vérifie qu'une base hors de l'intervalle [2, 36] est rejetée.
+    """
+    with pytest.raises(ValueError):
+        format_value(10, base)
+
+
+@pytest.mark.parametrize("text, limit, edges, expected", [
+    ("123456789", None, 2, "123456789"),
+    ("123456789", 9, 2, "123456789"),
+    ("1234567890", 9, 2, "12...90 (10 chiffres)"),
+    ("-1234567890", 9, 3, "-123...890 (10 chiffres)"),
+])
+def test_truncate_digits(text, limit, edges, expected):
+    """
+    Vérifie que seul un nombre plus long que la limite est tronqué, signe
+    conservé.
+    """
+    assert truncate_digits(text, limit, edges) == expected
+
+
+def test_display_options_format_result():
+    """
+    Vérifie que la troncature s'applique à la représentation dans la base
+    choisie et groupe le nombre total de chiffres.
+    """
+    display = DisplayOptions(base=16, truncate_limit=100, truncate_edges=4, thousands_sep=",")
+    assert display.format_result(2**4000 - 1) == "ffff...ffff (1,000 chiffres)"
+    assert display.format_result(255) == "ff"
+    assert DisplayOptions().format_result(10**200) == str(10**200)
+
+
+@pytest.mark.parametrize("value", [10**200, 10**200 + 7, -(3**500), 12345678901, 9 * 10**150 + 1])
+def test_display_options_format_result_decimal(value):
+    """
+    Vérifie qu'en base 10 un résultat tronqué est mis en forme sans
+    conversion décimale complète, à l'identique de `truncate_digits`.
+    """
+    display = DisplayOptions(truncate_limit=10, truncate_edges=4)
+    expected = truncate_digits(str(value), 10, 4)
+    with patch("pyfibonacci.cli.output.format_value", side_effect=AssertionError):
+        assert display.format_result(value) == expected
+
+
+def test_display_options_write_result():
+    """
+    Vérifie qu'un résultat affiché en entier est écrit par fragments, sans
+    construire sa représentation complète, et qu'un résultat tronqué est
+    écrit comme `format_result` le met en forme.
+    """
+    value = 3**20000
+    stream = io.StringIO()
+    with patch("pyfibonacci.cli.output.format_value", wraps=format_value) as conversion:
+        DisplayOptions().write_result(stream, "Résultat (fast): ", value)
+    assert stream.getvalue() == "Résultat (fast): " + "".join(iter_decimal_chunks(value)) + "\n"
+    assert all(call.args[0] < value for call in conversion.call_args_list)
+
+    display = DisplayOptions(truncate_limit=100, truncate_edges=4)
+    stream = io.StringIO()
+    display.write_result(stream, "> ", 3**500)
+    assert stream.getvalue() == f"> {display.format_result(3**500)}\n"
+
+
+@pytest.mark.parametrize("value", [0, 7, -42, 10**50, 10**50 + 1, -(3**500), 2**1000 - 1])
+@pytest.mark.parametrize("chunk_digits", [1, 3, 16])
+def test_iter_decimal_chunks(value, chunk_digits):
+    """
+    Vérifie que les fragments reconstituent la représentation décimale, zéros
+    intérieurs compris, sans dépasser la taille demandée.
+    """
+    chunks = list(iter_decimal_chunks(value, chunk_digits))
+
+    assert "".join(chunks) == str(value)
+    assert all(len(chunk) <= chunk_digits for chunk in chunks if chunk != "-")
+
+
+@pytest.mark.parametrize("value", [0, 1, -13, 255, 256, 2**64, -(3**500), 2**1000 - 1])
+@pytest.mark.parametrize("base", [2, 3, 8, 16, 32, 36])
+@pytest.mark.parametrize("chunk_digits", [1, 3, 16])
+def test_iter_base_chunks(value, base, chunk_digits):
+    """
+    Vérifie que les fragments reconstituent la représentation dans chaque
+    base, par découpage des bits comme par divisions, zéros intérieurs
+    compris.
+    """
+    chunks = list(iter_base_chunks(value, base, chunk_digits))
+
+    assert "".join(chunks) == format_value(value, base)
+    assert all(len(chunk) <= chunk_digits for chunk in chunks if chunk != "-")
+
+
+def test_iter_base_chunks_rejects_invalid_base():
+    """Vérifie le refus d'une base hors de l'intervalle [2, 36]."""
+    with pytest.raises(ValueError):
+        list(iter_base_chunks(10, 37))
+
+
+def test_iter_decimal_chunks_large_value_bypasses_str_limit():
+    """
+    Vérifie que la conversion par fragments n'est pas soumise à la limite de
+    chiffres de `str`, chaque fragment restant sous cette limite.
+    """
+    value = 7**20000
+    chunks = list(iter_decimal_chunks(value))
+
+    rebuilt = 0
+    for chunk in chunks:
+        rebuilt = rebuilt * 10 ** len(chunk) + int(chunk)
+
+    assert len(chunks) > 1
+    assert rebuilt == value
+
+
+@pytest.mark.parametrize("expected, position", [
+    (["12586269025"], None),
+    (["125", "86\n269", "025\n"], None),
+    (["12586", "", "269025"], None),
+    (["12586279025"], 7),
+    (["1258626902"], 11),
+    (["125862690250"], 12),
+    ([], 1),
+])
+def test_first_mismatch(expected, position):
+    """
+    Vérifie la position du premier caractère divergent, quel que soit le
+    découpage de la référence, blancs ignorés.
+    """
+    assert first_mismatch(12586269025, expected) == position
+
+
+def test_first_mismatch_across_chunks():
+    """Vérifie la comparaison lorsque la valeur est produite en plusieurs fragments."""
+    value = 3**30000
+    text = "".join(iter_decimal_chunks(value))
+    assert first_mismatch(value, [text[:5000], text[5000:]]) is None
+    altered = text[:9000] + ("1" if text[9000] != "1" else "2") + text[9001:]
+    assert first_mismatch(value, [altered]) == 9001
+
+
+@pytest.mark.parametrize("value, base, expected", [
+    (-12586269025, 10, "-12586269025\n"),
+    (255, 16, "ff\n"),
+    (-(2**70), 2, "-1" + "0" * 70 + "\n"),
+    (3**9000, 3, "1" + "0" * 9000 + "\n"),
+])
+def test_write_value(value, base, expected):
+    """Vérifie l'écriture d'une valeur sur un flux, suivie d'un saut de ligne."""
+    stream = io.StringIO()
+    write_value(stream, value, base)
+    assert stream.getvalue() == expected
+
+
+@pytest.mark.parametrize("value", [0, 1, -13, 255, 256, 2**64, -(3**1000)])
+def test_binary_round_trip(value):
+    """Vérifie qu'une valeur écrite au format binaire est relue à l'identique."""
+    stream = io.BytesIO()
+    size = write_binary(stream, value)
+    assert size == len(stream.getvalue()) == 14 + (abs(value).bit_length() + 7) // 8
+    stream.seek(0)
+    assert read_binary(stream) == value
+
+
+def test_binary_layout():
+    """Vérifie l'en-tête et la magnitude gros-boutiste du format binaire."""
+    stream = io.BytesIO()
+    write_binary(stream, -258)
+    assert stream.getvalue() == b"PYFB\x01\x01" + (2).to_bytes(8, "big") + b"\x01\x02"
+
+
+@pytest.mark.parametrize("algorithm", ["sha256", "blake2b"])
+@pytest.mark.parametrize("value", [0, 55, -258, 3**1000])
+def test_hash_value_matches_binary_export(algorithm, value):
+    """Vérifie que l'empreinte est celle de l'export binaire, signe compris."""
+    stream = io.BytesIO()
+    write_binary(stream, value)
+    assert hash_value(value, algorithm) == hashlib.new(algorithm, stream.getvalue()).hexdigest()
+    assert hash_value(value, algorithm) != hash_value(value + 1, algorithm)
+
+
+def test_encode_json_result_hash():
+    """
+    Vérifie que l'empreinte est publiée sur demande, même sans la valeur.
+    """
+    results = [CalculationResult("fast", 55, 0.0)]
+    assert "hash" not in json.loads(encode_json_result(10, results))
+
+    document = json.loads(encode_json_result(10, results, show_value=False, hash_algorithm="sha256"))
+    assert document["hash_algorithm"] == "sha256"
+    assert document["hash"] == hash_value(55)
+
+    failed = [CalculationResult("fast", None, 0.0, error="boom")]
+    assert json.loads(encode_json_result(10, failed, hash_algorithm="sha256"))["hash"] is None
+
+
+@pytest.mark.parametrize("data, message", [
+    (b"12586269025\n", "pas un export binaire"),
+    (b"PYFB\x01", "en-tête incomplet"),
+    (b"PYFB\x02\x00" + (1).to_bytes(8, "big") + b"\x01", "Version du format binaire"),
+    (b"PYFB\x01\x00" + (4).to_bytes(8, "big") + b"\x01", "1 octets lus sur 4"),
+])
+def test_read_binary_invalid(data, message):
+    """Vérifie qu'un fichier invalide ou tronqué est refusé."""
+    with pytest.raises(ValueError, match=message):
+        read_binary(io.BytesIO(data))
+
+
+def test_encode_json_result_base():
+    """
+    Vérifie que la valeur JSON est exprimée dans la base demandée.
+    """
+    document = json.loads(encode_json_result(10, [CalculationResult("fast", 55, 0.0)], base=16))
+
+    assert document["base"] == 16
+    assert document["value"] == "37"
+    assert document["digits"] == 2
+
+
+
+def test_encode_json_result_repeated_durations():
+    """
+    Vérifie que les durées de chaque exécution ne sont publiées que si le calcul a été répété.
+    """
+    results = [
+        CalculationResult("fast", 55, 0.002, durations=[0.001, 0.002, 0.004]),
+        CalculationResult("matrix", 55, 0.003),
+    ]
+    entries = json.loads(encode_json_result(10, results))["results"]
+
+    assert entries[0]["durations_ns"] == [1000000, 2000000, 4000000]
+    assert "durations_ns" not in entries[1]
+
+
+@pytest.mark.parametrize("value, expected", [
+    (0, (0, 0)),
+    (9, (9, 9)),
+    (12586269025, (46, 1)),
+    (-354224848179261915075, (93, 3)),
+    (10**1000, (1, 1)),
+])
+def test_digit_sum(value, expected):
+    """Vérifie la somme des chiffres et la racine numérique, signe ignoré."""
+    assert digit_sum(value) == expected
+
+
+def test_encode_json_result_digit_sum():
+    """
+    Vérifie que la somme des chiffres n'est publiée que sur demande, et vaut
+    `null` si aucun algorithme n'a abouti.
+    """
+    results = [CalculationResult("fast", 55, 0.0)]
+    assert "digit_sum" not in json.loads(encode_json_result(10, results))
+
+    document = json.loads(encode_json_result(10, results, base=16, digitsum=True))
+    assert (document["digit_sum"], document["digital_root"]) == (10, 1)
+
+    failed = [CalculationResult("fast", None, 0.0, error="boom")]
+    document = json.loads(encode_json_result(10, failed, digitsum=True))
+    assert document["digit_sum"] is None and document["digital_root"] is None
+
+
+@pytest.mark.parametrize("value, digits, expected", [
+    (354224848179261915075, 6, "3.54225e+20"),
+    (-354224848179261915075, 3, "-3.54e+20"),
+    (354224848179261915075, 21, "3.54224848179261915075e+20"),
+    (354224848179261915075, 25, "3.542248481792619150750000e+20"),
+    (999999, 3, "1.00e+06"),
+    (12345, 1, "1e+04"),
+    (7, 4, "7.000e+00"),
+    (0, 3, "0.00e+00"),
+])
+def test_format_scientific(value, digits, expected):
+    """Vérifie l'arrondi, le report d'exposant et les petits nombres."""
+    assert format_scientific(value, digits) == expected
+
+
+def test_format_scientific_beyond_float_range():
+    """
+    Vérifie que chaque chiffre demandé est exact, bien au-delà de la précision
+    et de l'étendue d'un flottant.
+    """
+    value = 3**5000
+    digits = 60
+    text = "".join(iter_decimal_chunks(value))
+    expected_mantissa = (int(text[:digits + 1]) + 5) // 10
+
+    mantissa, exponent = format_scientific(value, digits).split("e")
+    assert mantissa.replace(".", "") == str(expected_mantissa)
+    assert int(exponent) == len(text) - 1
+
+
+@pytest.mark.parametrize("value, sep, expected", [
+    (1234567, " ", "1 234 567"),
+    (1234567, ".", "1.234.567"),
+    (1234567, ",", "1,234,567"),
+    (1234567, "", "1234567"),
+    (-1234567, " ", "-1 234 567"),
+    (-123456, ".", "-123.456"),
+    (999, " ", "999"),
+    (-7, ".", "-7"),
+    (0, ",", "0"),
+])
+def test_group_digits(value, sep, expected):
+    """Vérifie le groupement par milliers, signe et nombres courts compris."""
+    assert group_digits(value, sep) == expected
+
+
+@pytest.mark.parametrize("durations, expected", [
+    ([0.001, 0.002, 0.006], "min 1.00 ms, médiane 2.00 ms, moyenne 3.00 ms sur 3 exécutions"),
+    ([0.0000125, 2.5], "min 12.5 µs, médiane 1.250 s, moyenne 1.250 s sur 2 exécutions"),
+    ([0.001], None),
+    ([], None),
+])
+def test_describe_durations(durations, expected):
+    """Vérifie le résumé des durées, absent si le calcul n'a pas été répété."""
+    assert describe_durations(durations) == expected
+
+
+@pytest.mark.parametrize("progress, expected", [
+    (CalculationProgress(completed=18, total=29), "~62% (bit 18/29)"),
+    (CalculationProgress(completed=29, total=29), "~100% (bit 29/29)"),
+    (CalculationProgress(), None),
+    (None, None),
+])
+def test_describe_progress(progress, expected):
+    """Vérifie la description de l'avancement, absente si non rapporté."""
+    assert describe_progress(progress) == expected
+
+
+@pytest.mark.parametrize("options, expected", [
+    (DisplayOptions(), True),
+    (DisplayOptions(json=True), False),
+    (DisplayOptions(quiet=True), False),
+])
+def test_display_options_show_messages(options, expected):
+    """Vérifie que les messages sont omis en mode JSON comme en mode silencieux."""
+    assert options.show_messages is expected
+
+
+@pytest.mark.parametrize("result, expected", [
+    (CalculationResult("fast", 55, 0.1), "success"),
+    (CalculationResult("fast", None, 0.1, error="boom"), "error"),
+    (CalculationResult("fast", None, 0.1, error="lent", timed_out=True), "timeout"),
+    (
+        CalculationResult(
+            "fast", None, 0.1, error="annulé",
+            failure=CalculationError("fast", 10, ErrorCategory.CANCELED, "annulé"),
+        ),
+        "cancel",
+    ),
+])
+def test_calculation_result_outcome(result, expected):
+    """Vérifie que l'issue d'un résultat reprend la catégorie de son échec."""
+    assert result.outcome == expected
+
+
+@pytest.mark.parametrize("value", [0, 1, 9, 10, 99, 100, -1000, 10**50 - 1, 10**50, 2**1000, 3**5000])
+def test_count_digits(value):
+    """Vérifie le nombre de chiffres, en particulier aux puissances de 10."""
+    assert count_digits(value) == len(str(abs(value)))
+
+
+@pytest.mark.parametrize("value, start, end", [
+    (354224848179261915075, 1, 5),
+    (354224848179261915075, 17, 21),
+    (-354224848179261915075, 1, 21),
+    (10**40 + 7, 2, 40),
+    (3**5000, 1000, 1010),
+    (7, 1, 1),
+])
+def test_digit_range(value, start, end):
+    """Vérifie l'extraction des chiffres, zéros de tête compris, signe exclu."""
+    assert digit_range(value, start, end) == str(abs(value))[start - 1:end]
+
+
+@pytest.mark.parametrize("start, end", [(0, 3), (5, 4), (20, 22), (22, 22)])
+def test_digit_range_rejects_out_of_bounds(start, end):
+    """Vérifie le refus d'un intervalle vide ou qui dépasse le nombre."""
+    with pytest.raises(ValueError, match="21 chiffres"):
+        digit_range(354224848179261915075, start, end)
+
+
+def test_append_csv_creates_header_then_appends(tmp_path):
+    """
+    Vérifie que le journal est créé avec son en-tête, puis complété d'une
+    ligne par algorithme à chaque exécution.
+    """
+    path = tmp_path / "runs.csv"
+    append_csv(str(path), 10, [CalculationResult("fast", 55, 0.25)])
+    append_csv(str(path), 20, [
+        CalculationResult("fast", 6765, 0.5),
+        CalculationResult("matrix", None, 1.0, error="lent", timed_out=True),
+    ])
+
+    with open(path, newline="", encoding="utf-8") as stream:
+        rows = list(csv.DictReader(stream))
+    assert list(rows[0]) == list(CSV_COLUMNS)
+    assert [(r["algorithm"], r["n"], r["duration_ns"], r["digits"], r["bit_length"], r["outcome"]) for r in rows] == [
+        ("fast", "10", "250000000", "2", "6", "success"),
+        ("fast", "20", "500000000", "4", "13", "success"),
+        ("matrix", "20", "1000000000", "", "", "timeout"),
+    ]
+
+
+def test_append_csv_marks_mismatch(tmp_path):
+    """Vérifie que les résultats en désaccord ont pour issue `mismatch`."""
+    path = tmp_path / "runs.csv"
+    append_csv(str(path), 10, [CalculationResult("fast", 55, 0.1), CalculationResult("wrong", 56, 0.1)])
+
+    with open(path, newline="", encoding="utf-8") as stream:
+        assert [row["outcome"] for row in csv.DictReader(stream)] == ["mismatch", "mismatch"]
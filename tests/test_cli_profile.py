@@ -0,0 +1,58 @@
+"""
+Tests pour le profilage des calculs.
+"""
+import json
+import pstats
+import tracemalloc
+
+import pytest
+from pyfibonacci.cli.profile import Profiler, start_profiling
+from pyfibonacci.core.algorithms import fib_iterative
+
+
+def test_profile_cpu_writes_pstats(tmp_path):
+    """Vérifie que le profil CPU est lisible par pstats et contient le calcul."""
+    profiler = start_profiling("cpu")
+    fib_iterative(1000)
+    profiler.stop()
+    path = tmp_path / "calcul.prof"
+    profiler.write(str(path))
+
+    stats = pstats.Stats(str(path))
+    assert any(name == "fib_iterative" for _, _, name in stats.stats)
+
+
+def test_profile_mem_writes_snapshot(tmp_path):
+    """Vérifie que l'instantané mémoire est relisible et que le suivi est arrêté."""
+    was_tracing = tracemalloc.is_tracing()
+    profiler = start_profiling("mem")
+    values = [fib_iterative(2000) for _ in range(10)]
+    profiler.stop()
+    path = tmp_path / "calcul.tracemalloc"
+    profiler.write(str(path))
+
+    assert values
+    assert tracemalloc.Snapshot.load(str(path)).statistics("lineno")
+    assert tracemalloc.is_tracing() == was_tracing
+
+
+def test_profile_trace_is_well_nested(tmp_path):
+    """Vérifie que la trace d'exécution est bien parenthésée, thread par thread."""
+    profiler = start_profiling("trace")
+    fib_iterative(10)
+    profiler.stop()
+    path = tmp_path / "calcul.trace.json"
+    profiler.write(str(path))
+
+    events = json.loads(path.read_text(encoding="utf-8"))["traceEvents"]
+    assert any(e["name"] == "fib_iterative" and e["ph"] == "B" for e in events)
+    depths = {}
+    for event in events:
+        depths[event["tid"]] = depths.get(event["tid"], 0) + (1 if event["ph"] == "B" else -1)
+        assert depths[event["tid"]] >= 0
+
+
+def test_profile_rejects_unknown_mode():
+    """Vérifie qu'un mode inconnu est refusé."""
+    with pytest.raises(ValueError):
+        Profiler("gpu")
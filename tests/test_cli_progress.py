@@ -3,10 +3,11 @@ Tests unitaires pour le module `pyfibonacci.cli.progress`.
 """
 
 import asyncio
+import json
 from unittest.mock import patch, MagicMock
 
 import pytest
-from pyfibonacci.cli.progress import progress_bar_manager
+from pyfibonacci.cli.progress import multi_progress_manager, progress_bar_manager
 
 @pytest.mark.asyncio
 @patch('pyfibonacci.cli.progress.tqdm')
@@ -51,37 +52,65 @@ async def test_progress_bar_manager_normal_flow(mock_tqdm):
 
 @pytest.mark.asyncio
 @patch('pyfibonacci.cli.progress.tqdm')
-async def test_progress_bar_manager_timeout(mock_tqdm):
+async def test_progress_bar_manager_colour(mock_tqdm):
     """
-    Vérifie que le gestionnaire se termine correctement si la file est vide
-    après un timeout, sans message 'done'.
+    Vérifie que la couleur demandée est transmise à la barre `tqdm`.
+    """
+    queue = asyncio.Queue()
+    await queue.put("done")
+    await progress_bar_manager(queue, 10, "Testing", colour="green")
+    mock_tqdm.assert_called_once_with(total=10, desc="Testing", unit=" steps", colour="green")
+
+@pytest.mark.asyncio
+@patch('pyfibonacci.cli.progress.tqdm')
+async def test_progress_bar_manager_slow_step(mock_tqdm):
+    """
+    Vérifie qu'une étape plus longue qu'une seconde n'abandonne pas la file :
+    le message final qui suit est bien reçu et porte la barre à 100 %.
     """
     queue = asyncio.Queue()
     mock_pbar = MagicMock()
+    mock_pbar.n = 0
+    mock_pbar.total = 50
     # The context manager returns the mock object
     mock_tqdm.return_value.__enter__.return_value = mock_pbar
 
     total = 50
-    description = "Timeout Test"
+    description = "Slow Step Test"
 
     async def producer():
         await queue.put(15)
-        # Ne pas envoyer 'done' pour simuler un timeout
+        # Une étape plus longue que l'ancien délai d'inactivité (1.0s)
+        await asyncio.sleep(1.2)
+        await queue.put("done")
 
     manager_task = asyncio.create_task(
         progress_bar_manager(queue, total, description)
     )
     await producer()
-    # Attendre un peu plus que le timeout interne de la fonction (1.0s)
-    await asyncio.sleep(1.1)
-
-    # La tâche devrait se terminer d'elle-même
     await manager_task
 
     mock_tqdm.assert_called_once_with(total=total, desc=description, unit=" steps")
     mock_pbar.update.assert_called_once_with(15)
-    # Ne doit pas aller à 100% car 'done' n'a pas été reçu
-    assert mock_pbar.n != mock_pbar.total
+    assert mock_pbar.n == mock_pbar.total
+
+
+@pytest.mark.asyncio
+@patch('pyfibonacci.cli.progress.REFRESH_INTERVAL', 0)
+async def test_progress_manager_json_slow_step(capsys):
+    """
+    Vérifie qu'en mode 'json' le statut final suit une étape lente.
+    """
+    queue = asyncio.Queue()
+
+    async def producer():
+        await queue.put(1)
+        await asyncio.sleep(1.2)
+        await queue.put("done")
+
+    await asyncio.gather(progress_bar_manager(queue, 4, "Json", mode="json"), producer())
+    events = [json.loads(line) for line in capsys.readouterr().err.splitlines()]
+    assert events[-1] == {"percent": 100.0, "status": "done"}
 
 
 @pytest.mark.asyncio
@@ -105,3 +134,149 @@ async def test_progress_bar_manager_exception(mock_tqdm):
     # Vérifie que la barre de progression a été créée mais pas mise à jour
     mock_tqdm.assert_called_once_with(total=total, desc=description, unit=" steps")
     mock_pbar.update.assert_not_called()
+
+
+@pytest.mark.asyncio
+@patch('pyfibonacci.cli.progress.REFRESH_INTERVAL', 0)
+async def test_progress_manager_plain_mode(capsys):
+    """
+    Vérifie qu'en mode 'plain' chaque mise à jour produit une ligne de
+    pourcentage sur stderr, sans retour chariot.
+    """
+    queue = asyncio.Queue()
+    for message in (1, 1, 2, "done"):
+        queue.put_nowait(message)
+
+    await progress_bar_manager(queue, 4, "Plain", mode="plain")
+
+    captured = capsys.readouterr()
+    assert captured.out == ""
+    assert captured.err.splitlines() == ["25%", "50%", "100%"]
+    assert "\r" not in captured.err
+
+
+@pytest.mark.asyncio
+@patch('pyfibonacci.cli.progress.REFRESH_INTERVAL', 0)
+async def test_progress_manager_json_mode(capsys):
+    """
+    Vérifie qu'en mode 'json' chaque ligne est un objet JSON valide.
+    """
+    queue = asyncio.Queue()
+    for message in (3, "done"):
+        queue.put_nowait(message)
+
+    await progress_bar_manager(queue, 8, "Json", mode="json")
+
+    events = [json.loads(line) for line in capsys.readouterr().err.splitlines()]
+    assert events == [{"percent": 37.5}, {"percent": 100.0, "status": "done"}]
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("mode, expected", [
+    ("plain", ["37%", "37% (timeout)"]),
+    ("json", ['{"percent": 37.5}', '{"percent": 37.5, "status": "timeout"}']),
+])
+@patch('pyfibonacci.cli.progress.REFRESH_INTERVAL', 0)
+async def test_progress_manager_text_modes_report_failure(capsys, mode, expected):
+    """
+    Vérifie qu'un calcul échoué termine la progression par un événement
+    portant son statut, au dernier pourcentage atteint plutôt qu'à 100 %.
+    """
+    queue = asyncio.Queue()
+    for message in (3, "timeout"):
+        queue.put_nowait(message)
+
+    await progress_bar_manager(queue, 8, "Echec", mode=mode)
+
+    assert capsys.readouterr().err.splitlines() == expected
+
+
+@pytest.mark.asyncio
+@patch('pyfibonacci.cli.progress.tqdm')
+async def test_progress_bar_manager_cancelled(mock_tqdm):
+    """
+    Vérifie qu'une barre annulée reste au dernier pas atteint et affiche son statut.
+    """
+    queue = asyncio.Queue()
+    mock_pbar = MagicMock()
+    mock_pbar.n = 0
+    mock_pbar.total = 10
+    mock_tqdm.return_value.__enter__.return_value = mock_pbar
+    for message in (4, "cancel"):
+        queue.put_nowait(message)
+
+    await progress_bar_manager(queue, 10, "Annulé")
+
+    mock_pbar.update.assert_called_once_with(4)
+    assert mock_pbar.n != mock_pbar.total
+    mock_pbar.set_postfix_str.assert_called_once_with("cancel")
+
+
+@pytest.mark.asyncio
+@patch('pyfibonacci.cli.progress.tqdm')
+async def test_progress_manager_none_mode(mock_tqdm, capsys):
+    """
+    Vérifie qu'en mode 'none' la file est vidée sans rien afficher.
+    """
+    queue = asyncio.Queue()
+    for message in (1, 2, "done"):
+        queue.put_nowait(message)
+
+    await progress_bar_manager(queue, 3, "None", mode="none")
+
+    assert queue.empty()
+    mock_tqdm.assert_not_called()
+    captured = capsys.readouterr()
+    assert captured.out == "" and captured.err == ""
+
+
+@pytest.mark.asyncio
+async def test_progress_manager_plain_mode_throttled(capsys):
+    """
+    Vérifie que l'intervalle de rafraîchissement limite le nombre de lignes.
+    """
+    queue = asyncio.Queue()
+    for message in [1] * 50 + ["done"]:
+        queue.put_nowait(message)
+
+    await progress_bar_manager(queue, 50, "Throttled", mode="plain")
+
+    lines = capsys.readouterr().err.splitlines()
+    assert lines[-1] == "100%"
+    assert len(lines) < 50
+
+
+@pytest.mark.asyncio
+@patch('pyfibonacci.cli.progress.tqdm')
+async def test_multi_progress_manager(mock_tqdm):
+    """
+    Vérifie qu'une barre étiquetée est créée par algorithme, à une position
+    distincte, et que chacune atteint 100 % à la réception de 'done'.
+    """
+    bars = {}
+
+    def make_bar(total, desc, unit, position):
+        bar = MagicMock(total=total, n=0)
+        bars[desc.strip()] = bar
+        return bar
+
+    mock_tqdm.side_effect = make_bar
+    queues = {"fast": asyncio.Queue(), "iterative": asyncio.Queue()}
+    queues["fast"].put_nowait(3)
+    queues["fast"].put_nowait("done")
+
+    async def late_done():
+        # L'algorithme itératif ne rapporte rien avant de terminer : sa file
+        # doit être attendue au-delà du délai d'inactivité habituel.
+        await asyncio.sleep(1.2)
+        await queues["iterative"].put("done")
+
+    await asyncio.gather(multi_progress_manager(queues, 10), late_done())
+
+    positions = [call.kwargs["position"] for call in mock_tqdm.call_args_list]
+    assert positions == [0, 1]
+    bars["fast"].update.assert_called_once_with(3)
+    assert bars["fast"].n == 10
+    assert bars["iterative"].n == 10
+    for bar in bars.values():
+        bar.close.assert_called_once()
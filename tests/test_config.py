@@ -0,0 +1,76 @@
+"""
+Tests pour le module de configuration persistante.
+"""
+
+import json
+import sys
+from unittest.mock import patch
+
+import pytest
+from pyfibonacci.cli.args import parse_args
+from pyfibonacci.config import get_config_path, load_config, save_config
+
+
+def test_get_config_path_from_environment(isolated_config):
+    """
+    Vérifie que la variable d'environnement détermine l'emplacement du fichier.
+    """
+    assert get_config_path() == isolated_config
+
+
+def test_load_config_missing_file():
+    """
+    Vérifie qu'un fichier absent produit une configuration vide.
+    """
+    assert load_config() == {}
+
+
+def test_save_and_load_config_round_trip(isolated_config):
+    """
+    Vérifie qu'une valeur enregistrée est relue, et que les autres clés du
+    fichier sont préservées.
+    """
+    isolated_config.write_text(json.dumps({"other": "kept"}))
+
+    save_config({"threshold": 4242})
+
+    assert load_config() == {"threshold": 4242}
+    assert json.loads(isolated_config.read_text())["other"] == "kept"
+
+
+@pytest.mark.parametrize("content", ["not json", "[1, 2]", '{"threshold": "high"}'])
+def test_load_config_invalid_file(isolated_config, content):
+    """
+    Vérifie qu'un fichier malformé est signalé clairement.
+    """
+    isolated_config.write_text(content)
+    with pytest.raises(ValueError):
+        load_config()
+
+
+def test_parse_args_uses_config_default(isolated_config):
+    """
+    Vérifie que le seuil enregistré remplace la valeur codée en dur.
+    """
+    save_config({"threshold": 777})
+    with patch.object(sys, "argv", ["pyfibonacci", "-n", "10"]):
+        assert parse_args().threshold == 777
+
+
+def test_parse_args_explicit_flag_overrides_config(isolated_config):
+    """
+    Vérifie qu'une option explicite a priorité sur le fichier de configuration.
+    """
+    save_config({"threshold": 777})
+    with patch.object(sys, "argv", ["pyfibonacci", "-n", "10", "--threshold", "123"]):
+        assert parse_args().threshold == 123
+
+
+def test_parse_args_invalid_config(isolated_config):
+    """
+    Vérifie qu'un fichier de configuration invalide interrompt l'analyse.
+    """
+    isolated_config.write_text("not json")
+    with patch.object(sys, "argv", ["pyfibonacci", "-n", "10"]):
+        with pytest.raises(SystemExit):
+            parse_args()
@@ -0,0 +1,82 @@
+"""
+Tests pour la recherche des points de croisement entre algorithmes.
+"""
+
+import pytest
+from pyfibonacci.cli.output import CalculationResult
+from pyfibonacci.core.algorithms import fib_iterative
+from pyfibonacci.crossover import CrossoverMeasure, crossover_ranges, describe_crossover, run_crossover
+
+
+@pytest.mark.asyncio
+async def test_run_crossover_drops_failed_algorithms():
+    """
+    Vérifie que chaque algorithme est mesuré à chaque indice, sauf après
+    son premier échec, et que la gamme s'arrête quand tous ont échoué.
+    """
+    calls = []
+
+    async def execute(n, algo_name):
+        calls.append((n, algo_name))
+        if algo_name == "iterative" and n > 10 or n > 100:
+            return CalculationResult(algo_name, None, 1.0, error="timeout (1.0s)")
+        return CalculationResult(algo_name, fib_iterative(n), n / 1000)
+
+    measures = await run_crossover(execute, ["iterative", "fast"], [10, 100, 1000, 10000])
+
+    assert calls == [
+        (10, "iterative"), (10, "fast"),
+        (100, "iterative"), (100, "fast"),
+        (1000, "fast"),
+    ]
+    assert measures == [
+        CrossoverMeasure(10, {"iterative": 0.01, "fast": 0.01}),
+        CrossoverMeasure(100, {"iterative": None, "fast": 0.1}),
+        CrossoverMeasure(1000, {"fast": None}),
+    ]
+
+
+def test_crossover_measure_fastest():
+    """Vérifie le choix du plus rapide, en ignorant les échecs."""
+    assert CrossoverMeasure(10, {"iterative": 0.2, "fast": 0.1, "matrix": None}).fastest == "fast"
+    assert CrossoverMeasure(10, {"iterative": None}).fastest is None
+
+
+def test_crossover_ranges_groups_consecutive_indices():
+    """Vérifie que seuls les changements d'algorithme ouvrent une plage."""
+    measures = [
+        CrossoverMeasure(100, {"iterative": 0.1, "fast": 0.2}),
+        CrossoverMeasure(200, {"iterative": 0.1, "fast": 0.2}),
+        CrossoverMeasure(500, {"iterative": None}),
+        CrossoverMeasure(1000, {"iterative": 0.3, "fast": 0.2}),
+        CrossoverMeasure(2000, {"matrix": 0.1, "fast": 0.2}),
+    ]
+    assert crossover_ranges(measures) == [(100, "iterative"), (1000, "fast"), (2000, "matrix")]
+
+
+def test_describe_crossover_spans():
+    """Vérifie le tableau et la description des plages d'indices."""
+    measures = [
+        CrossoverMeasure(100, {"iterative": 0.001, "fast": 0.002}),
+        CrossoverMeasure(1000, {"iterative": 0.003, "fast": 0.002}),
+        CrossoverMeasure(10000, {"iterative": None, "fast": 0.02}),
+        CrossoverMeasure(100000, {"matrix": 0.1, "fast": 0.2}),
+    ]
+    text = describe_crossover(["iterative", "fast", "matrix"], measures)
+
+    assert "|         10 000 |       ÉCHEC |    20.00 ms |           - | fast           |" in text
+    assert text.endswith(
+        "Algorithme le plus rapide :\n"
+        "  n < 1 000 : iterative\n"
+        "  1 000 ≤ n < 100 000 : fast\n"
+        "  n ≥ 100 000 : matrix"
+    )
+
+
+@pytest.mark.parametrize("measures, expected", [
+    ([CrossoverMeasure(100, {"fast": 0.1})], "à tous les indices mesurés : fast"),
+    ([CrossoverMeasure(100, {"fast": None})], "Aucun algorithme n'a abouti."),
+])
+def test_describe_crossover_without_crossing(measures, expected):
+    """Vérifie les rapports sans point de croisement."""
+    assert describe_crossover(["fast"], measures).endswith(expected)
@@ -0,0 +1,74 @@
+"""
+Tests pour le module d'estimation du coût d'un calcul.
+"""
+import pytest
+from pyfibonacci.core.algorithms import predict_bit_length, predict_digits
+from pyfibonacci import estimate
+from pyfibonacci.estimate import (
+    CalculationEstimate,
+    _format_bytes,
+    available_memory,
+    check_memory,
+    describe_estimate,
+    estimate_calculation,
+    predict_peak_memory,
+)
+
+
+def test_estimate_calculation():
+    """Vérifie que l'estimation reprend la taille prévue et croît avec n."""
+    small = estimate_calculation(10_000)
+    large = estimate_calculation(1_000_000)
+
+    assert (small.digits, small.bit_length) == (predict_digits(10_000), predict_bit_length(10_000))
+    assert small.peak_memory_bytes > small.digits
+    assert 0 < small.duration < large.duration
+    assert small.peak_memory_bytes < large.peak_memory_bytes
+
+
+@pytest.mark.parametrize("size, expected", [
+    (512, "512 o"),
+    (2048, "2.0 Kio"),
+    (3 * 1024**3, "3.0 Gio"),
+    (5 * 1024**4, "5.0 Tio"),
+])
+def test_format_bytes(size, expected):
+    """Vérifie la mise en forme des tailles dans l'unité binaire la plus lisible."""
+    assert _format_bytes(size) == expected
+
+
+def test_describe_estimate():
+    """Vérifie le rapport affiché pour une estimation."""
+    report = describe_estimate(CalculationEstimate(10**7, 2089877, 6942418, 6 * 1024**2, 2.5))
+    assert "Chiffres décimaux : ~2 089 877" in report
+    assert "Mémoire de pointe : ~6.0 Mio" in report
+    assert "Durée du calcul   : ~2.500 s" in report
+
+    report = describe_estimate(CalculationEstimate(10**7, 2089877, 6942418, 6 * 1024**2, 2.5), ".")
+    assert "Chiffres décimaux : ~2.089.877" in report
+    assert "Taille binaire    : ~6.942.418 bits" in report
+
+
+def test_predict_peak_memory():
+    """Vérifie que la mémoire prévue est celle de l'estimation complète."""
+    assert predict_peak_memory(1_000_000) == estimate_calculation(1_000_000).peak_memory_bytes
+    assert predict_peak_memory(-1_000_000) == predict_peak_memory(1_000_000)
+
+
+def test_available_memory():
+    """Vérifie que la mémoire disponible, si elle est connue, est positive."""
+    available = available_memory()
+    assert available is None or available > 0
+
+
+def test_check_memory(monkeypatch):
+    """Vérifie que seul un résultat dépassant la mémoire disponible est refusé."""
+    monkeypatch.setattr(estimate, "available_memory", lambda: 1024**3)
+    assert check_memory(1_000_000) is None
+
+    error = check_memory(10**12)
+    assert error.startswith("Le calcul de F(1000000000000) nécessiterait environ ")
+    assert "au-delà des 1.0 Gio disponibles" in error
+
+    monkeypatch.setattr(estimate, "available_memory", lambda: None)
+    assert check_memory(10**12) is None
@@ -0,0 +1,56 @@
+"""
+Tests pour la recherche inverse de l'indice d'un nombre de Fibonacci.
+"""
+import pytest
+from pyfibonacci.core.algorithms import apply_negafibonacci_sign, fib_iterative
+from pyfibonacci.core.context import CalculationContext
+from pyfibonacci.core.inverse import fibonacci_indices, is_fibonacci, is_perfect_square
+
+
+@pytest.fixture
+def context():
+    """Fournit un contexte de calcul de base pour les tests."""
+    return CalculationContext(threshold=10000)
+
+
+@pytest.mark.parametrize("m, expected", [(0, True), (1, True), (2, False), (49, True), (50, False), (-4, False)])
+def test_is_perfect_square(m, expected):
+    """Vérifie la détection des carrés parfaits, négatifs compris."""
+    assert is_perfect_square(m) is expected
+
+
+def test_is_perfect_square_large_values():
+    """Vérifie que les grands carrés, et leurs voisins, sont correctement classés."""
+    root = 3**5000 + 1
+    assert is_perfect_square(root * root)
+    assert not is_perfect_square(root * root + 1)
+    assert not is_perfect_square(root * root - 1)
+
+
+def test_is_fibonacci_matches_sequence():
+    """Vérifie que seuls les termes de la suite sont reconnus, jusqu'à F(25)."""
+    terms = {fib_iterative(n) for n in range(26)}
+    assert [m for m in range(fib_iterative(25) + 1) if is_fibonacci(m)] == sorted(terms)
+
+
+@pytest.mark.parametrize("m, expected", [(0, (0,)), (1, (1, 2)), (-1, (-2,)), (2, (3,)), (-2, ()), (-8, (-6,)), (4, ())])
+@pytest.mark.asyncio
+async def test_fibonacci_indices_edge_cases(context, m, expected):
+    """Vérifie les cas particuliers : 0, 1 (deux indices) et les valeurs négatives."""
+    assert await fibonacci_indices(context, m) == expected
+
+
+@pytest.mark.parametrize("n", [3, 12, 71, 72, 73, 1000, 20000, -40, -1000])
+@pytest.mark.asyncio
+async def test_fibonacci_indices_finds_index(context, n):
+    """Vérifie que l'indice est retrouvé, y compris lorsque l'estimation en est voisine."""
+    value = apply_negafibonacci_sign(n, fib_iterative(abs(n)))
+    assert await fibonacci_indices(context, value) == (n,)
+
+
+@pytest.mark.asyncio
+async def test_fibonacci_indices_rejects_neighbours(context):
+    """Vérifie que les voisins d'un grand terme ne sont pas reconnus."""
+    value = fib_iterative(5000)
+    assert await fibonacci_indices(context, value + 1) == ()
+    assert await fibonacci_indices(context, value - 1) == ()
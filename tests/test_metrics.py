@@ -0,0 +1,86 @@
+"""
+Tests pour l'instrumentation Prometheus des calculs.
+"""
+import asyncio
+from unittest.mock import AsyncMock, MagicMock, patch
+
+import pytest
+from prometheus_client import REGISTRY
+from pyfibonacci import metrics
+from pyfibonacci.app import _execute_algorithm, _run_all_algorithms
+from pyfibonacci.cli.output import CalculationResult
+from pyfibonacci.core.context import CalculationContext
+
+
+def sample(name, **labels):
+    """Retourne la valeur courante d'une série du registre par défaut (0 si absente)."""
+    return REGISTRY.get_sample_value(name, labels) or 0.0
+
+
+def calculations(algorithm, outcome):
+    """Retourne le nombre de calculs enregistrés pour un algorithme et une issue."""
+    return sample("pyfibonacci_calculations_total", algorithm=algorithm, outcome=outcome)
+
+
+def test_record_result_outcomes():
+    """Vérifie que chaque type de résultat incrémente l'issue correspondante."""
+    before = {o: calculations("test_record", o) for o in ("success", "error", "timeout")}
+    durations_before = sample("pyfibonacci_calculation_duration_seconds_count", algorithm="test_record")
+
+    metrics.record_result(CalculationResult("test_record", 55, 0.5))
+    metrics.record_result(CalculationResult("test_record", None, 0.1, error="boom"))
+    metrics.record_result(CalculationResult("test_record", None, 1.0, error="timeout", timed_out=True))
+
+    for outcome in ("success", "error", "timeout"):
+        assert calculations("test_record", outcome) == before[outcome] + 1
+    # Seuls les calculs réussis alimentent l'histogramme des durées.
+    assert sample("pyfibonacci_calculation_duration_seconds_count", algorithm="test_record") == durations_before + 1
+
+
+def test_record_request_keeps_largest_n():
+    """Vérifie que la jauge conserve la plus grande valeur de |n| demandée."""
+    metrics.record_request(-10**9)
+    metrics.record_request(5)
+    assert sample("pyfibonacci_largest_n") == 10**9
+
+
+@pytest.mark.asyncio
+async def test_execute_algorithm_records_metrics():
+    """Vérifie que l'exécution d'un algorithme est instrumentée."""
+    context = MagicMock(spec=CalculationContext)
+    before = calculations("test_exec", "success")
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"test_exec": AsyncMock(return_value=55)}):
+        await _execute_algorithm(context, 10, "test_exec", timeout=1)
+    assert calculations("test_exec", "success") == before + 1
+
+
+@pytest.mark.asyncio
+async def test_execute_algorithm_records_cancel():
+    """Vérifie qu'une annulation (ex. : déconnexion d'un client) est comptée."""
+
+    async def slow_algo(context, n):
+        await asyncio.sleep(10)
+
+    context = MagicMock(spec=CalculationContext)
+    before = calculations("test_cancel", "cancel")
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {"test_cancel": slow_algo}):
+        task = asyncio.create_task(_execute_algorithm(context, 10, "test_cancel", timeout=5))
+        await asyncio.sleep(0.01)
+        task.cancel()
+        with pytest.raises(asyncio.CancelledError):
+            await task
+    assert calculations("test_cancel", "cancel") == before + 1
+
+
+@pytest.mark.asyncio
+async def test_run_all_algorithms_records_mismatch(capsys):
+    """Vérifie qu'un désaccord entre algorithmes est signalé et compté."""
+    context = MagicMock(spec=CalculationContext)
+    before = calculations("test_wrong", "mismatch")
+    with patch("pyfibonacci.app.ALGORITHM_REGISTRY", {
+        "test_right": AsyncMock(return_value=55),
+        "test_wrong": AsyncMock(return_value=56),
+    }):
+        await _run_all_algorithms(context, 10, timeout=1)
+    assert calculations("test_wrong", "mismatch") == before + 1
+    assert "résultats différents" in capsys.readouterr().err
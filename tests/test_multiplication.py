@@ -3,11 +3,22 @@ Tests pour le module de multiplication.
 """
 
 import asyncio
+import dataclasses
 import pytest
-from concurrent.futures import ProcessPoolExecutor
+from concurrent.futures import ProcessPoolExecutor, ThreadPoolExecutor
 
-from pyfibonacci.core.context import CalculationContext
-from pyfibonacci.core.multiplication import multiply, _parallel_multiply
+from pyfibonacci.core.context import CANCELLATION_CHECK_BITS, CalculationContext, ExecutorLoad
+from unittest.mock import MagicMock, patch
+
+from pyfibonacci.core import multiplication
+from pyfibonacci.core.multiplication import (
+    GMP_THRESHOLD_BITS,
+    multiply,
+    should_parallelize,
+    square,
+    _parallel_multiply,
+    _parallel_square,
+)
 
 @pytest.mark.asyncio
 async def test_multiply_standard_when_executor_is_none():
@@ -58,3 +69,380 @@ def test_parallel_multiply_function():
     """
     a, b = 987, 654
     assert _parallel_multiply(a, b) == a * b
+
+@pytest.mark.parametrize("mul_algo, bits, expected", [
+    ("auto", 100, False),
+    ("auto", 10_000, True),
+    ("native", 10_000, False),
+    ("parallel", 100, True),
+])
+def test_should_parallelize(mul_algo, bits, expected):
+    """
+    Vérifie le point de bascule et les stratégies forcées.
+    Un seuil de 1000 chiffres correspond à environ 3322 bits.
+    """
+    context = CalculationContext(threshold=1000, executor=MagicMock(), mul_algo=mul_algo)
+    a = 1 << bits
+    assert should_parallelize(context, a, 3) is expected
+
+@pytest.mark.parametrize("mul_algo, in_flight, bits, expected", [
+    ("adaptive", 0, 10_000, True),
+    ("adaptive", 1, 10_000, True),
+    ("adaptive", 2, 10_000, False),
+    ("adaptive", 0, 100, False),
+    ("auto", 2, 10_000, True),
+])
+def test_should_parallelize_adaptive(mul_algo, in_flight, bits, expected):
+    """
+    Vérifie que la stratégie 'adaptive' ne délègue au-delà du seuil que si un
+    processus de travail est libre, et que 'auto' ignore l'occupation du pool.
+    """
+    context = CalculationContext(
+        threshold=1000,
+        executor=MagicMock(),
+        mul_algo=mul_algo,
+        executor_load=ExecutorLoad(workers=2, in_flight=in_flight),
+    )
+    assert should_parallelize(context, 1 << bits, 3) is expected
+
+@pytest.mark.asyncio
+async def test_multiply_tracks_executor_load():
+    """
+    Vérifie que les multiplications déléguées sont comptées pendant leur
+    exécution, y compris celles lancées par des calculs concurrents, et que
+    le compteur revient à zéro.
+    """
+    load = ExecutorLoad(workers=2)
+    observed = []
+
+    def product(a, b, backend):
+        observed.append(load.in_flight)
+        return a * b
+
+    with ThreadPoolExecutor(max_workers=2) as executor:
+        context = CalculationContext(
+            threshold=1000, executor=executor, mul_algo="parallel", executor_load=load
+        )
+        with patch.object(multiplication, "_parallel_multiply", product):
+            assert await asyncio.gather(multiply(context, 3, 4), multiply(context, 5, 6)) == [12, 30]
+
+    assert max(observed) >= 1
+    assert load.in_flight == 0
+
+def test_should_parallelize_without_executor():
+    """
+    Vérifie qu'aucune stratégie ne parallélise en l'absence d'exécuteur.
+    """
+    context = CalculationContext(threshold=10, executor=None, mul_algo="parallel")
+    assert not should_parallelize(context, 10**100, 10**100)
+
+def test_should_parallelize_unknown_strategy():
+    """
+    Vérifie qu'une stratégie inconnue est rejetée.
+    """
+    context = CalculationContext(threshold=10, mul_algo="fft")
+    with pytest.raises(ValueError):
+        should_parallelize(context, 2, 3)
+
+@pytest.mark.asyncio
+async def test_multiply_forced_parallel_for_small_numbers():
+    """
+    Vérifie que la stratégie 'parallel' délègue même les petits nombres.
+    """
+    with ThreadPoolExecutor() as executor:
+        context = CalculationContext(threshold=1000, executor=executor, mul_algo="parallel")
+        with patch.object(executor, "submit", wraps=executor.submit) as spy:
+            assert await multiply(context, 3, 4) == 12
+            spy.assert_called_once()
+
+
+@pytest.mark.asyncio
+async def test_multiply_native_large_honors_expired_timeout():
+    """
+    Vérifie qu'une grande multiplication native honore un timeout déjà échu,
+    au lieu de s'exécuter avant la prochaine vérification d'annulation.
+    """
+    context = CalculationContext(threshold=10000, mul_algo="native")
+    large = 1 << CANCELLATION_CHECK_BITS
+    with pytest.raises(TimeoutError):
+        async with asyncio.timeout(0):
+            await multiply(context, large, large)
+
+
+@pytest.mark.asyncio
+async def test_multiply_native_small_does_not_yield():
+    """
+    Vérifie que les petites multiplications natives ne rendent pas la main à la
+    boucle d'événements, pour ne pas pénaliser les algorithmes qui en font beaucoup.
+    """
+    context = CalculationContext(threshold=10000, mul_algo="native")
+    async with asyncio.timeout(0):
+        assert await multiply(context, 6, 7) == 42
+
+
+@pytest.mark.asyncio
+async def test_multiply_cancellation_check_bits_is_configurable():
+    """
+    Vérifie que la taille déclenchant les points d'annulation se règle par le
+    contexte : une petite multiplication honore alors un timeout échu.
+    """
+    context = CalculationContext(threshold=10000, mul_algo="native", cancellation_check_bits=8)
+    with pytest.raises(TimeoutError):
+        async with asyncio.timeout(0):
+            await multiply(context, 1 << 10, 3)
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("chunks", [2, 3, 7])
+async def test_multiply_chunked(chunks):
+    """
+    Vérifie que le découpage en tranches préserve le produit, signes compris,
+    et insère un point d'annulation entre les tranches.
+    """
+    context = CalculationContext(
+        threshold=10000, mul_algo="native", cancellation_check_bits=64, cancellation_chunks=chunks
+    )
+    a, b = 3**200 + 1, 7**150
+    for x, y in ((a, b), (-a, b), (a, -b), (-a, -b), (a, 0)):
+        assert await multiply(context, x, y) == x * y
+    assert await square(context, -a) == a * a
+
+    with patch("pyfibonacci.core.multiplication.asyncio.sleep", wraps=asyncio.sleep) as spy:
+        await multiply(context, a, b)
+    assert spy.call_count == chunks + 1
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("mul_algo", ["native", "parallel"])
+async def test_square(mul_algo):
+    """
+    Vérifie que `square` calcule le carré sur les voies native et parallèle.
+    """
+    with ThreadPoolExecutor(max_workers=1) as executor:
+        context = CalculationContext(threshold=10, executor=executor, mul_algo=mul_algo)
+        for value in (0, -7, 10**100, (1 << CANCELLATION_CHECK_BITS) + 1):
+            assert await square(context, value) == value * value
+
+
+@pytest.mark.asyncio
+async def test_square_parallel_sends_single_operand():
+    """
+    Vérifie que la voie parallèle ne transmet qu'un opérande à l'exécuteur,
+    avec le moteur de multiplication du contexte.
+    """
+    with ThreadPoolExecutor(max_workers=1) as executor:
+        context = CalculationContext(threshold=1000, executor=executor, mul_algo="parallel")
+        with patch.object(executor, "submit", wraps=executor.submit) as spy:
+            assert await square(context, 12) == 144
+            spy.assert_called_once_with(_parallel_square, 12, "auto")
+
+
+@pytest.fixture
+def fake_gmpy2(monkeypatch):
+    """Remplace `gmpy2` par un module simulé qui enregistre les conversions vers `mpz`."""
+    converted = []
+
+    def mpz(value):
+        converted.append(value)
+        return value
+
+    monkeypatch.setattr(multiplication, "gmpy2", MagicMock(mpz=mpz))
+    return converted
+
+
+@pytest.mark.asyncio
+async def test_multiply_uses_gmp_above_threshold(fake_gmpy2):
+    """
+    Vérifie que les produits au-delà de `GMP_THRESHOLD_BITS` sont confiés à GMP
+    lorsqu'il est disponible, et que les petits produits restent natifs.
+    """
+    context = CalculationContext(threshold=10**9, mul_algo="native")
+    large = 1 << GMP_THRESHOLD_BITS
+
+    assert await multiply(context, 6, 7) == 42
+    assert await square(context, 9) == 81
+    assert fake_gmpy2 == []
+
+    assert await multiply(context, large, 3) == large * 3
+    assert await square(context, large) == large * large
+    assert _parallel_multiply(large, 5) == large * 5
+    assert _parallel_square(large) == large * large
+    assert fake_gmpy2 == [large, 3, large, large, 5, large]
+
+
+@pytest.mark.parametrize("gmp, executor, bits, expected", [
+    (False, False, GMP_THRESHOLD_BITS, "native"),
+    (True, False, GMP_THRESHOLD_BITS - 1, "native"),
+    (True, False, GMP_THRESHOLD_BITS, "gmp"),
+    (True, True, GMP_THRESHOLD_BITS, "parallel"),
+])
+def test_multiplication_path(monkeypatch, gmp, executor, bits, expected):
+    """Vérifie la voie indiquée pour une multiplication, selon sa taille et le contexte."""
+    monkeypatch.setattr(multiplication, "gmpy2", MagicMock() if gmp else None)
+    context = CalculationContext(threshold=10, executor=MagicMock() if executor else None)
+    operand = 1 << (bits - 1)
+    assert multiplication.multiplication_path(context, operand, 3) == expected
+
+
+@pytest.mark.asyncio
+async def test_mul_backend_native_bypasses_gmp(fake_gmpy2):
+    """
+    Vérifie que le moteur 'native' écarte GMP, y compris dans les processus
+    de travail, auxquels il est transmis avec les opérandes.
+    """
+    large = 1 << GMP_THRESHOLD_BITS
+    with ThreadPoolExecutor(max_workers=1) as executor:
+        context = CalculationContext(
+            threshold=1000, executor=executor, mul_algo="parallel", mul_backend="native"
+        )
+        assert await multiply(context, large, 3) == large * 3
+        assert await square(context, large) == large * large
+    context = CalculationContext(threshold=10**9, mul_algo="native", mul_backend="native")
+    assert await multiply(context, large, 5) == large * 5
+    assert multiplication.multiplication_path(context, large, 5) == "native"
+    assert fake_gmpy2 == []
+
+    context = dataclasses.replace(context, mul_backend="gmp")
+    assert await multiply(context, large, 5) == large * 5
+    assert fake_gmpy2 == [large, 5]
+
+
+@pytest.mark.parametrize("gmp, backend, expected", [
+    (True, "auto", "gmp"),
+    (False, "auto", "native"),
+    (True, "native", "native"),
+    (True, "gmp", "gmp"),
+])
+def test_resolve_mul_backend(monkeypatch, gmp, backend, expected):
+    """Vérifie le moteur effectif, selon la demande et la présence de `gmpy2`."""
+    monkeypatch.setattr(multiplication, "gmpy2", MagicMock() if gmp else None)
+    assert multiplication.resolve_mul_backend(backend) == expected
+
+
+@pytest.mark.parametrize("backend, message", [("gmp", "gmpy2"), ("fft", "inconnu")])
+def test_resolve_mul_backend_rejects_unavailable(monkeypatch, backend, message):
+    """Vérifie qu'un moteur inconnu, ou GMP sans `gmpy2`, est refusé explicitement."""
+    monkeypatch.setattr(multiplication, "gmpy2", None)
+    with pytest.raises(ValueError, match=message):
+        multiplication.resolve_mul_backend(backend)
+
+
+def test_multiply_without_gmp(monkeypatch):
+    """
+    Vérifie qu'en l'absence de `gmpy2`, les grands produits restent natifs.
+    """
+    monkeypatch.setattr(multiplication, "gmpy2", None)
+    large = 1 << GMP_THRESHOLD_BITS
+    assert _parallel_multiply(large, large + 1) == large * (large + 1)
+
+
+def _raising_multiply(a, b):
+    raise SystemError("assertion interne")
+
+
+@pytest.mark.parametrize("multiply", [
+    _raising_multiply,
+    lambda a, b: (a * b) >> 64,  # Résultat tronqué d'un mot.
+    lambda a, b: -(a * b),  # Signe erroné.
+    lambda a, b: 1,  # Résultat absurde.
+], ids=["exception", "taille", "signe", "absurde"])
+@pytest.mark.parametrize("a, b", [(3**5000, 7**3000), (-(3**5000), 3**5000)])
+def test_checked_product_falls_back_to_native(multiply, a, b, capsys):
+    """
+    Vérifie qu'une multiplication défaillante est remplacée par le produit
+    natif, avec un avertissement.
+    """
+    assert multiplication._checked_product(a, b, multiply) == a * b
+    assert "AVERTISSEMENT: la multiplication GMP a échoué" in capsys.readouterr().err
+
+
+def test_checked_product_zero_operand():
+    """Vérifie qu'un produit par zéro non nul est écarté."""
+    assert multiplication._checked_product(0, 5**100, lambda a, b: 1) == 0
+
+
+def test_checked_product_accepts_valid_result(capsys):
+    """Vérifie qu'un produit cohérent est retourné tel quel, sans avertissement."""
+    a, b = 3**5000, -(7**3000)
+    assert multiplication._checked_product(a, b, lambda x, y: x * y) == a * b
+    assert capsys.readouterr().err == ""
+
+
+@pytest.mark.asyncio
+async def test_multiply_survives_gmp_failure(monkeypatch, capsys):
+    """
+    Vérifie qu'une exception levée par GMP dégrade le calcul en produit
+    natif plutôt que de l'interrompre.
+    """
+    monkeypatch.setattr(
+        multiplication, "gmpy2", MagicMock(mpz=MagicMock(side_effect=SystemError("assertion")))
+    )
+    context = CalculationContext(threshold=10**9, mul_algo="native")
+    large = (1 << GMP_THRESHOLD_BITS) + 1
+
+    assert await multiply(context, large, large - 2) == large * (large - 2)
+    assert await square(context, large) == large * large
+    assert capsys.readouterr().err.count("recalculé nativement") == 2
+
+
+# --- Tests basés sur les propriétés avec Hypothesis ---
+
+import random
+
+from hypothesis import given, strategies as st, settings
+
+# Tailles (en bits) des opérandes générés : les petites tailles, où les cas
+# particuliers abondent, et les abords du seuil de passage à GMP.
+OPERAND_BITS = (0, 1, 2, 31, 32, 33, 63, 64, 65, 1000,
+                GMP_THRESHOLD_BITS - 1, GMP_THRESHOLD_BITS, GMP_THRESHOLD_BITS + 1,
+                3 * GMP_THRESHOLD_BITS)
+
+
+def _operand(spec):
+    """Construit un entier signé d'exactement `bits` bits, tiré selon `seed`."""
+    bits, seed, negative = spec
+    value = (1 << (bits - 1)) | random.Random(seed).getrandbits(bits - 1) if bits else 0
+    return -value if negative else value
+
+
+OPERANDS = st.tuples(
+    st.sampled_from(OPERAND_BITS), st.integers(min_value=0, max_value=2**32), st.booleans()
+).map(_operand)
+
+
+@given(a=OPERANDS, b=OPERANDS)
+@settings(max_examples=100, deadline=None)
+def test_product_matches_native_multiplication(a, b):
+    """Vérifie que `_product` et `_square` concordent avec l'opérateur `*`."""
+    assert multiplication._product(a, b) == a * b
+    assert multiplication._square(a) == a * a
+
+
+@given(a=OPERANDS, b=OPERANDS)
+@settings(max_examples=100, deadline=None)
+def test_gmp_product_matches_native_multiplication(a, b):
+    """
+    Vérifie que les produits confiés à GMP concordent avec l'opérateur `*`,
+    en particulier de part et d'autre de `GMP_THRESHOLD_BITS`.
+    """
+    gmpy2 = pytest.importorskip("gmpy2")
+    with patch.object(multiplication, "gmpy2", gmpy2):
+        assert multiplication._product(a, b) == a * b
+        assert multiplication._square(a) == a * a
+        assert _parallel_multiply(a, b) == a * b
+        assert _parallel_square(a) == a * a
+
+
+@given(a=OPERANDS, b=OPERANDS, chunks=st.integers(min_value=1, max_value=7))
+@settings(max_examples=100, deadline=None)
+@pytest.mark.asyncio
+async def test_chunked_multiply_matches_native_multiplication(a, b, chunks):
+    """
+    Vérifie que le produit découpé en tranches concorde avec l'opérateur `*`,
+    signes et opérandes nuls compris.
+    """
+    context = CalculationContext(
+        threshold=10**9, mul_algo="native", cancellation_check_bits=1, cancellation_chunks=chunks
+    )
+    assert await multiply(context, a, b) == a * b
+    assert await square(context, a) == a * a
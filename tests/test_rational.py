@@ -0,0 +1,79 @@
+"""
+Tests pour les identités rationnelles de la suite de Fibonacci.
+"""
+from fractions import Fraction
+
+import pytest
+from pyfibonacci.core import rational
+from pyfibonacci.core.algorithms import apply_negafibonacci_sign, fib_iterative, fib_matrix
+from pyfibonacci.core.context import CalculationContext
+from pyfibonacci.core.rational import (
+    RATIONAL_IDENTITIES,
+    check_golden_convergent,
+    check_millin_series,
+    check_reciprocal_products,
+    fib_fraction,
+    reciprocal_sum,
+)
+
+
+@pytest.fixture
+def context():
+    """Fournit un contexte de calcul de base pour les tests."""
+    return CalculationContext(threshold=10000)
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("algorithm", [rational.fib_fast_doubling, fib_matrix, fib_iterative])
+@pytest.mark.parametrize("n", [0, 1, 10, -7, -8, 500])
+async def test_fib_fraction_wraps_integer_algorithms(context, algorithm, n):
+    """Vérifie que F(n) est retourné en rationnel exact, quel que soit l'algorithme."""
+    value = await fib_fraction(context, n, algorithm)
+    assert isinstance(value, Fraction)
+    assert value == apply_negafibonacci_sign(n, fib_iterative(abs(n)))
+    assert value.denominator == 1
+
+
+@pytest.mark.parametrize("n, expected", [(0, Fraction(0)), (1, Fraction(1)), (5, Fraction(91, 30))])
+def test_reciprocal_sum(n, expected):
+    """Vérifie les premières sommes partielles des inverses."""
+    assert reciprocal_sum(n) == expected
+
+
+def test_reciprocal_sum_converges():
+    """Vérifie la convergence vers la constante de Fibonacci inverse, par valeurs inférieures."""
+    constant = Fraction("3.359885666243177553172011302918927179688905133731")
+    partial = reciprocal_sum(60)
+    assert 0 < constant - partial < Fraction(1, 10**11)
+    assert reciprocal_sum(59) < partial
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("name", list(RATIONAL_IDENTITIES))
+@pytest.mark.parametrize("k", [1, 2, 3, 8, 12])
+async def test_rational_identities_hold(context, name, k):
+    """Vérifie chaque identité rationnelle sur plusieurs tailles."""
+    assert await RATIONAL_IDENTITIES[name](context, k)
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("check", [check_millin_series, check_reciprocal_products, check_golden_convergent])
+async def test_rational_identities_detect_wrong_terms(context, check, monkeypatch):
+    """Vérifie qu'un terme erroné, obtenu par 'Fast Doubling', met l'identité en défaut."""
+    async def corrupted(context, n):
+        return Fraction(fib_iterative(n) + (1 if n > 4 else 0))
+
+    monkeypatch.setattr(rational, "fib_fraction", corrupted)
+    assert not await check(context, 6)
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("check, invalid", [
+    (check_millin_series, 0),
+    (check_reciprocal_products, -1),
+    (check_golden_convergent, 0),
+])
+async def test_rational_identities_reject_invalid_parameter(context, check, invalid):
+    """Vérifie le refus des paramètres hors du domaine de chaque identité."""
+    with pytest.raises(ValueError):
+        await check(context, invalid)
@@ -0,0 +1,65 @@
+"""
+Tests pour le cache des résultats de référence.
+"""
+import json
+
+import pytest
+from pyfibonacci.core.algorithms import fib_iterative
+from pyfibonacci.reference import Reference, default_reference_dir, load_reference, save_reference
+
+
+def test_default_reference_dir(monkeypatch, tmp_path):
+    """Vérifie que le cache suit XDG_CACHE_HOME, et ~/.cache à défaut."""
+    monkeypatch.setenv("XDG_CACHE_HOME", str(tmp_path))
+    assert default_reference_dir() == tmp_path / "pyfibonacci" / "references"
+    monkeypatch.delenv("XDG_CACHE_HOME")
+    assert default_reference_dir().parts[-3:] == (".cache", "pyfibonacci", "references")
+
+
+@pytest.mark.parametrize("n", [0, 1, 1000, -7])
+def test_save_and_load_reference(tmp_path, n):
+    """Vérifie qu'une référence relue est identique à celle enregistrée."""
+    value = fib_iterative(abs(n)) * (-1 if n < 0 and n % 2 == 0 else 1)
+    path = save_reference(n, value, "fast", tmp_path / "cache")
+
+    assert path == tmp_path / "cache" / f"F{n}.bin"
+    reference = load_reference(n, tmp_path / "cache")
+    assert isinstance(reference, Reference)
+    assert (reference.n, reference.value, reference.algorithm) == (n, value, "fast")
+    assert len(reference.hash) == 64
+
+
+def test_save_reference_replaces_existing(tmp_path):
+    """Vérifie qu'un nouvel enregistrement remplace la référence précédente."""
+    save_reference(10, 54, "faux", tmp_path)
+    save_reference(10, 55, "fast", tmp_path)
+
+    assert load_reference(10, tmp_path).value == 55
+    assert sorted(p.name for p in tmp_path.iterdir()) == ["F10.bin", "F10.json"]
+
+
+def test_load_reference_missing(tmp_path):
+    """Vérifie qu'un indice absent du cache donne `None`."""
+    assert load_reference(10, tmp_path) is None
+    assert load_reference(10, tmp_path / "absent") is None
+
+
+def test_load_reference_rejects_corruption(tmp_path):
+    """Vérifie qu'une valeur qui ne correspond plus à son empreinte est refusée."""
+    save_reference(10, 55, "fast", tmp_path)
+    save_reference(11, 89, "fast", tmp_path)
+    (tmp_path / "F10.bin").write_bytes((tmp_path / "F11.bin").read_bytes())
+
+    with pytest.raises(ValueError, match="corrompue"):
+        load_reference(10, tmp_path)
+
+
+def test_load_reference_rejects_malformed_metadata(tmp_path):
+    """Vérifie que des métadonnées illisibles sont signalées."""
+    save_reference(10, 55, "fast", tmp_path)
+    metadata = json.loads((tmp_path / "F10.json").read_text(encoding="utf-8"))
+    del metadata["hash"]
+    (tmp_path / "F10.json").write_text(json.dumps(metadata), encoding="utf-8")
+
+    with pytest.raises(ValueError, match="malformées"):
+        load_reference(10, tmp_path)
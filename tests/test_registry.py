@@ -0,0 +1,117 @@
+"""
+Tests pour le registre des algorithmes et son API d'extension.
+"""
+
+import sys
+from unittest.mock import MagicMock, patch
+
+import pytest
+from pyfibonacci.app import _execute_algorithm
+from pyfibonacci.cli.args import parse_args
+from pyfibonacci.core.context import CalculationContext
+from pyfibonacci.registry import (
+    ALGORITHM_REGISTRY,
+    PLUGIN_ENTRY_POINT_GROUP,
+    describe_algorithms,
+    get_algorithms,
+    load_plugins,
+    register_algorithm,
+    select_best_algorithm,
+)
+
+
+async def fib_custom(context, n):
+    """Algorithme d'extension de test : F(n) par itération."""
+    a, b = 0, 1
+    for _ in range(n):
+        a, b = b, a + b
+    return a
+
+
+@pytest.fixture
+def registry():
+    """Restaure le registre après chaque test."""
+    with patch.dict(ALGORITHM_REGISTRY):
+        yield ALGORITHM_REGISTRY
+
+
+@pytest.mark.asyncio
+async def test_register_algorithm_is_usable(registry):
+    """
+    Vérifie qu'un algorithme enregistré est accepté par `--algo` et exécuté,
+    signe des indices négatifs compris.
+    """
+    register_algorithm("custom", fib_custom)
+
+    with patch.object(sys, "argv", ["pyfibonacci", "-n", "10", "--algo", "fast,custom"]):
+        assert parse_args().algo == "fast,custom"
+    result = await _execute_algorithm(CalculationContext(threshold=1000), -10, "custom", 1)
+    assert result.value == -55
+
+
+@pytest.mark.parametrize("name", ["", "all", "best", "fast,custom", "my algo", "fast"])
+def test_register_algorithm_rejects_invalid_names(registry, name):
+    """Vérifie le refus des noms vides, réservés, mal formés ou déjà pris."""
+    with pytest.raises(ValueError):
+        register_algorithm(name, fib_custom)
+
+
+def test_register_algorithm_rejects_non_callable(registry):
+    """Vérifie qu'un algorithme qui n'est pas une fonction est refusé."""
+    with pytest.raises(TypeError):
+        register_algorithm("custom", None)
+    assert "custom" not in registry
+
+
+def test_get_algorithms_is_read_only(registry):
+    """Vérifie que l'accesseur reflète le registre sans permettre de le modifier."""
+    algorithms = get_algorithms()
+    register_algorithm("custom", fib_custom)
+
+    assert algorithms["custom"] is fib_custom
+    with pytest.raises(TypeError):
+        algorithms["other"] = fib_custom
+
+
+def test_load_plugins(registry):
+    """Vérifie que les points d'entrée du groupe dédié sont enregistrés."""
+    entry_point = MagicMock()
+    entry_point.name = "custom"
+    entry_point.load.return_value = fib_custom
+
+    with patch("pyfibonacci.registry.entry_points", return_value=[entry_point]) as spy:
+        assert load_plugins() == ["custom"]
+
+    spy.assert_called_once_with(group=PLUGIN_ENTRY_POINT_GROUP)
+    assert registry["custom"] is fib_custom
+
+
+def test_select_best_algorithm():
+    """Vérifie que l'algorithme retenu est un algorithme de Fibonacci enregistré."""
+    name = select_best_algorithm()
+    assert name in ALGORITHM_REGISTRY and name != "lucas"
+
+
+def test_describe_algorithms(registry):
+    """
+    Vérifie la description des algorithmes : complexité documentée pour les
+    algorithmes intégrés, résumé tiré de la docstring pour les extensions.
+    """
+    def fib_sync(context, n):
+        """Extension synchrone de test.
+
+        Détails ignorés par le résumé.
+        """
+        return 0
+
+    register_algorithm("custom", fib_custom)
+    register_algorithm("sync", fib_sync)
+    infos = {info.name: info for info in describe_algorithms()}
+
+    assert list(infos) == list(registry)
+    assert infos["fast"].complexity.startswith("O(log n)") and infos["fast"].parallel
+    assert not infos["iterative"].parallel
+    assert infos["custom"].title == "fib_custom" and infos["custom"].complexity is None
+    assert infos["custom"].summary == "Algorithme d'extension de test : F(n) par itération."
+    assert infos["custom"].parallel and not infos["sync"].parallel
+    assert infos["sync"].summary == "Extension synchrone de test."
@@ -0,0 +1,98 @@
+"""
+Tests pour le module `pyfibonacci.core.result`.
+"""
+
+import asyncio
+
+import pytest
+from pyfibonacci.core.algorithms import fib_iterative, fib_fast_doubling
+from pyfibonacci.core.context import CalculationContext
+from pyfibonacci.core.errors import CalculationError, ErrorCategory
+from pyfibonacci.core.result import FibonacciResult, calculate_with_stats
+
+
+def test_fibonacci_result_metadata():
+    """
+    Vérifie les métadonnées dérivées de la valeur.
+    """
+    result = FibonacciResult(value=12586269025, n=50, algorithm="fast", duration=0.5)
+    assert result.bit_length == 34
+    assert result.digits == 11
+
+
+def test_fibonacci_result_negative_value_digits():
+    """
+    Vérifie que le signe n'est pas compté parmi les chiffres.
+    """
+    assert FibonacciResult(value=-55, n=-10, algorithm="fast", duration=0.0).digits == 2
+
+
+@pytest.mark.asyncio
+async def test_calculate_with_stats_async_algorithm():
+    """
+    Vérifie le calcul et les métadonnées avec un algorithme asynchrone.
+    """
+    context = CalculationContext(threshold=10000)
+    result = await calculate_with_stats(context, 100, fib_fast_doubling)
+
+    assert result.value == 354224848179261915075
+    assert result.n == 100
+    assert result.algorithm == "fib_fast_doubling"
+    assert result.duration >= 0
+
+
+@pytest.mark.asyncio
+async def test_calculate_with_stats_sync_algorithm_with_name():
+    """
+    Vérifie le calcul avec un algorithme synchrone et un nom explicite.
+    """
+    context = CalculationContext(threshold=10000)
+    result = await calculate_with_stats(context, 10, fib_iterative, name="iterative")
+
+    assert result.value == 55
+    assert result.algorithm == "iterative"
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize(
+    "raised, category",
+    [
+        (ValueError("boom"), ErrorCategory.INTERNAL),
+        (TimeoutError(), ErrorCategory.TIMEOUT),
+        (asyncio.CancelledError(), ErrorCategory.CANCELED),
+        (MemoryError(), ErrorCategory.MEMORY),
+    ],
+)
+async def test_calculate_with_stats_structured_error(raised, category):
+    """
+    Vérifie qu'un échec est signalé par une `CalculationError` catégorisée,
+    l'exception d'origine restant accessible par chaînage.
+    """
+    async def failing(context, n):
+        raise raised
+
+    with pytest.raises(CalculationError) as excinfo:
+        await calculate_with_stats(CalculationContext(threshold=10000), 7, failing, name="failing")
+
+    assert excinfo.value.algorithm == "failing"
+    assert excinfo.value.n == 7
+    assert excinfo.value.category is category
+    assert excinfo.value.__cause__ is raised
+
+
+@pytest.mark.asyncio
+async def test_calculate_with_stats_propagates_caller_cancellation():
+    """
+    Vérifie que l'annulation de l'appelant n'est pas convertie en erreur.
+    """
+    started = asyncio.Event()
+
+    async def slow(context, n):
+        started.set()
+        await asyncio.sleep(10)
+
+    task = asyncio.create_task(calculate_with_stats(CalculationContext(threshold=10000), 7, slow))
+    await started.wait()
+    task.cancel()
+    with pytest.raises(asyncio.CancelledError):
+        await task
@@ -0,0 +1,325 @@
+"""
+Tests pour le mode serveur HTTP.
+"""
+import asyncio
+import json
+import os
+import signal
+
+import pytest
+from prometheus_client import REGISTRY
+from pyfibonacci.cli.output import CalculationResult
+from pyfibonacci.server import (
+    CachingExecutor, ServerState, handle_fib_request, parse_listen_address, run_server, start_server
+)
+
+ALGORITHMS = ("fast", "iterative")
+
+
+async def fake_execute(n, algo_name):
+    """Calcul simulé : F(10) = 55, quel que soit l'algorithme."""
+    return CalculationResult(algo_name, 55, 0.002)
+
+
+async def http_get(server, target):
+    """Envoie une requête GET au serveur et retourne (statut, document JSON)."""
+    host, port = server.sockets[0].getsockname()[:2]
+    reader, writer = await asyncio.open_connection(host, port)
+    writer.write(f"GET {target} HTTP/1.1\r\nHost: test\r\n\r\n".encode())
+    await writer.drain()
+    response = await reader.read()
+    writer.close()
+    head, _, body = response.partition(b"\r\n\r\n")
+    status = int(head.split(b" ")[1])
+    return status, json.loads(body)
+
+
+@pytest.mark.parametrize(
+    "address, expected",
+    [(":8080", (None, 8080)), ("127.0.0.1:9000", ("127.0.0.1", 9000)), ("[::1]:80", ("::1", 80))],
+)
+def test_parse_listen_address(address, expected):
+    """Vérifie l'analyse des adresses d'écoute."""
+    assert parse_listen_address(address) == expected
+
+
+@pytest.mark.parametrize("address", ["8080", ":http", ":70000"])
+def test_parse_listen_address_invalid(address):
+    """Vérifie que les adresses malformées sont rejetées."""
+    with pytest.raises(ValueError):
+        parse_listen_address(address)
+
+
+@pytest.mark.asyncio
+async def test_handle_fib_request_success():
+    """Vérifie le document retourné pour un calcul réussi."""
+    status, body = await handle_fib_request({"n": ["10"], "algo": ["iterative"]}, fake_execute, ALGORITHMS, 100)
+    assert status == 200
+    assert body == {"n": 10, "algorithm": "iterative", "value": "55", "digits": 2, "duration_ms": 2.0}
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize(
+    "query",
+    [{}, {"n": ["abc"]}, {"n": ["101"]}, {"n": ["-101"]}, {"n": ["10"], "algo": ["unknown"]}],
+)
+async def test_handle_fib_request_bad_request(query):
+    """Vérifie qu'un paramètre invalide ou un n au-delà de la limite retourne 400."""
+    status, body = await handle_fib_request(query, fake_execute, ALGORITHMS, 100)
+    assert status == 400
+    assert "error" in body
+
+
+@pytest.mark.asyncio
+async def test_handle_fib_request_timeout():
+    """Vérifie qu'un dépassement du timeout retourne 504."""
+
+    async def timed_out(n, algo_name):
+        return CalculationResult(algo_name, None, 1.0, error="timeout (1.0s)", timed_out=True)
+
+    status, _ = await handle_fib_request({"n": ["10"]}, timed_out, ALGORITHMS, 100)
+    assert status == 504
+
+
+@pytest.mark.asyncio
+async def test_server_end_to_end():
+    """Vérifie les réponses du serveur pour une requête valide et des routes invalides."""
+    server = await start_server("127.0.0.1:0", fake_execute, ALGORITHMS, max_n=100)
+    async with server:
+        status, body = await http_get(server, "/fib?n=10&algo=fast")
+        assert status == 200
+        assert body["value"] == "55"
+
+        status, _ = await http_get(server, "/fib?n=1000")
+        assert status == 400
+
+        status, _ = await http_get(server, "/other")
+        assert status == 404
+
+
+@pytest.mark.asyncio
+async def test_server_cancels_calculation_on_disconnect():
+    """Vérifie qu'une déconnexion du client annule le calcul en cours."""
+    started = asyncio.Event()
+    cancelled = asyncio.Event()
+
+    async def slow_execute(n, algo_name):
+        started.set()
+        try:
+            await asyncio.sleep(10)
+        except asyncio.CancelledError:
+            cancelled.set()
+            raise
+
+    server = await start_server("127.0.0.1:0", slow_execute, ALGORITHMS, max_n=100)
+    async with server:
+        host, port = server.sockets[0].getsockname()[:2]
+        _, writer = await asyncio.open_connection(host, port)
+        writer.write(b"GET /fib?n=10 HTTP/1.1\r\n\r\n")
+        await writer.drain()
+        await asyncio.wait_for(started.wait(), timeout=1)
+        writer.close()
+        await asyncio.wait_for(cancelled.wait(), timeout=1)
+
+
+@pytest.mark.asyncio
+async def test_server_metrics_endpoint():
+    """Vérifie que /metrics publie les métriques au format Prometheus."""
+    server = await start_server("127.0.0.1:0", fake_execute, ALGORITHMS, max_n=100)
+    async with server:
+        host, port = server.sockets[0].getsockname()[:2]
+        reader, writer = await asyncio.open_connection(host, port)
+        writer.write(b"GET /metrics HTTP/1.1\r\n\r\n")
+        await writer.drain()
+        response = await reader.read()
+        writer.close()
+    head, _, body = response.partition(b"\r\n\r\n")
+    assert head.startswith(b"HTTP/1.1 200")
+    assert b"pyfibonacci_largest_n" in body
+
+
+@pytest.mark.asyncio
+async def test_server_probes_and_draining():
+    """
+    Vérifie les sondes de vivacité et de disponibilité, et qu'un serveur en
+    cours d'arrêt refuse les nouveaux calculs tout en laissant aboutir ceux
+    en cours.
+    """
+    release = asyncio.Event()
+
+    async def slow_execute(n, algo_name):
+        await release.wait()
+        return CalculationResult(algo_name, 55, 0.002)
+
+    state = ServerState()
+    server = await start_server("127.0.0.1:0", slow_execute, ALGORITHMS, max_n=100, state=state)
+    async with server:
+        assert await http_get(server, "/healthz") == (200, {"status": "ok"})
+        assert await http_get(server, "/readyz") == (200, {"status": "ready", "in_flight": 0})
+
+        pending = asyncio.create_task(http_get(server, "/fib?n=10"))
+        while state.in_flight == 0:
+            await asyncio.sleep(0.01)
+        draining = asyncio.create_task(state.drain(grace=5))
+        await asyncio.sleep(0)
+
+        assert await http_get(server, "/readyz") == (503, {"status": "draining", "in_flight": 1})
+        status, _ = await http_get(server, "/fib?n=10")
+        assert status == 503
+        assert await http_get(server, "/healthz") == (200, {"status": "ok"})
+
+        release.set()
+        assert await draining == 0
+        status, body = await pending
+        assert (status, body["value"]) == (200, "55")
+
+
+@pytest.mark.asyncio
+@pytest.mark.parametrize("grace", [5, 0.05])
+async def test_server_drain_waits_for_connections(grace):
+    """
+    Vérifie qu'au retour de `drain` toutes les connexions ont écrit leur
+    réponse et se sont fermées, qu'un calcul ait abouti ou ait été annulé :
+    l'arrêt de la boucle d'événements n'a plus de traitement à annuler.
+    """
+    release = asyncio.Event()
+
+    async def slow_execute(n, algo_name):
+        await release.wait()
+        return CalculationResult(algo_name, 55, 0.002)
+
+    state = ServerState()
+    server = await start_server("127.0.0.1:0", slow_execute, ALGORITHMS, max_n=100, state=state)
+    async with server:
+        pending = asyncio.create_task(http_get(server, "/fib?n=10"))
+        while state.in_flight == 0:
+            await asyncio.sleep(0.01)
+        draining = asyncio.create_task(state.drain(grace=grace))
+        if grace > 1:
+            release.set()
+        await draining
+        handlers = [t for t in asyncio.all_tasks() if t.get_coro().__name__ == "_on_connection"]
+        assert handlers == []
+        status, _ = await pending
+        assert status == (200 if grace > 1 else 503)
+
+
+@pytest.mark.asyncio
+async def test_server_drain_cancels_after_grace():
+    """Vérifie qu'un calcul qui dépasse le délai de grâce est annulé et reçoit 503."""
+    async def endless_execute(n, algo_name):
+        await asyncio.sleep(10)
+
+    state = ServerState()
+    server = await start_server("127.0.0.1:0", endless_execute, ALGORITHMS, max_n=100, state=state)
+    async with server:
+        pending = asyncio.create_task(http_get(server, "/fib?n=10"))
+        while state.in_flight == 0:
+            await asyncio.sleep(0.01)
+        assert await state.drain(grace=0.05) == 1
+        status, body = await pending
+        assert status == 503
+        assert "arrêt" in body["error"]
+
+
+@pytest.mark.asyncio
+@pytest.mark.skipif(not hasattr(signal, "SIGTERM") or os.name != "posix", reason="SIGTERM requis")
+async def test_run_server_stops_on_sigterm(capsys):
+    """Vérifie que SIGTERM arrête le serveur en douceur."""
+    serving = asyncio.create_task(run_server("127.0.0.1:0", fake_execute, ALGORITHMS, grace=1))
+    while "Serveur à l'écoute" not in capsys.readouterr().out:
+        await asyncio.sleep(0.01)
+
+    os.kill(os.getpid(), signal.SIGTERM)
+    await asyncio.wait_for(serving, timeout=2)
+
+    assert "Arrêt du serveur : 0 calcul(s) en cours" in capsys.readouterr().out
+
+
+def cache_sample(name, **labels):
+    """Retourne la valeur courante d'une métrique du cache (0 si absente)."""
+    return REGISTRY.get_sample_value(name, labels) or 0
+
+
+@pytest.mark.asyncio
+async def test_caching_executor_hits_and_evictions():
+    """Vérifie que les résultats sont servis depuis le cache et évincés du moins récent."""
+    calls = []
+
+    async def counting_execute(n, algo_name):
+        calls.append((n, algo_name))
+        return CalculationResult(algo_name, n * 2, 0.5)
+
+    hits = cache_sample("pyfibonacci_cache_lookups_total", result="hit")
+    misses = cache_sample("pyfibonacci_cache_lookups_total", result="miss")
+    evictions = cache_sample("pyfibonacci_cache_evictions_total")
+
+    execute = CachingExecutor(counting_execute, max_entries=2)
+    await execute(1, "fast")
+    await execute(2, "fast")
+    assert (await execute(1, "fast")).value == 2  # (1, fast) devient le plus récent.
+    await execute(3, "fast")  # Évince (2, fast).
+    await execute(2, "fast")
+
+    assert calls == [(1, "fast"), (2, "fast"), (3, "fast"), (2, "fast")]
+    assert len(execute) == 2
+    assert cache_sample("pyfibonacci_cache_lookups_total", result="hit") - hits == 1
+    assert cache_sample("pyfibonacci_cache_lookups_total", result="miss") - misses == 4
+    assert cache_sample("pyfibonacci_cache_evictions_total") - evictions == 2
+
+
+@pytest.mark.asyncio
+async def test_caching_executor_does_not_cache_failures():
+    """Vérifie qu'un échec n'est pas conservé et que le calcul est retenté."""
+    calls = []
+
+    async def failing_execute(n, algo_name):
+        calls.append(n)
+        return CalculationResult(algo_name, None, 1.0, error="timeout (1.0s)", timed_out=True)
+
+    execute = CachingExecutor(failing_execute, max_entries=4)
+    await execute(10, "fast")
+    await execute(10, "fast")
+    assert calls == [10, 10]
+    assert len(execute) == 0
+
+
+@pytest.mark.asyncio
+async def test_caching_executor_clear():
+    """Vérifie que le cache vidé recalcule les résultats qu'il servait."""
+    calls = []
+
+    async def counting_execute(n, algo_name):
+        calls.append(n)
+        return CalculationResult(algo_name, n * 2, 0.5)
+
+    execute = CachingExecutor(counting_execute, max_entries=4)
+    await execute(1, "fast")
+    await execute(2, "fast")
+    assert execute.clear() == 2
+    assert len(execute) == 0
+    await execute(1, "fast")
+    assert calls == [1, 2, 1]
+
+
+@pytest.mark.asyncio
+async def test_caching_executor_drains_when_idle():
+    """Vérifie que le cache est vidé après une période sans consultation."""
+    execute = CachingExecutor(fake_execute, max_entries=4)
+    drainer = asyncio.create_task(execute.drain_when_idle(0.1))
+    try:
+        await execute(10, "fast")
+        await asyncio.sleep(0.05)
+        await execute(11, "fast")
+        await asyncio.sleep(0.05)
+        assert len(execute) == 2  # Consulté récemment : conservé.
+        await asyncio.sleep(0.15)
+        assert len(execute) == 0
+    finally:
+        drainer.cancel()
+
+
+def test_caching_executor_invalid_size():
+    """Vérifie qu'une taille de cache nulle ou négative est refusée."""
+    with pytest.raises(ValueError):
+        CachingExecutor(fake_execute, max_entries=0)
@@ -0,0 +1,60 @@
+"""
+Tests pour l'instantané de progression déclenché par SIGUSR1.
+"""
+import io
+import os
+import signal
+
+import pytest
+from pyfibonacci.core.context import CalculationProgress
+from pyfibonacci.core.snapshot import ProgressSnapshot
+
+
+def test_snapshot_without_calculation():
+    """Vérifie l'instantané lorsqu'aucun calcul n'est en cours."""
+    assert ProgressSnapshot().describe().startswith("Progression : aucun calcul en cours, écoulé : ")
+
+
+def test_snapshot_single_calculation():
+    """Vérifie que l'avancement d'un calcul inscrit est rapporté, puis oublié à sa fin."""
+    snapshot = ProgressSnapshot()
+    with snapshot.track("fast", CalculationProgress(completed=3, total=8)):
+        assert snapshot.describe().startswith("Progression (fast) : 37.5 %, écoulé : ")
+    assert "aucun calcul en cours" in snapshot.describe()
+
+
+def test_snapshot_aggregates_concurrent_calculations():
+    """
+    Vérifie que l'avancement moyen ne porte que sur les calculs qui
+    rapportent leur progression.
+    """
+    snapshot = ProgressSnapshot()
+    with (
+        snapshot.track("fast", CalculationProgress(completed=9, total=10)),
+        snapshot.track("matrix", CalculationProgress(completed=5, total=10)),
+        snapshot.track("iterative", CalculationProgress()),
+    ):
+        report = snapshot.describe()
+    assert report.startswith(
+        "Progression : 70.0 % (fast : 90.0 %, matrix : 50.0 %, iterative : inconnue), écoulé : "
+    )
+
+
+@pytest.mark.skipif(not hasattr(signal, "SIGUSR1"), reason="SIGUSR1 indisponible sur cette plate-forme")
+def test_snapshot_reports_on_sigusr1():
+    """
+    Vérifie que SIGUSR1 écrit l'instantané sans interrompre le processus, et
+    que le gestionnaire précédent est rétabli.
+    """
+    previous = signal.getsignal(signal.SIGUSR1)
+    stream = io.StringIO()
+    snapshot = ProgressSnapshot(stream)
+    assert snapshot.install()
+    try:
+        with snapshot.track("fast", CalculationProgress(completed=1, total=4)):
+            os.kill(os.getpid(), signal.SIGUSR1)
+    finally:
+        snapshot.uninstall()
+
+    assert stream.getvalue().startswith("Progression (fast) : 25.0 %, écoulé : ")
+    assert signal.getsignal(signal.SIGUSR1) == previous
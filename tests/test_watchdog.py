@@ -0,0 +1,74 @@
+"""
+Tests pour le chien de garde des calculs figés.
+"""
+import asyncio
+
+import pytest
+from pyfibonacci.core.watchdog import Watchdog
+
+
+@pytest.fixture
+def report_stream(tmp_path):
+    """Fixture pour un flux de rapport possédant un descripteur de fichier."""
+    with open(tmp_path / "watchdog.log", "w+", encoding="utf-8") as stream:
+        yield stream
+
+
+def read_report(stream):
+    """Relit le rapport écrit par le chien de garde."""
+    stream.flush()
+    stream.seek(0)
+    return stream.read()
+
+
+@pytest.mark.asyncio
+async def test_watchdog_reports_stalled_calculation(report_stream):
+    """
+    Vérifie qu'un calcul sans signe de vie déclenche l'écriture des piles des
+    threads et des tâches asyncio.
+    """
+    watchdog = Watchdog(0.1, stream=report_stream)
+
+    async def stalled():
+        with watchdog.watch():
+            await asyncio.sleep(0.4)
+
+    await asyncio.create_task(stalled(), name="calcul-fige")
+    watchdog.close()
+
+    report = read_report(report_stream)
+    assert "WATCHDOG: aucun signe de vie" in report
+    assert "Thread" in report
+    assert "Tâche calcul-fige" in report
+    assert "stalled" in report
+
+
+@pytest.mark.asyncio
+async def test_watchdog_silent_while_fed(report_stream):
+    """
+    Vérifie que des signes de vie réguliers empêchent tout rapport.
+    """
+    watchdog = Watchdog(0.2, stream=report_stream)
+
+    with watchdog.watch():
+        for _ in range(20):
+            await asyncio.sleep(0.02)
+            watchdog.feed()
+    watchdog.close()
+
+    assert read_report(report_stream) == ""
+
+
+@pytest.mark.asyncio
+async def test_watchdog_disarmed_outside_calculations(report_stream):
+    """
+    Vérifie que le chien de garde ne surveille que les calculs en cours.
+    """
+    watchdog = Watchdog(0.1, stream=report_stream)
+
+    with watchdog.watch():
+        pass
+    await asyncio.sleep(0.3)
+    watchdog.close()
+
+    assert read_report(report_stream) == ""